@@ -25,7 +25,7 @@ func NewAddPrivilegeCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(roleID) {
-				id, err := utils.ResolveID(cfg, "role", roleID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "role", roleID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -38,7 +38,7 @@ func NewAddPrivilegeCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "addPrivilege", []string{roleID, privilegeID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "addPrivilege", []string{roleID, privilegeID})
 		},
 	}
 
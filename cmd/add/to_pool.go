@@ -25,7 +25,7 @@ func NewAddToPoolCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(poolID) {
-				id, err := utils.ResolveID(cfg, "pool", poolID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "pool", poolID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -34,7 +34,7 @@ func NewAddToPoolCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -42,7 +42,7 @@ func NewAddToPoolCmd() *cobra.Command {
 				projectID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "addToPool", []string{poolID, projectID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "addToPool", []string{poolID, projectID})
 		},
 	}
 
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/tidwall/gjson"
+)
+
+func NewAPICmd() *cobra.Command {
+	var (
+		fields      []string
+		typedFields []string
+		input       string
+		jq          string
+		clusterName string
+	)
+	var cmd = &cobra.Command{
+		Use:   "api <METHOD> <path>",
+		Short: "Send an authenticated request to any controller endpoint",
+		Long: `Send a request to any path of the controller API with the token stored by
+auth login, for endpoints gns3util has no command for yet.
+
+The path is relative to the API prefix (/v3 may be given and is dropped).
+Fields given with -f are sent as strings, -F converts true, false, null and
+numbers to JSON and reads @file (or @- for stdin) as the value. Fields become
+query parameters for GET and DELETE and a JSON object body otherwise;
+--input sends a file or stdin as the body instead.
+
+With --cluster the request goes to every node of the cluster and the arrays
+the nodes return are merged into one array. --jq selects parts of the
+response with a gjson path (https://github.com/tidwall/gjson/blob/master/SYNTAX.md).
+
+Failed requests print the response body and exit non-zero.`,
+		Example: `  # List the projects
+  gns3util -s https://controller:3080 api GET /projects
+
+  # Only the project names
+  gns3util -s https://controller:3080 api GET /projects --jq '#.name'
+
+  # Create a user from fields
+  gns3util -s https://controller:3080 api POST /access/users -f username=alice -f password=Secret123 -F is_active=true
+
+  # Send a body from a file or stdin
+  gns3util -s https://controller:3080 api PUT /projects/<id> --input update.json
+  echo '{"name":"lab"}' | gns3util -s https://controller:3080 api POST /projects --input -
+
+  # All users on every node of a cluster as one list
+  gns3util api GET /access/users --cluster lab --jq '#.username'`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+
+			method := api.HTTPMethod(strings.ToUpper(args[0]))
+			switch method {
+			case api.GET, api.POST, api.PUT, api.DELETE, api.PATCH:
+			default:
+				return fmt.Errorf("unsupported method %q: use GET, POST, PUT, PATCH or DELETE", args[0])
+			}
+			if input != "" && method == api.GET {
+				return fmt.Errorf("--input cannot be used with GET")
+			}
+
+			path, params, err := utils.ParseAPIPath(args[1])
+			if err != nil {
+				return err
+			}
+			req := utils.RawRequest{Method: method, Path: path, Params: params}
+
+			values, err := parseAPIFields(fields, typedFields)
+			if err != nil {
+				return err
+			}
+			if method == api.GET || method == api.DELETE || input != "" {
+				for k, v := range values {
+					if s, ok := v.(string); ok {
+						req.Params[k] = s
+					} else {
+						b, _ := json.Marshal(v)
+						req.Params[k] = string(b)
+					}
+				}
+			} else if len(values) > 0 {
+				b, err := json.Marshal(values)
+				if err != nil {
+					return fmt.Errorf("failed to encode fields: %w", err)
+				}
+				req.Body = string(b)
+			}
+			if input != "" {
+				b, err := readAPIInput(input)
+				if err != nil {
+					return err
+				}
+				req.Body = string(b)
+			}
+
+			servers := []string{cfg.Server}
+			if clusterName != "" {
+				if servers, err = clusterServers(clusterName); err != nil {
+					return err
+				}
+			}
+
+			if len(servers) == 1 {
+				body, err := sendAPIRequest(cmd.Context(), cfg, req)
+				if err != nil {
+					if apiErr, ok := api.AsAPIError(err); ok {
+						printAPIResponse(cfg, apiErr.Body, "")
+					}
+					return err
+				}
+				printAPIResponse(cfg, body, jq)
+				return nil
+			}
+
+			var bodies [][]byte
+			failed := 0
+			for _, s := range servers {
+				nodeCfg := cfg
+				nodeCfg.Server = s
+				body, err := sendAPIRequest(cmd.Context(), nodeCfg, req)
+				if errors.Is(err, context.Canceled) {
+					return fmt.Errorf("%s %s interrupted after %d/%d nodes: %w", req.Method, req.Path, len(bodies)+failed, len(servers), err)
+				}
+				if err != nil {
+					failed++
+					fmt.Printf("%v %s: %v\n", messageUtils.ErrorMsg("API error on"), s, err)
+					continue
+				}
+				bodies = append(bodies, body)
+			}
+			if len(bodies) > 0 {
+				printAPIResponse(cfg, utils.MergeArrays(bodies), jq)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%s %s failed on %d/%d nodes of cluster %s", req.Method, req.Path, failed, len(servers), clusterName)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&fields, "field", "f", nil, "Add a string field key=value (repeatable)")
+	cmd.Flags().StringArrayVarP(&typedFields, "typed-field", "F", nil, "Add a field key=value with a JSON typed value or @file contents (repeatable)")
+	cmd.Flags().StringVar(&input, "input", "", "Send the body from this file, - for stdin")
+	cmd.Flags().StringVar(&jq, "jq", "", "Select values from the response with a gjson path, e.g. '#.name'")
+	cmd.Flags().StringVarP(&clusterName, "cluster", "c", "", "Send the request to every node of this cluster")
+	return cmd
+}
+
+func parseAPIFields(fields, typedFields []string) (map[string]any, error) {
+	values := make(map[string]any)
+	for _, f := range fields {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid field %q: expected key=value", f)
+		}
+		values[k] = v
+	}
+	for _, f := range typedFields {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid field %q: expected key=value", f)
+		}
+		switch {
+		case strings.HasPrefix(v, "@"):
+			b, err := readAPIInput(v[1:])
+			if err != nil {
+				return nil, err
+			}
+			values[k] = string(b)
+		case v == "true" || v == "false":
+			values[k] = v == "true"
+		case v == "null":
+			values[k] = nil
+		default:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				values[k] = n
+			} else if f, err := strconv.ParseFloat(v, 64); err == nil {
+				values[k] = f
+			} else {
+				values[k] = v
+			}
+		}
+	}
+	return values, nil
+}
+
+func readAPIInput(name string) ([]byte, error) {
+	if name == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return b, nil
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return b, nil
+}
+
+func clusterServers(clusterName string) ([]string, error) {
+	conn, err := db.InitIfNeeded()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init db: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			fmt.Printf("failed to close database connection: %v", err)
+		}
+	}()
+
+	clusters, err := db.GetClusters(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clusters: %w", err)
+	}
+	clusterID := 0
+	for _, c := range clusters {
+		if c.Name == clusterName {
+			clusterID = c.Id
+			break
+		}
+	}
+	if clusterID == 0 {
+		return nil, fmt.Errorf("cluster not found: %s", clusterName)
+	}
+
+	nodes, err := db.GetNodes(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	var servers []string
+	for _, n := range nodes {
+		if n.ClusterID == clusterID {
+			servers = append(servers, fmt.Sprintf("%s://%s:%d", n.Protocol, n.Host, n.Port))
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("cluster %s has no nodes", clusterName)
+	}
+	return servers, nil
+}
+
+// sendAPIRequest sends req to cfg.Server. Requests without content report
+// success and return no body.
+func sendAPIRequest(ctx context.Context, cfg config.GlobalOptions, req utils.RawRequest) ([]byte, error) {
+	body, status, err := utils.CallRaw(ctx, cfg, req)
+	if err != nil {
+		return nil, err
+	}
+	if status == 204 || len(body) == 0 {
+		fmt.Printf("%v %s %s on %s (no content returned)\n",
+			messageUtils.SuccessMsg("Request succeeded"), req.Method, req.Path, cfg.Server)
+		return nil, nil
+	}
+	return body, nil
+}
+
+func printAPIResponse(cfg config.GlobalOptions, body []byte, jq string) {
+	if len(body) == 0 {
+		return
+	}
+	if jq != "" {
+		result := gjson.GetBytes(body, jq)
+		if !result.Exists() {
+			return
+		}
+		body = []byte(result.Raw)
+	}
+	utils.PrintResponse(cfg, body)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+)
+
+func TestAPICmdExitStatus(t *testing.T) {
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	defer ts.Close()
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(context.Background(), cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args   []string
+		status int
+	}{
+		{[]string{"GET", "/projects"}, 0},
+		{[]string{"GET", "/v3/access/users", "--jq", "#.username"}, 0},
+		{[]string{"GET", "/projects/00000000-0000-0000-0000-000000000000"}, http.StatusNotFound},
+		{[]string{"DELETE", "/access/users/00000000-0000-0000-0000-000000000000"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		cmd := NewAPICmd()
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetArgs(tt.args)
+		err := cmd.ExecuteContext(config.WithGlobalOptions(context.Background(), cfg))
+		if tt.status == 0 {
+			if err != nil {
+				t.Errorf("api %v = %v", tt.args, err)
+			}
+			continue
+		}
+		apiErr, ok := api.AsAPIError(err)
+		if !ok || apiErr.StatusCode != tt.status {
+			t.Errorf("api %v = %v, want status %d", tt.args, err, tt.status)
+		}
+	}
+}
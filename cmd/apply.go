@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/access"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/colorUtils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewApplyCmd() *cobra.Command {
+	var (
+		file     string
+		prune    bool
+		planOnly bool
+		yes      bool
+	)
+	var cmd = &cobra.Command{
+		Use:   "apply",
+		Short: "Make users, groups, roles, pools and ACL match a manifest",
+		Long: `Compare an access control manifest with the server, print the plan and
+apply it after confirmation.
+
+The manifest lists users, groups, roles, pools and ACL entries, all by
+name. Group members are usernames, role privileges are privilege names,
+pool projects are project names and ACL paths can name pools and projects
+(/pools/lab1). Creates and updates run in dependency order, deletes after
+them in reverse.
+
+Objects on the server that are not in the manifest are kept unless
+--prune is set. Built-in groups and roles, superadmins, the logged in user
+and everything the manifest refers to are never pruned. A user password is
+only used to create the user. "gns3util export access" writes the current
+state in the same format.`,
+		Example: `  gns3util -s https://controller:3080 apply -f access.yaml --plan
+  gns3util -s https://controller:3080 apply -f access.yaml --prune --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			m, err := access.Load(file)
+			if err != nil {
+				return err
+			}
+			client, err := sdk.NewFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			plan, err := access.Diff(cmd.Context(), client, m, prune)
+			if err != nil {
+				return fmt.Errorf("failed to plan changes: %w", err)
+			}
+
+			printAccessPlan(plan)
+			if len(plan.Changes) == 0 || planOnly {
+				return nil
+			}
+			if cfg.DryRun == nil && !yes {
+				if !term.IsTerminal(os.Stdin.Fd()) {
+					return fmt.Errorf("refusing to apply without a terminal to confirm, use --yes")
+				}
+				fmt.Println()
+				if !utils.ConfirmPrompt("Apply these changes?", false) {
+					fmt.Printf("%v nothing was changed\n", messageUtils.InfoMsg("Cancelled"))
+					return nil
+				}
+			}
+
+			fmt.Println()
+			err = plan.Apply(cmd.Context(), client, func(c access.Change, err error) {
+				if err == nil {
+					fmt.Printf("%s %s %s\n", actionSymbol(c.Action), c.Kind, c.Name)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if cfg.DryRun != nil {
+				fmt.Printf("%v %d to create, %d to update, %d to delete planned, nothing was sent\n", messageUtils.WarningMsg("Dry run"),
+					plan.Count(access.Create), plan.Count(access.Update), plan.Count(access.Delete))
+				return nil
+			}
+			fmt.Printf("%v %d created, %d updated, %d deleted\n", messageUtils.SuccessMsg("Applied"),
+				plan.Count(access.Create), plan.Count(access.Update), plan.Count(access.Delete))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Access control manifest to apply")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete objects that are not in the manifest")
+	cmd.Flags().BoolVar(&planOnly, "plan", false, "Only print the plan")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without asking for confirmation")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func actionSymbol(a access.Action) string {
+	switch a {
+	case access.Create:
+		return colorUtils.Success("+")
+	case access.Update:
+		return colorUtils.Warning("~")
+	}
+	return colorUtils.Error("-")
+}
+
+func printAccessPlan(plan *access.Plan) {
+	if len(plan.Changes) == 0 {
+		fmt.Printf("%v the server already matches the manifest\n", messageUtils.InfoMsg("No changes"))
+	}
+	for _, c := range plan.Changes {
+		fmt.Printf("  %s %s %s\n", actionSymbol(c.Action), c.Kind, messageUtils.Bold(c.Name))
+		for _, d := range c.Details {
+			fmt.Printf("      %s\n", d)
+		}
+	}
+	if len(plan.Changes) > 0 {
+		fmt.Printf("\n%s %d to create, %d to update, %d to delete.\n", messageUtils.Bold("Plan:"),
+			plan.Count(access.Create), plan.Count(access.Update), plan.Count(access.Delete))
+	}
+	if plan.Unmanaged > 0 {
+		fmt.Printf("%v %d object(s) on the server are not in the manifest, use --prune to delete them\n",
+			messageUtils.InfoMsg("Unmanaged"), plan.Unmanaged)
+	}
+}
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("apply", append(accessReadRoutes(),
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateUser()},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.UpdateUser("{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteUser("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateGroup()},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.AddGroupMember("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteUserFromGroup("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteGroup("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateRole()},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.UpdateRole("{}")},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.AddPrivilege("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteRolePrivilege("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteRole("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreatePool()},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.AddToPool("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeletePoolResource("{}", "{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeletePool("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateACL()},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.UpdateACE("{}")},
+		endpoints.Route{Method: string(api.DELETE), Path: ep.Delete.DeleteACE("{}")},
+	)...)
+}
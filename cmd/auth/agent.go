@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewAuthAgentCmd() *cobra.Command {
+	var ttl time.Duration
+	var stop bool
+	var cmd = &cobra.Command{
+		Use:   "agent",
+		Short: "Cache the credential store key for other commands",
+		Long: `Run an agent that keeps the key of the encrypted credential store in
+memory, so commands only ask for the passphrase once. It listens on
+~/.gns3/agent.sock (or GNS3UTIL_AGENT_SOCK), which only the current user can
+open, and forgets the key when it has not been used for --ttl.`,
+		Example: `  gns3util auth agent &
+  gns3util auth agent --ttl 1h
+  gns3util auth agent --stop`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stop {
+				if err := credstore.StopAgent(); err != nil {
+					return err
+				}
+				fmt.Printf("%v The agent forgot its keys and exited\n", messageUtils.SuccessMsg("Agent stopped"))
+				return nil
+			}
+
+			path, err := credstore.SocketPath()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%v Listening on %s\n", messageUtils.InfoMsg("Agent started"), path)
+			return credstore.ServeAgent(cmd.Context(), ttl)
+		},
+	}
+	cmd.Flags().DurationVar(&ttl, "ttl", 15*time.Minute, "Forget the key after it has not been used for this long, 0 keeps it until the agent stops")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop a running agent")
+	return cmd
+}
@@ -16,6 +16,10 @@ func NewAuthCmdGroup() *cobra.Command {
 
 	authCmd.AddCommand(NewAuthStatusCmd())
 	authCmd.AddCommand(NewAuthLoginCmd())
+	authCmd.AddCommand(NewAuthLogoutCmd())
+	authCmd.AddCommand(NewAuthLsCmd())
+	authCmd.AddCommand(NewAuthMigrateStoreCmd())
+	authCmd.AddCommand(NewAuthAgentCmd())
 
 	return authCmd
 }
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewAuthLsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List the stored identities",
+		Long: `List the users with a token in the keyfile for every server. The default
+identity of a server, used without --as, is marked with *.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Only the keyfile is read, no server is needed.
+			if err := config.ApplyContext(cmd); err != nil {
+				return err
+			}
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			cmd.SetContext(config.WithGlobalOptions(cmd.Context(), config.GlobalOptions{KeyFile: keyFile}))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			identities, err := authentication.Identities(cfg.KeyFile)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to load keyfile: %w", err)
+			}
+
+			raw, _ := cmd.Flags().GetBool("raw")
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			if output := utils.OutputFlag(cmd); output != "" {
+				mar, err := json.Marshal(identities)
+				if err != nil {
+					return fmt.Errorf("failed to marshall the results: %w", err)
+				}
+				return utils.WriteOutput(output, "", mar)
+			}
+			if raw {
+				if identities == nil {
+					identities = []authentication.Identity{}
+				}
+				mar, err := json.Marshal(identities)
+				if err != nil {
+					return fmt.Errorf("failed to marshall the results: %w", err)
+				}
+				if noColor {
+					utils.PrintJsonUgly(mar)
+				} else {
+					utils.PrintJson(mar)
+				}
+				return nil
+			}
+			if len(identities) == 0 {
+				fmt.Printf("No identities stored, log in with %s\n", messageUtils.Bold("auth login"))
+				return nil
+			}
+
+			utils.PrintTable(identities, []utils.Column[authentication.Identity]{
+				{Header: "", Value: func(i authentication.Identity) string {
+					if i.Default {
+						return "*"
+					}
+					return ""
+				}},
+				{Header: "Server", Value: func(i authentication.Identity) string { return i.Server }},
+				{Header: "User", Value: func(i authentication.Identity) string { return i.User }},
+				{Header: "Expires", Value: func(i authentication.Identity) string {
+					if i.ExpiresAt == 0 {
+						return "N/A"
+					}
+					expires := time.Unix(i.ExpiresAt, 0)
+					if time.Now().After(expires) {
+						return "expired"
+					}
+					return expires.Format(time.DateTime)
+				}},
+			})
+			return nil
+		},
+	}
+	utils.AddOutputFlag(cmd)
+	return cmd
+}
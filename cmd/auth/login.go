@@ -1,13 +1,13 @@
 package auth
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"strings"
+	"net/url"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
 	"github.com/stefanistkuhl/gns3util/pkg/authentication"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
@@ -16,12 +16,15 @@ import (
 
 var username string
 var password string
+var trustCert bool
 
 func NewAuthLoginCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "login",
 		Short: "Log in as user",
-		Long:  `Log in as a user`,
+		Long: `Log in as a user. Missing credentials are taken from the flags, the
+GNS3_USER and GNS3_PASSWORD variables, the credential helper and last an
+interactive prompt.`,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.SetEnvPrefix("GNS3")
 			viper.AutomaticEnv()
@@ -44,6 +47,16 @@ func NewAuthLoginCmd() *cobra.Command {
 				return
 			}
 
+			if err := trustServerCert(cmd.Context(), &cfg); err != nil {
+				fmt.Printf("%v %v\n", messageUtils.ErrorMsg("Error"), err)
+				return
+			}
+
+			username, password, err = authentication.LoginCredentials(cmd.Context(), cfg, username, password)
+			if err != nil {
+				fmt.Printf("%v %v\n", messageUtils.WarningMsg("Warning"), err)
+			}
+
 			if username == "" || password == "" {
 				interactiveUsername, interactivePassword, err := utils.GetLoginCredentials()
 				if err != nil {
@@ -64,53 +77,65 @@ func NewAuthLoginCmd() *cobra.Command {
 				return
 			}
 
-			credentials := schemas.Credentials{
-				Username: username,
-				Password: password,
-			}
-
-			data, err := json.Marshal(credentials)
-			if err != nil {
-				fmt.Printf("%s Failed to marshal credentials: %v\n", messageUtils.ErrorMsg("Error"), err)
-				return
-			}
-
-			var payload map[string]any
-			if err := json.Unmarshal(data, &payload); err != nil {
-				fmt.Printf("%s Failed to prepare payload: %v\n", messageUtils.ErrorMsg("Error"), err)
-				return
-			}
-			body, status, err := utils.CallClient(cfg, "userAuthenticate", []string{}, payload)
-			if err != nil {
-				if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Authentication was unsuccessful") {
+			if err := authentication.Login(cmd.Context(), cfg, username, password); err != nil {
+				if api.IsUnauthorized(err) {
 					fmt.Printf("%v Authentication failed. Please check your username and password.\n", messageUtils.ErrorMsg("Error"))
 					return
 				}
 				fmt.Printf("%v %v\n", messageUtils.ErrorMsg("Error"), err)
 				return
 			}
-
-			if status == 200 {
-				fmt.Printf("%v Successfully logged in as %s\n", messageUtils.SuccessMsg("Success"), messageUtils.Bold(username))
-				var token schemas.Token
-				marshallErr := json.Unmarshal(body, &token)
-				if marshallErr != nil {
-					fmt.Printf("%v failed to unmarshall response: %s", messageUtils.ErrorMsg("Error"), marshallErr)
-					return
-				}
-				writeErr := authentication.SaveAuthData(cfg, token, credentials.Username)
-				if writeErr != nil {
-					fmt.Printf("%v failed to write authentication data to the keyfile: %s", messageUtils.ErrorMsg("Error"), writeErr)
-					return
-				}
-			} else {
-				fmt.Printf("%v Authentication failed (status: %d)\n", messageUtils.ErrorMsg("Error"), status)
+			fmt.Printf("%v Successfully logged in as %s\n", messageUtils.SuccessMsg("Success"), messageUtils.Bold(username))
+			if key, ok := authentication.KeyForServer(config.GlobalOptions{Server: cfg.Server, KeyFile: cfg.KeyFile}); ok && key.User != username {
+				fmt.Printf("%v %s stays the default identity for %s, use %s to act as %s\n", messageUtils.InfoMsg("Identity added"),
+					messageUtils.Bold(key.User), cfg.Server, messageUtils.Bold("--as "+username), username)
 			}
 
 		},
 	}
 	cmd.Flags().StringVarP(&username, "user", "u", "", "User to log in as (env: GNS3_USER)")
 	cmd.Flags().StringVarP(&password, "password", "p", "", "Password to use (env: GNS3_PASSWORD)")
+	cmd.Flags().BoolVar(&trustCert, "trust-cert", false, "Pin the certificate the server presents now without asking, replacing a previously pinned one")
 
 	return cmd
 }
+
+// trustServerCert pins the certificate of an https server on the first login
+// if it does not pass normal verification, like the self-signed ones set up
+// by remote install https. The pin is saved with the key.
+func trustServerCert(ctx context.Context, cfg *config.GlobalOptions) error {
+	u, err := url.Parse(cfg.Server)
+	if err != nil || u.Scheme != "https" || cfg.Insecure || cfg.Cassette.Replaying() {
+		return nil
+	}
+	if cfg.CertPins.For(cfg.Server) != "" && !trustCert {
+		return nil
+	}
+
+	leaf, verifyErr, err := api.ProbeCertificate(ctx, config.APISettings(*cfg, ""))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", cfg.Server, err)
+	}
+	if verifyErr == nil && !trustCert {
+		return nil
+	}
+
+	fingerprint := api.CertFingerprint(leaf.Raw)
+	if !trustCert {
+		fmt.Printf("%v The certificate of %s is not trusted: %v\n", messageUtils.WarningMsg("Warning"), messageUtils.Bold(cfg.Server), verifyErr)
+		fmt.Printf("  Subject:     %s\n", leaf.Subject)
+		fmt.Printf("  Issuer:      %s\n", leaf.Issuer)
+		fmt.Printf("  Valid until: %s\n", leaf.NotAfter.Format("2006-01-02"))
+		fmt.Printf("  SHA-256:     %s\n", fingerprint)
+		if !utils.ConfirmPrompt("Trust this certificate and pin it for this server?", false) {
+			return fmt.Errorf("certificate not trusted, use --ca-file to trust its CA instead")
+		}
+	}
+
+	if cfg.CertPins == nil {
+		cfg.CertPins = api.CertPins{}
+	}
+	cfg.CertPins.Set(cfg.Server, fingerprint)
+	fmt.Printf("%v Pinned certificate %s\n", messageUtils.InfoMsg("Pinned certificate"), fingerprint)
+	return nil
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewAuthLogoutCmd() *cobra.Command {
+	var user string
+	var cmd = &cobra.Command{
+		Use:   "logout",
+		Short: "Remove a stored identity",
+		Long: `Remove the token of a user for the server from the keyfile. Without --user
+the identity selected by --as is removed, otherwise the default one.`,
+		Example: `  gns3util -s https://lab:3080 auth logout
+  gns3util -s https://lab:3080 auth logout --user student1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			if user != "" {
+				cfg.As = user
+			}
+
+			defaultCfg := config.GlobalOptions{Server: cfg.Server, KeyFile: cfg.KeyFile}
+			previous, _ := authentication.KeyForServer(defaultCfg)
+			removed, err := authentication.RemoveIdentity(cfg)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%v Logged out %s from %s\n", messageUtils.SuccessMsg("Logged out"), messageUtils.Bold(removed), cfg.Server)
+			if key, ok := authentication.KeyForServer(defaultCfg); ok && previous.User == removed {
+				fmt.Printf("%v %s is the default identity for %s now\n", messageUtils.InfoMsg("Default identity"), messageUtils.Bold(key.User), cfg.Server)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&user, "user", "u", "", "User to log out")
+	return cmd
+}
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewAuthMigrateStoreCmd() *cobra.Command {
+	var decrypt bool
+	var cmd = &cobra.Command{
+		Use:   "migrate-store",
+		Short: "Encrypt the stored tokens and student passwords",
+		Long: `Set up the encrypted credential store and convert the existing keyfile
+tokens and the default passwords of students in the cluster database to it.
+
+The key is derived from a passphrase with scrypt. Commands that need a
+credential ask for the passphrase, read it from GNS3UTIL_PASSPHRASE or get the
+key from a running auth agent. The tokens use a store next to the keyfile, the
+student passwords always the one in ~/.gns3 next to the cluster database. Run the command again after copying a plain
+keyfile over, --decrypt converts everything back and removes the store.`,
+		Example: `  gns3util auth migrate-store
+  gns3util -k ~/lab/gns3key auth migrate-store
+  gns3util auth migrate-store --decrypt`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Only local files are touched, no server is needed.
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			if err := credstore.UseKeyFile(keyFile); err != nil {
+				return err
+			}
+			cmd.SetContext(config.WithGlobalOptions(cmd.Context(), config.GlobalOptions{KeyFile: keyFile}))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+
+			tokens, passwords, err := migrateStore(cfg.KeyFile, decrypt)
+			if err != nil {
+				return err
+			}
+			if decrypt {
+				fmt.Printf("%v Decrypted %d tokens and %d student passwords, the credential store is removed\n",
+					messageUtils.SuccessMsg("Decrypted"), tokens, passwords)
+				return nil
+			}
+			fmt.Printf("%v Encrypted %d tokens and %d student passwords\n",
+				messageUtils.SuccessMsg("Encrypted"), tokens, passwords)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&decrypt, "decrypt", false, "Decrypt everything again and remove the credential store")
+	return cmd
+}
+
+// migrateStore converts the tokens in keyFile with the store next to it and
+// the student passwords in the cluster database with the store in ~/.gns3,
+// setting up whichever of them is missing.
+func migrateStore(keyFile string, decrypt bool) (tokens, passwords int, err error) {
+	keyStore, clusterStore := credstore.Keys(), credstore.Cluster()
+	stores := []credstore.Store{keyStore}
+	if !clusterStore.Same(keyStore) {
+		stores = append(stores, clusterStore)
+	}
+
+	convert := func(s credstore.Store) func(string) (string, error) {
+		return s.Encrypt
+	}
+	if decrypt {
+		enabled := false
+		for _, s := range stores {
+			enabled = enabled || s.Enabled()
+		}
+		if !enabled {
+			return 0, 0, fmt.Errorf("the credential store is not set up")
+		}
+		convert = func(s credstore.Store) func(string) (string, error) {
+			return s.Decrypt
+		}
+	} else {
+		passphrase := ""
+		for _, s := range stores {
+			if s.Enabled() {
+				continue
+			}
+			if passphrase == "" {
+				if passphrase, err = newPassphrase(); err != nil {
+					return 0, 0, err
+				}
+			}
+			if err := s.Init(passphrase); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	keys, err := authentication.LoadKeys(keyFile)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, 0, fmt.Errorf("failed to load keyfile: %w", err)
+	}
+	for i, key := range keys {
+		token, err := convert(keyStore)(key.AccessToken)
+		if err != nil {
+			return 0, 0, err
+		}
+		if token != key.AccessToken {
+			keys[i].AccessToken = token
+			tokens++
+		}
+		// Passwords for logging in again are never kept in plain text.
+		if decrypt {
+			keys[i].Password = ""
+		}
+	}
+	// Also rewritten without changes to restrict the permissions.
+	if len(keys) > 0 {
+		if err := authentication.WriteKeys(keyFile, keys); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	conn, err := db.InitIfNeeded()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to init db: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			fmt.Printf("failed to close database connection: %v", err)
+		}
+	}()
+	if passwords, err = db.RewritePasswords(conn, convert(clusterStore)); err != nil {
+		return 0, 0, err
+	}
+
+	if decrypt {
+		for _, s := range stores {
+			if !s.Enabled() {
+				continue
+			}
+			if err := s.Remove(); err != nil {
+				return 0, 0, fmt.Errorf("failed to remove the credential store: %w", err)
+			}
+		}
+	}
+	return tokens, passwords, nil
+}
+
+func newPassphrase() (string, error) {
+	passphrase := os.Getenv(credstore.PassphraseEnv)
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, err := credstore.ReadPassphrase("New passphrase for the credential store: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := credstore.ReadPassphrase("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+// studentPasswords returns the passwords of the class as a later run reads
+// them from the cluster database.
+func studentPasswords(t *testing.T, clusterID int) []string {
+	t.Helper()
+	conn, err := db.InitIfNeeded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	nodes, err := db.GetNodeGroupNamesForClass(conn, clusterID, "net101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var passwords []string
+	for _, n := range nodes {
+		for _, g := range n.Groups {
+			for _, s := range g.Students {
+				passwords = append(passwords, s.Password)
+			}
+		}
+	}
+	return passwords
+}
+
+func TestMigrateStoreWithKeyFile(t *testing.T) {
+	homedir.DisableCache = true
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(credstore.AgentSocketEnv, filepath.Join(home, "agent.sock"))
+	t.Setenv(credstore.PassphraseEnv, "passphrase")
+	t.Cleanup(func() { _ = credstore.UseKeyFile("") })
+
+	conn, err := db.InitIfNeeded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := db.CreateClusters(conn, []db.ClusterName{{Name: "lab"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterID := clusters[0].Id
+	nodes, err := db.InsertNodes(clusterID, []db.NodeData{{User: "admin", Protocol: "http", Host: "lab", Port: 3080, Weight: 1, MaxGroups: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullName := "Alice"
+	class, err := db.InsertClassIntoDB(conn, clusterID, schemas.Class{
+		Name:   "net101",
+		Groups: []schemas.Group{{Name: "net101-g1", Students: []schemas.Student{{UserName: "alice", FullName: &fullName, Password: "s3cret"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AssignGroupsToNode(conn, db.NodeAndGroupIds{Assignments: []db.AssignmentIds{
+		{NodeID: nodes[0].ID, GroupIDs: []int{class.GroupIDs["net101-g1"]}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	lab := t.TempDir()
+	keyFile := filepath.Join(lab, "gns3key")
+	if err := authentication.WriteKeys(keyFile, []pathUtils.GNS3Key{{ServerURL: "http://lab:3080", User: "admin", AccessToken: "token"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// gns3util -k ~/lab/gns3key auth migrate-store
+	if err := credstore.UseKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	tokens, passwords, err := migrateStore(keyFile, false)
+	if err != nil || tokens != 1 || passwords != 1 {
+		t.Fatalf("migrateStore() = %d, %d, %v, want 1 token and 1 password", tokens, passwords, err)
+	}
+	for _, dir := range []string{lab, filepath.Join(home, ".gns3")} {
+		if _, err := os.Stat(filepath.Join(dir, "credstore.json")); err != nil {
+			t.Errorf("no store in %s: %v", dir, err)
+		}
+	}
+	keys, err := authentication.LoadKeys(keyFile)
+	if err != nil || len(keys) != 1 || !credstore.IsEncrypted(keys[0].AccessToken) {
+		t.Fatalf("keyfile after migrate = %+v, %v", keys, err)
+	}
+
+	// A later run without -k still reads the student passwords.
+	if err := credstore.UseKeyFile(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := studentPasswords(t, clusterID); len(got) != 1 || got[0] != "s3cret" {
+		t.Errorf("passwords without -k = %q, want [s3cret]", got)
+	}
+
+	if err := credstore.UseKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := migrateStore(keyFile, true); err != nil {
+		t.Fatalf("migrateStore(decrypt) = %v", err)
+	}
+	if keys, _ := authentication.LoadKeys(keyFile); len(keys) != 1 || keys[0].AccessToken != "token" {
+		t.Errorf("keyfile after decrypt = %+v", keys)
+	}
+	if credstore.Keys().Enabled() || credstore.Cluster().Enabled() {
+		t.Error("a store is left after decrypt")
+	}
+	if got := studentPasswords(t, clusterID); len(got) != 1 || got[0] != "s3cret" {
+		t.Errorf("passwords after decrypt = %q, want [s3cret]", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
@@ -30,8 +31,14 @@ func NewAuthStatusCmd() *cobra.Command {
 				panic(err)
 			}
 
-			userData, err := authentication.TryKeys(keys, cfg)
+			userData, err := authentication.TryKeys(cmd.Context(), keys, cfg)
 			if err != nil {
+				if key, ok := authentication.KeyForServer(cfg); ok && key.ExpiresAt != 0 && time.Now().Unix() >= key.ExpiresAt {
+					fmt.Printf("%v The token for %s expired at %s. Use %s to log in again.\n",
+						messageUtils.ErrorMsg("Token expired"), messageUtils.Bold(cfg.Server),
+						time.Unix(key.ExpiresAt, 0).Format(time.DateTime), messageUtils.Bold("auth login"))
+					return
+				}
 				fmt.Println(err)
 				return
 			}
@@ -40,8 +47,27 @@ func NewAuthStatusCmd() *cobra.Command {
 			if err != nil {
 				log.Fatalf("Error unmarshaling JSON: %v", err)
 			}
-			fmt.Printf("%s logged in as user %s", messageUtils.SuccessMsg("Logged in as user"), messageUtils.Bold(*user.Username))
+			fmt.Printf("%s logged in as user %s\n", messageUtils.SuccessMsg("Logged in as user"), messageUtils.Bold(*user.Username))
+			printTokenExpiry(cfg)
 		},
 	}
 	return cmd
 }
+
+// tokenExpiryWarning is how long before the expiry auth status warns.
+const tokenExpiryWarning = time.Hour
+
+func printTokenExpiry(cfg config.GlobalOptions) {
+	key, ok := authentication.KeyForServer(cfg)
+	if !ok || key.ExpiresAt == 0 {
+		return
+	}
+	expires := time.Unix(key.ExpiresAt, 0)
+	left := time.Until(expires).Round(time.Minute)
+	if left < tokenExpiryWarning {
+		fmt.Printf("%v The token expires in %s, at %s. Use %s to renew it.\n",
+			messageUtils.WarningMsg("Token expires soon"), left, expires.Format(time.DateTime), messageUtils.Bold("auth login"))
+		return
+	}
+	fmt.Printf("%v The token is valid until %s (%s left)\n", messageUtils.InfoMsg("Token"), expires.Format(time.DateTime), left)
+}
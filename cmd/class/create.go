@@ -40,6 +40,9 @@ The class structure includes:
   gns3util -s https://controller:3080 class create --interactive
 		`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.ApplyContext(cmd); err != nil {
+				return err
+			}
 			serverUrl, _ := cmd.InheritedFlags().GetString("server")
 			cluster, _ := cmd.Flags().GetString("cluster")
 			filePath, _ := cmd.Flags().GetString("file")
@@ -53,7 +56,9 @@ The class structure includes:
 			if filePath == "" && !interactive {
 				return errorUtils.FormatError("either --file or --interactive must be specified")
 			}
-			return nil
+			// Cobra only runs the nearest persistent hook, so run the root one
+			// to get the global options into the context.
+			return cmd.Root().PersistentPreRunE(cmd, args)
 		},
 		RunE: runCreateClass,
 	}
@@ -250,7 +255,7 @@ func runCreateClass(cmd *cobra.Command, args []string) error {
 		insertedNodes = filteredNodes
 	}
 
-	success, err := class.CreateClass(cfg, clusterID, classData, insertedNodes)
+	success, err := class.CreateClass(cmd.Context(), cfg, clusterID, classData, insertedNodes)
 	if err != nil {
 		err = errorUtils.WrapError(err, "failed to create class")
 		fmt.Printf("%v\n", err)
@@ -1,7 +1,9 @@
 package class
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -104,12 +106,12 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 
 	if targetClassName != "" {
 		if clusterName != "" {
-			if err := deleteClassInCluster(cfg, clusterName, targetClassName, confirm, deleteExercises); err != nil {
+			if err := deleteClassInCluster(cmd.Context(), cfg, clusterName, targetClassName, confirm, deleteExercises); err != nil {
 				return fmt.Errorf("failed to delete class: %w", err)
 			}
 			return nil
 		}
-		if err := deleteClassWithConfirmation(cfg, targetClassName, confirm, deleteExercises); err != nil {
+		if err := deleteClassWithConfirmation(cmd.Context(), cfg, targetClassName, confirm, deleteExercises); err != nil {
 			return fmt.Errorf("failed to delete class: %w", err)
 		}
 	} else if deleteAll {
@@ -118,7 +120,7 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 		if clusterName != "" {
 			classNames, err = getAllClassNamesFromDBForClusterName(clusterName)
 		} else {
-			classNames, err = getAllClassNames(cfg, dbFirst)
+			classNames, err = getAllClassNames(cmd.Context(), cfg, dbFirst)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to get class names: %w", err)
@@ -141,12 +143,15 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		for _, name := range classNames {
+		for i, name := range classNames {
 			var derr error
 			if clusterName != "" {
-				derr = deleteClassInCluster(cfg, clusterName, name, false, deleteExercises)
+				derr = deleteClassInCluster(cmd.Context(), cfg, clusterName, name, false, deleteExercises)
 			} else {
-				derr = deleteClassWithConfirmation(cfg, name, false, deleteExercises)
+				derr = deleteClassWithConfirmation(cmd.Context(), cfg, name, false, deleteExercises)
+			}
+			if errors.Is(derr, context.Canceled) {
+				return fmt.Errorf("deletion interrupted after %d/%d classes: %w", i, len(classNames), derr)
 			}
 			if derr != nil {
 				fmt.Printf("%v Failed to delete class %v: %v\n",
@@ -161,7 +166,7 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 		if clusterName != "" {
 			classNames, err = selectClassesWithFuzzyForClusterName(clusterName, multi)
 		} else {
-			classNames, err = selectClassesWithFuzzy(cfg, multi, dbFirst)
+			classNames, err = selectClassesWithFuzzy(cmd.Context(), cfg, multi, dbFirst)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to select classes: %w", err)
@@ -172,12 +177,15 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		for _, name := range classNames {
+		for i, name := range classNames {
 			var derr error
 			if clusterName != "" {
-				derr = deleteClassInCluster(cfg, clusterName, name, confirm, deleteExercises)
+				derr = deleteClassInCluster(cmd.Context(), cfg, clusterName, name, confirm, deleteExercises)
 			} else {
-				derr = deleteClassWithConfirmation(cfg, name, confirm, deleteExercises)
+				derr = deleteClassWithConfirmation(cmd.Context(), cfg, name, confirm, deleteExercises)
+			}
+			if errors.Is(derr, context.Canceled) {
+				return fmt.Errorf("deletion interrupted after %d/%d classes: %w", i, len(classNames), derr)
 			}
 			if derr != nil {
 				fmt.Printf("%v Failed to delete class %v: %v\n",
@@ -191,7 +199,7 @@ func runDeleteClass(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func deleteClassInCluster(cfg config.GlobalOptions, clusterName, className string, confirm bool, deleteExercises bool) error {
+func deleteClassInCluster(ctx context.Context, cfg config.GlobalOptions, clusterName, className string, confirm bool, deleteExercises bool) error {
 	if confirm {
 		message := fmt.Sprintf("Delete class '%s' from cluster '%s'?", className, clusterName)
 		if deleteExercises {
@@ -248,13 +256,13 @@ func deleteClassInCluster(cfg config.GlobalOptions, clusterName, className strin
 		nodeCfg.Server = fmt.Sprintf("%s://%s:%d", n.Protocol, n.Host, n.Port)
 
 		if deleteExercises {
-			if err := class.DeleteAllExercisesForClass(nodeCfg, className); err != nil {
+			if err := class.DeleteAllExercisesForClass(ctx, nodeCfg, className); err != nil {
 				fmt.Printf("%v failed to delete exercises for class %v on %s: %v\n",
 					messageUtils.WarningMsg("Warning"), messageUtils.Bold(className), nodeCfg.Server, err)
 			}
 		}
 
-		if err := class.DeleteClass(nodeCfg, className); err != nil {
+		if err := class.DeleteClass(ctx, nodeCfg, className); err != nil {
 			fmt.Printf("%v Failed to delete class %v on %s: %v\n",
 				messageUtils.ErrorMsg("Failed to delete class"), messageUtils.Bold(className), nodeCfg.Server, err)
 		} else {
@@ -266,14 +274,14 @@ func deleteClassInCluster(cfg config.GlobalOptions, clusterName, className strin
 	return nil
 }
 
-func getAllClassNames(cfg config.GlobalOptions, dbFirst bool) ([]string, error) {
+func getAllClassNames(ctx context.Context, cfg config.GlobalOptions, dbFirst bool) ([]string, error) {
 	if dbFirst {
 		classNames, err := getAllClassNamesFromDB(cfg)
 		if err == nil && len(classNames) > 0 {
 			return classNames, nil
 		}
 	}
-	return getAllClassNamesFromAPI(cfg)
+	return getAllClassNamesFromAPI(ctx, cfg)
 }
 
 func getAllClassNamesFromDB(cfg config.GlobalOptions) ([]string, error) {
@@ -305,8 +313,8 @@ func getAllClassNamesFromDB(cfg config.GlobalOptions) ([]string, error) {
 	return classNames, nil
 }
 
-func getAllClassNamesFromAPI(cfg config.GlobalOptions) ([]string, error) {
-	groupsBody, status, err := utils.CallClient(cfg, "getGroups", []string{}, nil)
+func getAllClassNamesFromAPI(ctx context.Context, cfg config.GlobalOptions) ([]string, error) {
+	groupsBody, status, err := utils.CallClient(ctx, cfg, "getGroups", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get groups: %w", err)
 	}
@@ -322,7 +330,7 @@ func getAllClassNamesFromAPI(cfg config.GlobalOptions) ([]string, error) {
 	return getClassNamesFromGroups(groups)
 }
 
-func selectClassesWithFuzzy(cfg config.GlobalOptions, multi bool, dbFirst bool) ([]string, error) {
+func selectClassesWithFuzzy(ctx context.Context, cfg config.GlobalOptions, multi bool, dbFirst bool) ([]string, error) {
 	if dbFirst {
 		classNames, err := getAllClassNamesFromDB(cfg)
 		if err == nil && len(classNames) > 0 {
@@ -331,11 +339,11 @@ func selectClassesWithFuzzy(cfg config.GlobalOptions, multi bool, dbFirst bool)
 		}
 	}
 
-	return selectClassesWithFuzzyFromAPI(cfg, multi)
+	return selectClassesWithFuzzyFromAPI(ctx, cfg, multi)
 }
 
-func selectClassesWithFuzzyFromAPI(cfg config.GlobalOptions, multi bool) ([]string, error) {
-	groupsBody, status, err := utils.CallClient(cfg, "getGroups", []string{}, nil)
+func selectClassesWithFuzzyFromAPI(ctx context.Context, cfg config.GlobalOptions, multi bool) ([]string, error) {
+	groupsBody, status, err := utils.CallClient(ctx, cfg, "getGroups", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get groups: %w", err)
 	}
@@ -416,7 +424,7 @@ func getClassNamesFromGroups(groups []schemas.UserGroupResponse) ([]string, erro
 	return classNames, nil
 }
 
-func deleteClassWithConfirmation(cfg config.GlobalOptions, className string, confirm bool, deleteExercises bool) error {
+func deleteClassWithConfirmation(ctx context.Context, cfg config.GlobalOptions, className string, confirm bool, deleteExercises bool) error {
 	if confirm {
 		message := fmt.Sprintf("Delete class '%s'?", className)
 		if deleteExercises {
@@ -434,7 +442,7 @@ func deleteClassWithConfirmation(cfg config.GlobalOptions, className string, con
 			messageUtils.InfoMsg("Deleting exercises for class"),
 			messageUtils.Bold(className))
 
-		if err := class.DeleteAllExercisesForClass(cfg, className); err != nil {
+		if err := class.DeleteAllExercisesForClass(ctx, cfg, className); err != nil {
 			fmt.Printf("%v failed to delete exercises for class %v: %v\n",
 				messageUtils.WarningMsg("Warning: failed to delete exercises for class"),
 				messageUtils.Bold(className),
@@ -446,7 +454,7 @@ func deleteClassWithConfirmation(cfg config.GlobalOptions, className string, con
 		}
 	}
 
-	return class.DeleteClass(cfg, className)
+	return class.DeleteClass(ctx, cfg, className)
 }
 
 func confirmAction(message string) bool {
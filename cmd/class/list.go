@@ -1,7 +1,9 @@
 package class
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -26,6 +28,8 @@ func NewClassLsCmd() *cobra.Command {
 	listCmd.Flags().Bool("db-only", false, "Show only classes from database (skip API calls)")
 	listCmd.Flags().Bool("api-only", false, "Show only classes from API (skip database)")
 	listCmd.Flags().StringP("cluster", "c", "", "Cluster name")
+	utils.AddOutputFlag(listCmd)
+	utils.AddListFlags(listCmd)
 
 	return listCmd
 }
@@ -96,6 +100,13 @@ func runListClasses(cmd *cobra.Command, args []string) error {
 		finalClasses = append(finalClasses, class)
 	}
 
+	if cfg.Output != "" || cfg.List.Active() {
+		if cfg.Output == "" && cfg.List.Query == "" {
+			cfg.Output = "table"
+		}
+		return printClassesOutput(cfg, finalClasses)
+	}
+
 	utils.PrintTable(finalClasses, []utils.Column[ClassDistribution]{
 		{
 			Header: "Class Name",
@@ -181,6 +192,42 @@ func runListClasses(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+type classRow struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Groups      int            `json:"groups"`
+	Users       int            `json:"users"`
+	Nodes       []classNodeRow `json:"nodes"`
+}
+
+type classNodeRow struct {
+	Node       string   `json:"node"`
+	Groups     int      `json:"groups"`
+	Users      int      `json:"users"`
+	GroupNames []string `json:"group_names"`
+}
+
+func printClassesOutput(cfg config.GlobalOptions, classes []ClassDistribution) error {
+	rows := make([]classRow, 0, len(classes))
+	for _, class := range classes {
+		row := classRow{Name: class.Name, Description: class.Description, Nodes: []classNodeRow{}}
+		for nodeURL, node := range class.Nodes {
+			row.Groups += node.GroupCount
+			row.Users += node.UserCount
+			row.Nodes = append(row.Nodes, classNodeRow{Node: nodeURL, Groups: node.GroupCount, Users: node.UserCount, GroupNames: node.GroupNames})
+		}
+		sort.Slice(row.Nodes, func(i, j int) bool { return row.Nodes[i].Node < row.Nodes[j].Node })
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("failed to marshall the results: %w", err)
+	}
+	utils.PrintOutput(cfg, "class", body)
+	return nil
+}
+
 type ClassDistribution struct {
 	Name        string
 	Description string
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/stefanistkuhl/gns3util/cmd/clustercmd"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
 )
 
 func NewClusterCmdGroup() *cobra.Command {
@@ -11,11 +12,18 @@ func NewClusterCmdGroup() *cobra.Command {
 		Short: "cluster operations",
 		Long:  `Create and organize your GNS3 servers inside of a cluster`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-
+			if err := config.ApplyContext(cmd); err != nil {
+				return err
+			}
 			if err := validateGlobalFlags(); err != nil {
 				return err
 			}
 
+			opts, err := globalOptionsFromFlags()
+			if err != nil {
+				return err
+			}
+			cmd.SetContext(config.WithGlobalOptions(cmd.Context(), opts))
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
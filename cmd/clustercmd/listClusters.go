@@ -33,6 +33,17 @@ func NewLsClusterCmd() *cobra.Command {
 			}
 			raw, _ := cmd.InheritedFlags().GetBool("raw")
 			noColor, _ := cmd.InheritedFlags().GetBool("no-color")
+			if output := utils.OutputFlag(cmd); output != "" {
+				mar, err := json.Marshal(clusters)
+				if err != nil {
+					fmt.Printf("%s failed to marshall the results %s", messageUtils.ErrorMsg("Error"), err)
+					return
+				}
+				if err := utils.WriteOutput(output, "cluster", mar); err != nil {
+					fmt.Printf("%s %s\n", messageUtils.ErrorMsg("Error"), err)
+				}
+				return
+			}
 			if raw {
 				mar, err := json.Marshal(clusters)
 				if err != nil {
@@ -72,6 +83,7 @@ func NewLsClusterCmd() *cobra.Command {
 
 		},
 	}
+	utils.AddOutputFlag(cmd)
 
 	return cmd
 }
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/cmd/contextcmd"
+)
+
+func NewContextCmdGroup() *cobra.Command {
+	contextCmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage named contexts",
+		Long: `Manage named contexts that supply --server, --insecure, --key-file and a
+default cluster, so they are not needed on every command. The current
+context is stored in ~/.gns3/contexts.toml, GNS3UTIL_CONTEXT or --context
+select another one.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Context commands only edit the contexts file
+			return nil
+		},
+	}
+	contextCmd.AddCommand(contextcmd.NewAddContextCmd())
+	contextCmd.AddCommand(contextcmd.NewUseContextCmd())
+	contextCmd.AddCommand(contextcmd.NewLsContextCmd())
+	contextCmd.AddCommand(contextcmd.NewCurrentContextCmd())
+	contextCmd.AddCommand(contextcmd.NewRmContextCmd())
+
+	return contextCmd
+}
@@ -0,0 +1,71 @@
+package contextcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewAddContextCmd() *cobra.Command {
+	var user, cluster string
+	var use bool
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add or update a context",
+		Long: `Add a context from the global --server, --insecure, --key-file and
+--credential-helper flags, a user and a default cluster. An existing context
+with the name is replaced.`,
+		Example: `  gns3util context add lab1 --server https://lab1:3080 --insecure --user teacher
+  gns3util context add prod --cluster production-cluster --use`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			server, _ := cmd.Flags().GetString("server")
+			insecure, _ := cmd.Flags().GetBool("insecure")
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			credentialHelper, _ := cmd.Flags().GetString("credential-helper")
+			if server == "" && cluster == "" {
+				return fmt.Errorf("a context needs --server or --cluster")
+			}
+
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			_, exists := contexts.Contexts[name]
+			if err := contexts.Set(name, config.Context{
+				Server:   server,
+				Insecure: insecure,
+				User:     user,
+				KeyFile:  keyFile,
+				Cluster:  cluster,
+
+				CredentialHelper: credentialHelper,
+			}); err != nil {
+				return err
+			}
+			if use {
+				contexts.Current = name
+			}
+			if err := config.WriteContexts(contexts); err != nil {
+				return fmt.Errorf("failed to write contexts: %w", err)
+			}
+
+			action := "Added"
+			if exists {
+				action = "Updated"
+			}
+			fmt.Printf("%v %s context %s\n", messageUtils.SuccessMsg(action+" context"), action, messageUtils.Bold(name))
+			if use {
+				fmt.Printf("%v Switched to context %s\n", messageUtils.InfoMsg("Current context"), messageUtils.Bold(name))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&user, "user", "", "User to act as on the server")
+	cmd.Flags().StringVar(&cluster, "cluster", "", "Default cluster for commands with a --cluster flag")
+	cmd.Flags().BoolVar(&use, "use", false, "Also make it the current context")
+	return cmd
+}
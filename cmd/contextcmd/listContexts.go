@@ -0,0 +1,108 @@
+package contextcmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+type contextRow struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	config.Context
+}
+
+func NewLsContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the contexts",
+		Long:  `List the contexts, the active one is marked with *.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			override, _ := cmd.Flags().GetString("context")
+			active, _, _, _ := config.ActiveContext(override)
+
+			rows := make([]contextRow, 0, len(contexts.Contexts))
+			for _, name := range contexts.Names() {
+				rows = append(rows, contextRow{Name: name, Active: name == active, Context: contexts.Contexts[name]})
+			}
+
+			raw, _ := cmd.Flags().GetBool("raw")
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			if output := utils.OutputFlag(cmd); output != "" {
+				mar, err := json.Marshal(rows)
+				if err != nil {
+					return fmt.Errorf("failed to marshall the results: %w", err)
+				}
+				return utils.WriteOutput(output, "", mar)
+			}
+			if raw {
+				mar, err := json.Marshal(rows)
+				if err != nil {
+					return fmt.Errorf("failed to marshall the results: %w", err)
+				}
+				if noColor {
+					utils.PrintJsonUgly(mar)
+				} else {
+					utils.PrintJson(mar)
+				}
+				return nil
+			}
+			if len(rows) == 0 {
+				fmt.Printf("No contexts found, add one with %s\n", messageUtils.Bold("context add"))
+				return nil
+			}
+
+			orNA := func(s string) string {
+				if s == "" {
+					return "N/A"
+				}
+				return s
+			}
+			utils.PrintTable(rows, []utils.Column[contextRow]{
+				{Header: "", Value: func(r contextRow) string {
+					if r.Active {
+						return "*"
+					}
+					return ""
+				}},
+				{Header: "Name", Value: func(r contextRow) string { return r.Name }},
+				{Header: "Server", Value: func(r contextRow) string { return orNA(r.Server) }},
+				{Header: "Cluster", Value: func(r contextRow) string { return orNA(r.Cluster) }},
+				{Header: "User", Value: func(r contextRow) string { return orNA(r.User) }},
+				{Header: "Insecure", Value: func(r contextRow) string { return fmt.Sprintf("%t", r.Insecure) }},
+			})
+			return nil
+		},
+	}
+	utils.AddOutputFlag(cmd)
+	return cmd
+}
+
+func NewCurrentContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Print the active context",
+		Long:  `Print the name of the active context, as selected by --context, GNS3UTIL_CONTEXT or context use.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			override, _ := cmd.Flags().GetString("context")
+			name, _, ok, err := config.ActiveContext(override)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("no context is active, select one with context use")
+			}
+			fmt.Println(name)
+			return nil
+		},
+	}
+	return cmd
+}
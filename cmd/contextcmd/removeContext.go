@@ -0,0 +1,38 @@
+package contextcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewRmContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove a context",
+		Long:  `Remove a context. Removing the current context leaves no context selected.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			if _, ok := contexts.Contexts[name]; !ok {
+				return fmt.Errorf("context %q does not exist", name)
+			}
+			delete(contexts.Contexts, name)
+			if contexts.Current == name {
+				contexts.Current = ""
+			}
+			if err := config.WriteContexts(contexts); err != nil {
+				return fmt.Errorf("failed to write contexts: %w", err)
+			}
+			fmt.Printf("%v Removed context %s\n", messageUtils.SuccessMsg("Removed context"), messageUtils.Bold(name))
+			return nil
+		},
+	}
+	return cmd
+}
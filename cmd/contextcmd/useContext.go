@@ -0,0 +1,56 @@
+package contextcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fuzzy"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewUseContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "Switch the current context",
+		Long:  `Switch the current context. Without a name a fuzzy finder lists the contexts.`,
+		Example: `  gns3util context use lab1
+  GNS3UTIL_CONTEXT=lab2 gns3util project ls`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			if len(contexts.Contexts) == 0 {
+				return fmt.Errorf("no contexts, add one with context add")
+			}
+
+			var name string
+			if len(args) == 1 {
+				name = args[0]
+			} else {
+				selected := fuzzy.NewFuzzyFinderWithTitle(contexts.Names(), false, "Select a context:")
+				if len(selected) == 0 {
+					return nil
+				}
+				name = selected[0]
+			}
+			if _, ok := contexts.Contexts[name]; !ok {
+				return fmt.Errorf("context %q does not exist", name)
+			}
+
+			contexts.Current = name
+			if err := config.WriteContexts(contexts); err != nil {
+				return fmt.Errorf("failed to write contexts: %w", err)
+			}
+			fmt.Printf("%v Switched to context %s\n", messageUtils.SuccessMsg("Current context"), messageUtils.Bold(name))
+			if env := os.Getenv(config.ContextEnv); env != "" && env != name {
+				fmt.Printf("%v %s=%s still selects %s in this shell\n", messageUtils.WarningMsg("Warning"), config.ContextEnv, env, messageUtils.Bold(env))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
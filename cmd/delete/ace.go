@@ -28,7 +28,7 @@ func NewDeleteACECmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteACE", []string{aceID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteACE", []string{aceID})
 		},
 	}
 
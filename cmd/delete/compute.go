@@ -24,7 +24,7 @@ func NewDeleteComputeCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(computeID) {
-				id, err := utils.ResolveID(cfg, "compute", computeID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "compute", computeID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteComputeCmd() *cobra.Command {
 				computeID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteCompute", []string{computeID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteCompute", []string{computeID})
 		},
 	}
 
@@ -24,7 +24,7 @@ func NewDeleteGroupCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(groupID) {
-				id, err := utils.ResolveID(cfg, "group", groupID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "group", groupID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteGroupCmd() *cobra.Command {
 				groupID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteGroup", []string{groupID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteGroup", []string{groupID})
 		},
 	}
 
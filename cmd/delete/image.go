@@ -23,7 +23,7 @@ func NewDeleteImageCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteImage", []string{imageID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteImage", []string{imageID})
 		},
 	}
 
@@ -25,7 +25,7 @@ func NewDeleteLinkCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -38,7 +38,7 @@ func NewDeleteLinkCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteLink", []string{projectID, linkID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteLink", []string{projectID, linkID})
 		},
 	}
 
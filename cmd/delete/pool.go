@@ -24,7 +24,7 @@ func NewDeletePoolCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(poolID) {
-				id, err := utils.ResolveID(cfg, "pool", poolID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "pool", poolID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeletePoolCmd() *cobra.Command {
 				poolID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deletePool", []string{poolID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deletePool", []string{poolID})
 		},
 	}
 
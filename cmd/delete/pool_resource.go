@@ -25,7 +25,7 @@ func NewDeletePoolResourceCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(poolID) {
-				id, err := utils.ResolveID(cfg, "pool", poolID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "pool", poolID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -38,7 +38,7 @@ func NewDeletePoolResourceCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deletePoolResource", []string{poolID, resourceID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deletePoolResource", []string{poolID, resourceID})
 		},
 	}
 
@@ -24,7 +24,7 @@ func NewDeleteProjectCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteProjectCmd() *cobra.Command {
 				projectID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteProject", []string{projectID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteProject", []string{projectID})
 		},
 	}
 
@@ -21,7 +21,7 @@ func NewDeletePruneImagesCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deletePruneImages", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deletePruneImages", nil)
 		},
 	}
 
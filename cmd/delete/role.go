@@ -24,7 +24,7 @@ func NewDeleteRoleCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(roleID) {
-				id, err := utils.ResolveID(cfg, "role", roleID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "role", roleID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteRoleCmd() *cobra.Command {
 				roleID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteRole", []string{roleID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteRole", []string{roleID})
 		},
 	}
 
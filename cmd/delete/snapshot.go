@@ -25,7 +25,7 @@ func NewDeleteSnapshotCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -38,7 +38,7 @@ func NewDeleteSnapshotCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteSnapshot", []string{projectID, snapshotID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteSnapshot", []string{projectID, snapshotID})
 		},
 	}
 
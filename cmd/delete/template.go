@@ -24,7 +24,7 @@ func NewDeleteTemplateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(templateID) {
-				id, err := utils.ResolveID(cfg, "template", templateID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "template", templateID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteTemplateCmd() *cobra.Command {
 				templateID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteTemplate", []string{templateID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteTemplate", []string{templateID})
 		},
 	}
 
@@ -24,7 +24,7 @@ func NewDeleteUserCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(userID) {
-				id, err := utils.ResolveID(cfg, "user", userID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "user", userID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -32,7 +32,7 @@ func NewDeleteUserCmd() *cobra.Command {
 				userID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteUser", []string{userID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteUser", []string{userID})
 		},
 	}
 
@@ -25,7 +25,7 @@ func NewDeleteUserFromGroupCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(groupID) {
-				id, err := utils.ResolveID(cfg, "group", groupID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "group", groupID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -34,7 +34,7 @@ func NewDeleteUserFromGroupCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(userID) {
-				id, err := utils.ResolveID(cfg, "user", userID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "user", userID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -42,7 +42,7 @@ func NewDeleteUserFromGroupCmd() *cobra.Command {
 				userID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "deleteUserFromGroup", []string{groupID, userID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "deleteUserFromGroup", []string{groupID, userID})
 		},
 	}
 
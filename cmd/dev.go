@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/cmd/devcmd"
+)
+
+func NewDevCmdGroup() *cobra.Command {
+	devCmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Developer tools",
+		Long:  `Tools for developing and testing against gns3util without a real GNS3 server.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Dev commands do not talk to a server
+			return nil
+		},
+	}
+	devCmd.AddCommand(devcmd.NewFakeServerCmd())
+
+	return devCmd
+}
@@ -0,0 +1,57 @@
+package devcmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/colorUtils"
+)
+
+func NewFakeServerCmd() *cobra.Command {
+	var (
+		host          string
+		port          int
+		adminUser     string
+		adminPassword string
+		version       string
+	)
+	cmd := &cobra.Command{
+		Use:   "fake-server",
+		Short: "Run an in-memory fake GNS3v3 controller",
+		Long: `Run an in-memory fake GNS3v3 controller for local testing.
+
+The fake controller implements the /v3 endpoints gns3util uses (authentication,
+users, groups, roles, ACL, pools, projects, nodes, links, snapshots, ...) with
+state that only lives as long as the process. Stop it with Ctrl-C.`,
+		Example: `  gns3util dev fake-server --port 3080
+  gns3util -s http://127.0.0.1:3080 auth login -u admin -p admin`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srv := fakeserver.New(fakeserver.Options{
+				AdminUser:     adminUser,
+				AdminPassword: adminPassword,
+				Version:       version,
+			})
+			addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+			fmt.Printf("%s %s\n", colorUtils.Info("Fake GNS3 controller listening on"), colorUtils.Highlight("http://"+addr))
+			fmt.Printf("%s gns3util -s http://%s auth login -u %s -p %s\n", colorUtils.Info("Log in with:"), addr, adminUser, adminPassword)
+
+			if err := fakeserver.ListenAndServe(cmd.Context(), addr, srv); err != nil {
+				return fmt.Errorf("fake server failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "127.0.0.1", "Address to listen on")
+	cmd.Flags().IntVar(&port, "port", 3080, "Port to listen on")
+	cmd.Flags().StringVar(&adminUser, "admin-user", fakeserver.DefaultAdminUser, "Username of the built-in admin account")
+	cmd.Flags().StringVar(&adminPassword, "admin-password", fakeserver.DefaultAdminPassword, "Password of the built-in admin account")
+	cmd.Flags().StringVar(&version, "server-version", fakeserver.DefaultVersion, "Version reported by /v3/version")
+
+	return cmd
+}
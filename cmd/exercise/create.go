@@ -2,7 +2,9 @@ package exercise
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -70,7 +72,7 @@ This command will:
 	return createExerciseCmd
 }
 
-func selectAndReplicateTemplateAcrossCluster(cfg config.GlobalOptions, clusterID int) (map[string]string, error) {
+func selectAndReplicateTemplateAcrossCluster(ctx context.Context, cfg config.GlobalOptions, clusterID int) (map[string]string, error) {
 	conn, err := db.InitIfNeeded()
 	if err != nil {
 		return nil, fmt.Errorf("init db: %w", err)
@@ -96,7 +98,7 @@ func selectAndReplicateTemplateAcrossCluster(cfg config.GlobalOptions, clusterID
 		cfgServer := cfg
 		cfgServer.Server = nodeURL
 
-		body, status, err := utils.CallClient(cfgServer, "getProjects", []string{}, nil)
+		body, status, err := utils.CallClient(ctx, cfgServer, "getProjects", []string{}, nil)
 		if err != nil {
 			return nil, fmt.Errorf("[%s] getProjects: %w", nodeURL, err)
 		}
@@ -151,7 +153,7 @@ func selectAndReplicateTemplateAcrossCluster(cfg config.GlobalOptions, clusterID
 
 	srcCfg := cfg
 	srcCfg.Server = srcURL
-	exportData, err := exportProjectArchive(srcCfg, srcProjID)
+	exportData, err := exportProjectArchive(ctx, srcCfg, srcProjID)
 	if err != nil {
 		return nil, fmt.Errorf("export from %s: %w", srcURL, err)
 	}
@@ -164,7 +166,7 @@ func selectAndReplicateTemplateAcrossCluster(cfg config.GlobalOptions, clusterID
 			result[nodeURL] = id
 			continue
 		}
-		newID, err := importProjectArchive(tgtCfg, exportData, selName)
+		newID, err := importProjectArchive(ctx, tgtCfg, exportData, selName)
 		if err != nil {
 			return nil, fmt.Errorf("import to %s failed: %w", nodeURL, err)
 		}
@@ -175,8 +177,8 @@ func selectAndReplicateTemplateAcrossCluster(cfg config.GlobalOptions, clusterID
 	return result, nil
 }
 
-func exportProjectArchive(cfg config.GlobalOptions, projectID string) ([]byte, error) {
-	body, status, err := utils.CallClient(cfg, "exportProject", []string{projectID}, nil)
+func exportProjectArchive(ctx context.Context, cfg config.GlobalOptions, projectID string) ([]byte, error) {
+	body, status, err := utils.CallClient(ctx, cfg, "exportProject", []string{projectID}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("export project: %w", err)
 	}
@@ -186,12 +188,12 @@ func exportProjectArchive(cfg config.GlobalOptions, projectID string) ([]byte, e
 	return body, nil
 }
 
-func importProjectArchive(cfg config.GlobalOptions, archive []byte, projectName string) (string, error) {
+func importProjectArchive(ctx context.Context, cfg config.GlobalOptions, archive []byte, projectName string) (string, error) {
 	token, err := authentication.GetKeyForServer(cfg)
 	if err != nil {
 		return "", fmt.Errorf("get token: %w", err)
 	}
-	settings := api.NewSettings(api.WithBaseURL(cfg.Server), api.WithVerify(cfg.Insecure), api.WithToken(token))
+	settings := config.APISettings(cfg, token)
 	client := api.NewGNS3Client(settings)
 
 	ep := endpoints.Endpoints{}
@@ -209,7 +211,7 @@ func importProjectArchive(cfg config.GlobalOptions, archive []byte, projectName
 	}
 	_ = w.Close()
 
-	req := api.NewRequestOptions(settings).WithURL(urlStr).WithMethod(api.POST).WithData(buf.String())
+	req := api.NewRequestOptions(settings).WithContext(ctx).WithURL(urlStr).WithMethod(api.POST).WithData(buf.String())
 	_, resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -273,7 +275,7 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 
 		var templateIDByNode map[string]string
 		if selectTemplate {
-			templateIDByNode, err = selectAndReplicateTemplateAcrossCluster(cfg, clusterID)
+			templateIDByNode, err = selectAndReplicateTemplateAcrossCluster(cmd.Context(), cfg, clusterID)
 			if err != nil {
 				return fmt.Errorf("template selection/replication failed: %w", err)
 			}
@@ -287,7 +289,7 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 			cfgServer := cfg
 			cfgServer.Server = plan.NodeURL
 
-			groupsBody, status, err := utils.CallClient(cfgServer, "getGroups", []string{}, nil)
+			groupsBody, status, err := utils.CallClient(cmd.Context(), cfgServer, "getGroups", []string{}, nil)
 			if err != nil {
 				return fmt.Errorf("[%s] getGroups: %w", plan.NodeURL, err)
 			}
@@ -328,14 +330,17 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 			if selectTemplate {
 				preselectedTemplate = templateIDByNode[plan.NodeURL]
 			}
-			_, created, err := createForGroupsOnServer(
+			_, created, err := createForGroupsOnServer(cmd.Context(),
 				cfgServer, className, exerciseName, format, templatePath,
 				selectTemplate, deleteTemplate, classGroups, preselectedTemplate,
 			)
+			totalCreated += created
 			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return fmt.Errorf("%w (%d projects created across the cluster)", err, totalCreated)
+				}
 				return err
 			}
-			totalCreated += created
 		}
 
 		fmt.Printf("\n%v Created %d projects for exercise '%s' across cluster %s\n",
@@ -350,7 +355,7 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName, format, templatePath string, selectTemplate, deleteTemplate bool, classGroups []schemas.UserGroupResponse, preselectedTemplateID string) (string, int, error) {
+func createForGroupsOnServer(ctx context.Context, cfg config.GlobalOptions, className, exerciseName, format, templatePath string, selectTemplate, deleteTemplate bool, classGroups []schemas.UserGroupResponse, preselectedTemplateID string) (string, int, error) {
 	fmt.Printf("%v Found %d groups for class %v on %s\n",
 		messageUtils.InfoMsg("Found groups for class"),
 		len(classGroups),
@@ -361,7 +366,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 		fmt.Printf("  - %v\n", messageUtils.Highlight(group.Name))
 	}
 
-	roleID, err := getUserRoleID(cfg)
+	roleID, err := getUserRoleID(ctx, cfg)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to get User role ID: %w", err)
 	}
@@ -370,7 +375,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 	if preselectedTemplateID != "" {
 		templateProjectID = preselectedTemplateID
 	} else if selectTemplate {
-		templateProjectID, err = selectTemplateWithFuzzy(cfg)
+		templateProjectID, err = selectTemplateWithFuzzy(ctx, cfg)
 		if err != nil {
 			return "", 0, fmt.Errorf("failed to select template project: %w", err)
 		}
@@ -379,7 +384,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 			messageUtils.Bold(templateProjectID))
 	} else if templatePath != "" {
 		if _, err := os.Stat(templatePath); err == nil {
-			templateProjectID, err = importTemplateProject(cfg, templatePath, className, exerciseName)
+			templateProjectID, err = importTemplateProject(ctx, cfg, templatePath, className, exerciseName)
 			if err != nil {
 				return "", 0, fmt.Errorf("failed to import template project: %w", err)
 			}
@@ -387,7 +392,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 				messageUtils.SuccessMsg("Imported template project"),
 				messageUtils.Bold(templateProjectID))
 		} else {
-			templateProjectID, err = resolveTemplateProject(cfg, templatePath)
+			templateProjectID, err = resolveTemplateProject(ctx, cfg, templatePath)
 			if err != nil {
 				return "", 0, fmt.Errorf("failed to resolve template project: %w", err)
 			}
@@ -399,14 +404,14 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 
 	var exportData []byte
 	if templateProjectID != "" {
-		_, _, _ = utils.CallClient(cfg, "closeProject", []string{templateProjectID}, nil)
-		exportData, err = exportProjectArchive(cfg, templateProjectID)
+		_, _, _ = utils.CallClient(ctx, cfg, "closeProject", []string{templateProjectID}, nil)
+		exportData, err = exportProjectArchive(ctx, cfg, templateProjectID)
 		if err != nil {
 			return "", 0, fmt.Errorf("export template: %w", err)
 		}
 	}
 
-	existingExercises, err := checkExistingExercises(cfg, className, classGroups)
+	existingExercises, err := checkExistingExercises(ctx, cfg, className, classGroups)
 	if err != nil {
 		return templateProjectID, 0, fmt.Errorf("failed to check existing exercises: %w", err)
 	}
@@ -427,6 +432,10 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 
 	successCount := 0
 	for _, group := range classGroups {
+		if err := ctx.Err(); err != nil {
+			return templateProjectID, successCount, fmt.Errorf("exercise creation on %s interrupted after %d/%d projects: %w",
+				cfg.Server, successCount, len(classGroups), err)
+		}
 		groupName := group.Name
 		groupID := group.UserGroupID.String()
 		if slices.Contains(existingExercises, groupName) {
@@ -438,7 +447,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 
 		var projectID string
 		if len(exportData) > 0 {
-			projectID, err = importProjectArchive(cfg, exportData, projectName)
+			projectID, err = importProjectArchive(ctx, cfg, exportData, projectName)
 			if err != nil {
 				fmt.Printf("%v Failed to import template for %s on %s: %v\n",
 					messageUtils.ErrorMsg("Failed to import template"),
@@ -447,7 +456,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 			}
 		} else {
 			projectData := schemas.ProjectCreate{Name: &projectName}
-			body, status, err := utils.CallClient(cfg, "createProject", []string{}, projectData)
+			body, status, err := utils.CallClient(ctx, cfg, "createProject", []string{}, projectData)
 			if err != nil || status != 201 {
 				if err == nil {
 					err = fmt.Errorf("status %d", status)
@@ -465,7 +474,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 			}
 			projectID = pr.ProjectID
 		}
-		if _, status, err := utils.CallClient(cfg, "closeProject", []string{projectID}, nil); err != nil || (status != 200 && status != 204) {
+		if _, status, err := utils.CallClient(ctx, cfg, "closeProject", []string{projectID}, nil); err != nil || (status != 200 && status != 204) {
 			if err == nil {
 				err = fmt.Errorf("status %d", status)
 			}
@@ -475,7 +484,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 
 		poolName := fmt.Sprintf("%s-pool", projectName)
 		poolData := schemas.ResourcePoolCreate{Name: &poolName}
-		poolBody, status, err := utils.CallClient(cfg, "createPool", []string{}, poolData)
+		poolBody, status, err := utils.CallClient(ctx, cfg, "createPool", []string{}, poolData)
 		if err != nil || status != 201 {
 			if err == nil {
 				err = fmt.Errorf("status %d", status)
@@ -492,7 +501,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 		}
 		poolID := poolResp.ResourcePoolID
 
-		if _, status, err = utils.CallClient(cfg, "addToPool", []string{poolID, projectID}, nil); err != nil || (status != 201 && status != 204) {
+		if _, status, err = utils.CallClient(ctx, cfg, "addToPool", []string{poolID, projectID}, nil); err != nil || (status != 201 && status != 204) {
 			if err == nil {
 				err = fmt.Errorf("status %d", status)
 			}
@@ -513,7 +522,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 			GroupID:   &groupID,
 			RoleID:    &roleID,
 		}
-		if _, status, err = utils.CallClient(cfg, "createACL", []string{}, aclData); err != nil || (status != 201 && status != 204) {
+		if _, status, err = utils.CallClient(ctx, cfg, "createACL", []string{}, aclData); err != nil || (status != 201 && status != 204) {
 			if err == nil {
 				err = fmt.Errorf("status %d", status)
 			}
@@ -548,7 +557,7 @@ func createForGroupsOnServer(cfg config.GlobalOptions, className, exerciseName,
 	}
 
 	if templateProjectID != "" && deleteTemplate {
-		if err := cleanupTemplateProject(cfg, templateProjectID, deleteTemplate); err != nil {
+		if err := cleanupTemplateProject(ctx, cfg, templateProjectID, deleteTemplate); err != nil {
 			fmt.Printf("%v Failed to clean up template project on %s: %v\n",
 				messageUtils.WarningMsg("Failed to clean up template project"), messageUtils.Highlight(cfg.Server), err)
 		} else {
@@ -580,8 +589,8 @@ func generateProjectName(format, className, exerciseName, groupNumber string) st
 	return projectName
 }
 
-func getUserRoleID(cfg config.GlobalOptions) (string, error) {
-	rolesBody, status, err := utils.CallClient(cfg, "getRoles", []string{}, nil)
+func getUserRoleID(ctx context.Context, cfg config.GlobalOptions) (string, error) {
+	rolesBody, status, err := utils.CallClient(ctx, cfg, "getRoles", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get roles: %w", err)
 	}
@@ -604,8 +613,8 @@ func getUserRoleID(cfg config.GlobalOptions) (string, error) {
 	return "", fmt.Errorf("user role not found")
 }
 
-func checkExistingExercises(cfg config.GlobalOptions, className string, classGroups []schemas.UserGroupResponse) ([]string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func checkExistingExercises(ctx context.Context, cfg config.GlobalOptions, className string, classGroups []schemas.UserGroupResponse) ([]string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -638,8 +647,8 @@ func checkExistingExercises(cfg config.GlobalOptions, className string, classGro
 	return existingExercises, nil
 }
 
-func selectTemplateWithFuzzy(cfg config.GlobalOptions) (string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func selectTemplateWithFuzzy(ctx context.Context, cfg config.GlobalOptions) (string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -685,7 +694,7 @@ func selectTemplateWithFuzzy(cfg config.GlobalOptions) (string, error) {
 	return projectMap[selected], nil
 }
 
-func importTemplateProject(cfg config.GlobalOptions, filePath, className, exerciseName string) (string, error) {
+func importTemplateProject(ctx context.Context, cfg config.GlobalOptions, filePath, className, exerciseName string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to open template file: %w", err)
@@ -712,7 +721,7 @@ func importTemplateProject(cfg config.GlobalOptions, filePath, className, exerci
 		return "", fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	resp, status, err := utils.CallClient(cfg, "postProject", []string{}, body)
+	resp, status, err := utils.CallClient(ctx, cfg, "postProject", []string{}, body)
 	if err != nil {
 		return "", fmt.Errorf("failed to import project: %w", err)
 	}
@@ -731,13 +740,13 @@ func importTemplateProject(cfg config.GlobalOptions, filePath, className, exerci
 	return project.ProjectID, nil
 }
 
-func resolveTemplateProject(cfg config.GlobalOptions, projectRef string) (string, error) {
-	_, status, err := utils.CallClient(cfg, "getProject", []string{projectRef}, nil)
+func resolveTemplateProject(ctx context.Context, cfg config.GlobalOptions, projectRef string) (string, error) {
+	_, status, err := utils.CallClient(ctx, cfg, "getProject", []string{projectRef}, nil)
 	if err == nil && status == 200 {
 		return projectRef, nil
 	}
 
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -760,8 +769,8 @@ func resolveTemplateProject(cfg config.GlobalOptions, projectRef string) (string
 	return "", fmt.Errorf("template project not found: %s", projectRef)
 }
 
-func cleanupTemplateProject(cfg config.GlobalOptions, projectID string, deleteTemplate bool) error {
-	_, status, err := utils.CallClient(cfg, "closeProject", []string{projectID}, nil)
+func cleanupTemplateProject(ctx context.Context, cfg config.GlobalOptions, projectID string, deleteTemplate bool) error {
+	_, status, err := utils.CallClient(ctx, cfg, "closeProject", []string{projectID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to close project: %w", err)
 	}
@@ -771,7 +780,7 @@ func cleanupTemplateProject(cfg config.GlobalOptions, projectID string, deleteTe
 	}
 
 	if deleteTemplate {
-		_, status, err = utils.CallClient(cfg, "deleteProject", []string{projectID}, nil)
+		_, status, err = utils.CallClient(ctx, cfg, "deleteProject", []string{projectID}, nil)
 		if err != nil {
 			return fmt.Errorf("failed to delete project: %w", err)
 		}
@@ -783,3 +792,7 @@ func cleanupTemplateProject(cfg config.GlobalOptions, projectID string, deleteTe
 
 	return nil
 }
+
+func init() {
+	utils.RegisterRoutes("exercise create", endpoints.Route{Method: string(api.POST), Path: endpoints.Endpoints{}.Post.ProjectImport("{}")})
+}
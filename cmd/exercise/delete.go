@@ -1,6 +1,7 @@
 package exercise
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -76,7 +77,7 @@ func NewExerciseDeleteCmd() *cobra.Command {
 	return deleteExerciseCmd
 }
 
-func deleteExerciseInCluster(cfg config.GlobalOptions, clusterName, exerciseName, className, groupName string, confirm bool) error {
+func deleteExerciseInCluster(ctx context.Context, cfg config.GlobalOptions, clusterName, exerciseName, className, groupName string, confirm bool) error {
 	if confirm {
 		msg := fmt.Sprintf("Delete exercise '%s' across cluster '%s'?", exerciseName, clusterName)
 		if className != "" {
@@ -125,9 +126,12 @@ func deleteExerciseInCluster(cfg config.GlobalOptions, clusterName, exerciseName
 		if n.ClusterID != clusterID {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		nodeCfg := cfg
 		nodeCfg.Server = fmt.Sprintf("%s://%s:%d", n.Protocol, n.Host, n.Port)
-		err := deleteExerciseWithConfirmation(nodeCfg, exerciseName, className, groupName, false)
+		err := deleteExerciseWithConfirmation(ctx, nodeCfg, exerciseName, className, groupName, false)
 		if errors.Is(err, class.ErrExerciseNotFound) {
 			fmt.Printf("%v Exercise %v not present on %s; skipping.\n",
 				messageUtils.WarningMsg("Warning"),
@@ -145,7 +149,7 @@ func deleteExerciseInCluster(cfg config.GlobalOptions, clusterName, exerciseName
 	return nil
 }
 
-func getAllExerciseNamesFromCluster(cfg config.GlobalOptions, clusterName string) ([]string, error) {
+func getAllExerciseNamesFromCluster(ctx context.Context, cfg config.GlobalOptions, clusterName string) ([]string, error) {
 	conn, err := db.InitIfNeeded()
 	if err != nil {
 		return nil, fmt.Errorf("failed to init db: %w", err)
@@ -183,7 +187,7 @@ func getAllExerciseNamesFromCluster(cfg config.GlobalOptions, clusterName string
 		}
 		nodeCfg := cfg
 		nodeCfg.Server = fmt.Sprintf("%s://%s:%d", n.Protocol, n.Host, n.Port)
-		names, err := getAllExerciseNames(nodeCfg)
+		names, err := getAllExerciseNames(ctx, nodeCfg)
 		if err != nil {
 			continue
 		}
@@ -197,8 +201,8 @@ func getAllExerciseNamesFromCluster(cfg config.GlobalOptions, clusterName string
 	return out, nil
 }
 
-func selectExercisesWithFuzzyFromCluster(cfg config.GlobalOptions, clusterName string, multi bool) ([]string, error) {
-	names, err := getAllExerciseNamesFromCluster(cfg, clusterName)
+func selectExercisesWithFuzzyFromCluster(ctx context.Context, cfg config.GlobalOptions, clusterName string, multi bool) ([]string, error) {
+	names, err := getAllExerciseNamesFromCluster(ctx, cfg, clusterName)
 	if err != nil {
 		return nil, err
 	}
@@ -256,7 +260,7 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 		}
 
 		if selectClass {
-			selectedClass, err := selectClassWithFuzzy(cfg)
+			selectedClass, err := selectClassWithFuzzy(cmd.Context(), cfg)
 			if err != nil {
 				return fmt.Errorf("failed to select class: %w", err)
 			}
@@ -271,7 +275,7 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 			if className == "" {
 				return fmt.Errorf("must select a class before selecting a group")
 			}
-			selectedGroup, err := selectGroupWithFuzzy(cfg, className)
+			selectedGroup, err := selectGroupWithFuzzy(cmd.Context(), cfg, className)
 			if err != nil {
 				return fmt.Errorf("failed to select group: %w", err)
 			}
@@ -303,11 +307,11 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 
 	if targetExerciseName != "" {
 		if clusterName != "" {
-			if err := deleteExerciseInCluster(cfg, clusterName, targetExerciseName, className, groupName, confirm); err != nil {
+			if err := deleteExerciseInCluster(cmd.Context(), cfg, clusterName, targetExerciseName, className, groupName, confirm); err != nil {
 				return fmt.Errorf("failed to delete exercise: %w", err)
 			}
 		} else {
-			if err := deleteExerciseWithConfirmation(cfg, targetExerciseName, className, groupName, confirm); err != nil {
+			if err := deleteExerciseWithConfirmation(cmd.Context(), cfg, targetExerciseName, className, groupName, confirm); err != nil {
 				return fmt.Errorf("failed to delete exercise: %w", err)
 			}
 		}
@@ -315,9 +319,9 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 		var exerciseNames []string
 		var err error
 		if clusterName != "" {
-			exerciseNames, err = getAllExerciseNamesFromCluster(cfg, clusterName)
+			exerciseNames, err = getAllExerciseNamesFromCluster(cmd.Context(), cfg, clusterName)
 		} else {
-			exerciseNames, err = getAllExerciseNames(cfg)
+			exerciseNames, err = getAllExerciseNames(cmd.Context(), cfg)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to get exercise names: %w", err)
@@ -363,12 +367,15 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		for _, name := range exerciseNames {
+		for i, name := range exerciseNames {
 			var derr error
 			if clusterName != "" {
-				derr = deleteExerciseInCluster(cfg, clusterName, name, className, groupName, false)
+				derr = deleteExerciseInCluster(cmd.Context(), cfg, clusterName, name, className, groupName, false)
 			} else {
-				derr = deleteExerciseWithConfirmation(cfg, name, className, groupName, false)
+				derr = deleteExerciseWithConfirmation(cmd.Context(), cfg, name, className, groupName, false)
+			}
+			if errors.Is(derr, context.Canceled) {
+				return fmt.Errorf("deletion interrupted after %d/%d exercisees: %w", i, len(exerciseNames), derr)
 			}
 			if derr != nil {
 				fmt.Printf("%v Failed to delete exercise %v: %v\n",
@@ -378,16 +385,16 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else if className != "" {
-		if err := deleteAllExercisesForClassWithConfirmation(cfg, className, confirm); err != nil {
+		if err := deleteAllExercisesForClassWithConfirmation(cmd.Context(), cfg, className, confirm); err != nil {
 			return fmt.Errorf("failed to delete exercises for class: %w", err)
 		}
 	} else if selectExercise || (!selectClass && !selectGroup) {
 		var exerciseNames []string
 		var err error
 		if clusterName != "" {
-			exerciseNames, err = selectExercisesWithFuzzyFromCluster(cfg, clusterName, multi)
+			exerciseNames, err = selectExercisesWithFuzzyFromCluster(cmd.Context(), cfg, clusterName, multi)
 		} else {
-			exerciseNames, err = selectExercisesWithFuzzy(cfg, multi)
+			exerciseNames, err = selectExercisesWithFuzzy(cmd.Context(), cfg, multi)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to select exercises: %w", err)
@@ -398,12 +405,15 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		for _, name := range exerciseNames {
+		for i, name := range exerciseNames {
 			var derr error
 			if clusterName != "" {
-				derr = deleteExerciseInCluster(cfg, clusterName, name, className, groupName, confirm)
+				derr = deleteExerciseInCluster(cmd.Context(), cfg, clusterName, name, className, groupName, confirm)
 			} else {
-				derr = deleteExerciseWithConfirmation(cfg, name, className, groupName, confirm)
+				derr = deleteExerciseWithConfirmation(cmd.Context(), cfg, name, className, groupName, confirm)
+			}
+			if errors.Is(derr, context.Canceled) {
+				return fmt.Errorf("deletion interrupted after %d/%d exercisees: %w", i, len(exerciseNames), derr)
 			}
 			if derr != nil {
 				fmt.Printf("%v Failed to delete exercise %v: %v\n",
@@ -414,7 +424,7 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 		}
 	} else {
 		if className != "" {
-			if err := deleteAllExercisesForClassWithConfirmation(cfg, className, confirm); err != nil {
+			if err := deleteAllExercisesForClassWithConfirmation(cmd.Context(), cfg, className, confirm); err != nil {
 				return fmt.Errorf("failed to delete exercises for class: %w", err)
 			}
 		} else {
@@ -425,8 +435,8 @@ func runDeleteExercise(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func selectExercisesWithFuzzy(cfg config.GlobalOptions, multi bool) ([]string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func selectExercisesWithFuzzy(ctx context.Context, cfg config.GlobalOptions, multi bool) ([]string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -465,8 +475,8 @@ func selectExercisesWithFuzzy(cfg config.GlobalOptions, multi bool) ([]string, e
 	return finder, nil
 }
 
-func getAllExerciseNames(cfg config.GlobalOptions) ([]string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func getAllExerciseNames(ctx context.Context, cfg config.GlobalOptions) ([]string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -496,7 +506,7 @@ func getAllExerciseNames(cfg config.GlobalOptions) ([]string, error) {
 	return exerciseNames, nil
 }
 
-func deleteExerciseWithConfirmation(cfg config.GlobalOptions, exerciseName, className, groupName string, confirm bool) error {
+func deleteExerciseWithConfirmation(ctx context.Context, cfg config.GlobalOptions, exerciseName, className, groupName string, confirm bool) error {
 	if confirm {
 		message := fmt.Sprintf("Delete exercise '%s'?", exerciseName)
 		if className != "" {
@@ -512,7 +522,7 @@ func deleteExerciseWithConfirmation(cfg config.GlobalOptions, exerciseName, clas
 		}
 	}
 
-	if err := class.DeleteExercise(cfg, exerciseName, className, groupName); err != nil {
+	if err := class.DeleteExercise(ctx, cfg, exerciseName, className, groupName); err != nil {
 		if errors.Is(err, class.ErrExerciseNotFound) {
 			fmt.Printf("%v Exercise %v not found on %s; skipping.\n",
 				messageUtils.WarningMsg("Warning"),
@@ -527,7 +537,7 @@ func deleteExerciseWithConfirmation(cfg config.GlobalOptions, exerciseName, clas
 	return nil
 }
 
-func deleteAllExercisesForClassWithConfirmation(cfg config.GlobalOptions, className string, confirm bool) error {
+func deleteAllExercisesForClassWithConfirmation(ctx context.Context, cfg config.GlobalOptions, className string, confirm bool) error {
 	if confirm {
 		if !utils.ConfirmPrompt(fmt.Sprintf("Delete all exercises for class '%s'?", className), false) {
 			fmt.Println("Deletion cancelled.")
@@ -549,11 +559,11 @@ func deleteAllExercisesForClassWithConfirmation(cfg config.GlobalOptions, classN
 		}
 	}
 
-	return class.DeleteAllExercisesForClass(cfg, className)
+	return class.DeleteAllExercisesForClass(ctx, cfg, className)
 }
 
-func selectClassWithFuzzy(cfg config.GlobalOptions) (string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func selectClassWithFuzzy(ctx context.Context, cfg config.GlobalOptions) (string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -593,8 +603,8 @@ func selectClassWithFuzzy(cfg config.GlobalOptions) (string, error) {
 	return selected[0], nil
 }
 
-func selectGroupWithFuzzy(cfg config.GlobalOptions, className string) (string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func selectGroupWithFuzzy(ctx context.Context, cfg config.GlobalOptions, className string) (string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get projects: %w", err)
 	}
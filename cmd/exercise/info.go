@@ -1,6 +1,7 @@
 package exercise
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/fuzzy"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
 	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
 )
 
@@ -19,6 +21,7 @@ func NewExerciseInfoCmd() *cobra.Command {
 		RunE:  runExerciseInfo,
 	}
 	cmd.Flags().StringP("cluster", "c", "", "Cluster name")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -72,6 +75,27 @@ func runExerciseInfo(cmd *cobra.Command, args []string) error {
 	}
 	target := picked[0]
 
+	if cfg.Output != "" {
+		type infoRow struct {
+			Node string `json:"node"`
+			db.ExerciseItem
+		}
+		rows := []infoRow{}
+		for _, n := range nodes {
+			for _, it := range n.Exercises {
+				if it.Name == target {
+					rows = append(rows, infoRow{Node: n.NodeURL, ExerciseItem: it})
+				}
+			}
+		}
+		body, err := json.Marshal(rows)
+		if err != nil {
+			return fmt.Errorf("failed to marshall the results: %w", err)
+		}
+		utils.PrintOutput(cfg, "", body)
+		return nil
+	}
+
 	fmt.Printf("%s %s\n", messageUtils.Bold("Exercise:"), messageUtils.Highlight(target))
 	fmt.Println(strings.Repeat("-", 69))
 	for _, n := range nodes {
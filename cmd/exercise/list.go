@@ -1,6 +1,7 @@
 package exercise
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -22,6 +23,8 @@ func NewExerciseLsCmd() *cobra.Command {
 	cmd.Flags().Bool("api-only", false, "Use only API for listing (not implemented)")
 	cmd.Flags().StringP("cluster", "c", "", "Cluster name")
 	cmd.Flags().String("class", "", "Filter by class name")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -53,6 +56,13 @@ func runExerciseLs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get exercise distribution: %w", err)
 	}
 
+	if cfg.Output != "" || cfg.List.Active() {
+		if cfg.Output == "" && cfg.List.Query == "" {
+			cfg.Output = "table"
+		}
+		return printExercisesOutput(cfg, nodes)
+	}
+
 	type row struct{ Node, Exercises, Projects, Groups string }
 	rows := make([]row, 0, len(nodes))
 	for _, n := range nodes {
@@ -156,3 +166,42 @@ func resolveExerciseClusterID(cfg config.GlobalOptions, clusterName string) (int
 	}
 	return 0, fmt.Errorf("cluster not found: %s", derived)
 }
+
+type exerciseRow struct {
+	Node      string            `json:"node"`
+	Exercises int               `json:"exercises"`
+	Projects  int               `json:"projects"`
+	Groups    int               `json:"groups"`
+	Items     []db.ExerciseItem `json:"items"`
+}
+
+func printExercisesOutput(cfg config.GlobalOptions, nodes []db.NodeExercisesForClass) error {
+	rows := make([]exerciseRow, 0, len(nodes))
+	for _, n := range nodes {
+		exNames := make(map[string]bool)
+		projects := make(map[string]bool)
+		groups := make(map[string]bool)
+		for _, it := range n.Exercises {
+			exNames[it.Name] = true
+			projects[it.ProjectUUID] = true
+			groups[it.GroupName] = true
+		}
+		items := n.Exercises
+		if items == nil {
+			items = []db.ExerciseItem{}
+		}
+		rows = append(rows, exerciseRow{
+			Node:      n.NodeURL,
+			Exercises: len(exNames),
+			Projects:  len(projects),
+			Groups:    len(groups),
+			Items:     items,
+		})
+	}
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("failed to marshall the results: %w", err)
+	}
+	utils.PrintOutput(cfg, "exercise", body)
+	return nil
+}
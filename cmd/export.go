@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/access"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewExportCmdGroup() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export server state as manifests",
+		Long:  `Export the state of the server in the manifest formats that apply reads.`,
+	}
+
+	exportCmd.AddCommand(NewExportAccessCmd())
+
+	return exportCmd
+}
+
+func NewExportAccessCmd() *cobra.Command {
+	var file string
+	var cmd = &cobra.Command{
+		Use:   "access",
+		Short: "Export users, groups, roles, pools and ACL as a manifest",
+		Long: `Write the users, groups, roles, pools and ACL of the server as a manifest
+for "gns3util apply". Built-in roles are left out, passwords can not be
+exported.`,
+		Example: `  gns3util -s https://controller:3080 export access > access.yaml
+  gns3util -s https://controller:3080 export access -f access.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			client, err := sdk.NewFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			m, err := access.Export(cmd.Context(), client)
+			if err != nil {
+				return fmt.Errorf("failed to export access control: %w", err)
+			}
+			data, err := m.Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to encode manifest: %w", err)
+			}
+			if file == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			if err := os.WriteFile(file, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+			fmt.Printf("%v manifest written to %s\n", messageUtils.SuccessMsg("Exported"), messageUtils.Highlight(file))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the manifest to a file instead of stdout")
+	return cmd
+}
+
+// accessReadRoutes are the routes pkg/access reads the current state from.
+func accessReadRoutes() []endpoints.Route {
+	ep := endpoints.Endpoints{}
+	return []endpoints.Route{
+		{Method: string(api.GET), Path: ep.Get.Me()},
+		{Method: string(api.GET), Path: ep.Get.Users()},
+		{Method: string(api.GET), Path: ep.Get.Groups()},
+		{Method: string(api.GET), Path: ep.Get.GroupMembers("{}")},
+		{Method: string(api.GET), Path: ep.Get.GetPrivileges()},
+		{Method: string(api.GET), Path: ep.Get.Roles()},
+		{Method: string(api.GET), Path: ep.Get.RolePrivs("{}")},
+		{Method: string(api.GET), Path: ep.Get.Projects()},
+		{Method: string(api.GET), Path: ep.Get.Pools()},
+		{Method: string(api.GET), Path: ep.Get.PoolResources("{}")},
+		{Method: string(api.GET), Path: ep.Get.ACL()},
+	}
+}
+
+func init() {
+	utils.RegisterRoutes("export access", accessReadRoutes()...)
+}
@@ -19,9 +19,11 @@ func NewGetAclCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getAcl", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getAcl", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -38,9 +40,10 @@ func NewGetAceCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getAce", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getAce", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -55,8 +58,9 @@ func NewGetAclEndpointsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getAclEndpoints", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getAclEndpoints", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
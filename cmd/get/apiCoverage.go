@@ -0,0 +1,78 @@
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewGetAPICoverageCmd() *cobra.Command {
+	var specFile string
+	var cmd = &cobra.Command{
+		Use:   "api-coverage",
+		Short: "Report controller API operations gns3util does not cover",
+		Long: `Compare the controller's OpenAPI document with gns3util and list the
+operations without an endpoint method or CLI command, and the request schemas
+whose fields are missing from pkg/api/schemas.`,
+		Example: `  gns3util -s https://controller:3080 system api-coverage
+  gns3util system api-coverage --file openapi.json --raw`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.ApplyContext(cmd); err != nil {
+				return err
+			}
+			server, _ := cmd.Flags().GetString("server")
+			if server != "" {
+				return cmd.Root().PersistentPreRunE(cmd, args)
+			}
+			if specFile == "" {
+				return fmt.Errorf("either --server or --file must be specified")
+			}
+			// A local document needs no server, only the output flags.
+			raw, _ := cmd.Flags().GetBool("raw")
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			cmd.SetContext(config.WithGlobalOptions(cmd.Context(), config.GlobalOptions{Raw: raw, NoColors: noColor}))
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				fmt.Printf("failed to get global options: %v\n", err)
+				return
+			}
+
+			var spec []byte
+			if specFile != "" {
+				spec, err = os.ReadFile(specFile)
+			} else {
+				spec, err = utils.FetchOpenAPI(cmd.Context(), cfg)
+			}
+			if err != nil {
+				fmt.Printf("%v failed to get the OpenAPI document: %v\n", messageUtils.ErrorMsg("Error"), err)
+				return
+			}
+
+			report, err := utils.BuildCoverageReport(spec)
+			if err != nil {
+				fmt.Printf("%v %v\n", messageUtils.ErrorMsg("Error"), err)
+				return
+			}
+			if cfg.Raw {
+				body, _ := json.Marshal(report)
+				if cfg.NoColors {
+					utils.PrintJsonUgly(body)
+				} else {
+					utils.PrintJson(body)
+				}
+				return
+			}
+			utils.PrintCoverageReport(report)
+		},
+	}
+	cmd.Flags().StringVarP(&specFile, "file", "f", "", "Read the OpenAPI document from this file instead of the server")
+	return cmd
+}
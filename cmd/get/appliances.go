@@ -20,9 +20,11 @@ func NewGetAppliancesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getAppliances", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getAppliances", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -59,7 +61,7 @@ func NewGetApplianceCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getAppliances", "name", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -68,17 +70,18 @@ func NewGetApplianceCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "appliance", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "appliance", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getAppliance", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getAppliance", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find an appliance")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple appliances")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
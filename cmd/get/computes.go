@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
 )
@@ -19,9 +20,19 @@ func NewGetComputesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getComputes", nil)
+			if watch, interval := utils.WatchFlags(cmd); watch {
+				w := utils.Watch{CmdName: "getComputes", StreamURL: endpoints.GetEndpoints{}.Notifications(), Interval: interval}
+				if err := utils.WatchList(cmd.Context(), cfg, w); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getComputes", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
+	utils.AddWatchFlags(cmd)
 	return cmd
 }
 
@@ -39,15 +50,16 @@ func NewGetComputeCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "compute", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "compute", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getCompute", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getCompute", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -65,15 +77,16 @@ func NewGetComputeDockerImagesCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "compute", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "compute", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getComputeDockerImgs", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getComputeDockerImgs", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -91,15 +104,16 @@ func NewGetComputeVirtualboxVMSCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "compute", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "compute", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getVirtualboxVms", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getVirtualboxVms", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -117,14 +131,15 @@ func NewGetComputeVmWareVMSCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "compute", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "compute", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getVmwareVms", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getVmwareVms", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
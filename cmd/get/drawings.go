@@ -42,34 +42,36 @@ func NewGetDrawingsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", multi, "project", "Project:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				projectDrawings, err := utils.GetResourceWithContext(cfg, "getDrawings", ids, "project", "Project:")
+				projectDrawings, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getDrawings", ids, "project", "Project:")
 				if err != nil {
 					fmt.Printf("Error getting drawings: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(projectDrawings, "Project:")
+				utils.PrintResourceWithContext(cfg, "drawing", projectDrawings, "Project:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "project", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getDrawings", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getDrawings", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple projects")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -88,15 +90,16 @@ func NewGetDrawingCmd() *cobra.Command {
 			projectID := args[0]
 			linkID := args[1]
 			if !utils.IsValidUUIDv4(args[0]) {
-				projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+				projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getDrawing", []string{projectID, linkID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getDrawing", []string{projectID, linkID})
 
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
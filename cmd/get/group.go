@@ -42,7 +42,7 @@ func NewGetGroupCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getGroups", "name", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -50,18 +50,19 @@ func NewGetGroupCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "group", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "group", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getGroup", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getGroup", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a group")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple groups")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -76,9 +77,11 @@ func NewGetGroupsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getGroups", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getGroups", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -115,33 +118,35 @@ func NewGetGroupMembersCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getGroups", "name", multi, "group", "Group:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				groupMembers, err := utils.GetResourceWithContext(cfg, "getGroupMembers", ids, "group", "Group:")
+				groupMembers, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getGroupMembers", ids, "group", "Group:")
 				if err != nil {
 					fmt.Printf("Error getting group members: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(groupMembers, "Group:")
+				utils.PrintResourceWithContext(cfg, "user", groupMembers, "Group:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(id) {
-					id, err = utils.ResolveID(cfg, "group", id, nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "group", id, nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getGroupMembers", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getGroupMembers", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a group")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple groups")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
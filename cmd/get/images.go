@@ -29,10 +29,12 @@ func NewGetImagesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getImages", []string{imageType})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getImages", []string{imageType})
 		},
 	}
 	cmd.Flags().StringVarP(&imageType, "image-type", "t", "", "What type of image to get (qemu/ios/iou)")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -72,7 +74,7 @@ func NewGetImageCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if useFuzzy {
-				rawData, _, err := utils.CallClient(cfg, "getImages", []string{imageType}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getImages", []string{imageType}, nil)
 				if err != nil {
 					fmt.Printf("Error getting images: %v\n", err)
 					return
@@ -123,20 +125,16 @@ func NewGetImageCmd() *cobra.Command {
 				}
 				buf.WriteByte(']')
 
-				toPrint := buf.Bytes()
-				if cfg.Raw {
-					utils.PrintJson(toPrint)
-				} else {
-					utils.PrintKV(toPrint)
-				}
+				utils.PrintOutput(cfg, "image", buf.Bytes())
 			} else {
 				path := args[0]
-				utils.ExecuteAndPrint(cfg, "getImage", []string{path})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getImage", []string{path})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find an image")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple images")
 	cmd.Flags().StringVarP(&imageType, "image-type", "t", "", "What type of image to get (qemu/ios/iou)")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
@@ -19,8 +19,9 @@ func NewGetIouLicenseCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getIouLicense", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getIouLicense", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
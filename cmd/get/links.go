@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/fuzzy"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
@@ -34,6 +35,9 @@ func NewGetLinksCmd() *cobra.Command {
 			if multi && !useFuzzy {
 				return fmt.Errorf("the --multi (-m) flag can only be used together with --fuzzy (-f)")
 			}
+			if watch, _ := utils.WatchFlags(cmd); watch && multi {
+				return fmt.Errorf("--watch follows a single project and can not be used with --multi (-m)")
+			}
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
@@ -41,36 +45,64 @@ func NewGetLinksCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
+			if watch, interval := utils.WatchFlags(cmd); watch {
+				var id string
+				if useFuzzy {
+					params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
+					ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
+					if err != nil {
+						fmt.Println(err)
+						return
+					}
+					if len(ids) == 0 {
+						return
+					}
+					id = ids[0]
+				} else if id = args[0]; !utils.IsValidUUIDv4(id) {
+					if id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil); err != nil {
+						fmt.Println(err)
+						return
+					}
+				}
+				w := utils.Watch{CmdName: "getLinks", Args: []string{id}, StreamURL: endpoints.GetEndpoints{}.ProjectNotifications(id), ProjectID: id, Interval: interval}
+				if err := utils.WatchList(cmd.Context(), cfg, w); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", multi, "project", "Project:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				projectLinks, err := utils.GetResourceWithContext(cfg, "getLinks", ids, "project", "Project:")
+				projectLinks, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getLinks", ids, "project", "Project:")
 				if err != nil {
 					fmt.Printf("Error getting links: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(projectLinks, "Project:")
+				utils.PrintResourceWithContext(cfg, "link", projectLinks, "Project:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "project", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getLinks", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getLinks", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple projects")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
+	utils.AddWatchFlags(cmd)
 	return cmd
 }
 
@@ -107,7 +139,7 @@ func NewGetLinkCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -118,7 +150,7 @@ func NewGetLinkCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getLinks", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getLinks", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting links: %v\n", err)
 					return
@@ -147,24 +179,25 @@ func NewGetLinkCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(linkIDs, multi)
 
 				for _, linkID := range results {
-					utils.ExecuteAndPrint(cfg, "getLink", []string{projectIDs[0], linkID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getLink", []string{projectIDs[0], linkID})
 				}
 			} else {
 				projectID := args[0]
 				linkID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getLink", []string{projectID, linkID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getLink", []string{projectID, linkID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and link")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple links")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -201,7 +234,7 @@ func NewGetLinkIfaceCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -212,7 +245,7 @@ func NewGetLinkIfaceCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getLinks", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getLinks", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting links: %v\n", err)
 					return
@@ -241,24 +274,25 @@ func NewGetLinkIfaceCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(linkIDs, multi)
 
 				for _, linkID := range results {
-					utils.ExecuteAndPrint(cfg, "getLinkIface", []string{projectIDs[0], linkID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getLinkIface", []string{projectIDs[0], linkID})
 				}
 			} else {
 				projectID := args[0]
 				linkID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getLinkIface", []string{projectID, linkID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getLinkIface", []string{projectID, linkID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and link")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple links")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -295,7 +329,7 @@ func NewGetLinkFiltersCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -306,7 +340,7 @@ func NewGetLinkFiltersCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getLinks", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getLinks", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting links: %v\n", err)
 					return
@@ -335,23 +369,24 @@ func NewGetLinkFiltersCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(linkIDs, multi)
 
 				for _, linkID := range results {
-					utils.ExecuteAndPrint(cfg, "getLinkFilters", []string{projectIDs[0], linkID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getLinkFilters", []string{projectIDs[0], linkID})
 				}
 			} else {
 				projectID := args[0]
 				linkID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getLinkFilters", []string{projectID, linkID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getLinkFilters", []string{projectID, linkID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and link")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple links")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/fuzzy"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
@@ -34,6 +35,9 @@ func NewGetNodesCmd() *cobra.Command {
 			if multi && !useFuzzy {
 				return fmt.Errorf("the --multi (-m) flag can only be used together with --fuzzy (-f)")
 			}
+			if watch, _ := utils.WatchFlags(cmd); watch && multi {
+				return fmt.Errorf("--watch follows a single project and can not be used with --multi (-m)")
+			}
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
@@ -41,36 +45,64 @@ func NewGetNodesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
+			if watch, interval := utils.WatchFlags(cmd); watch {
+				var id string
+				if useFuzzy {
+					params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
+					ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
+					if err != nil {
+						fmt.Println(err)
+						return
+					}
+					if len(ids) == 0 {
+						return
+					}
+					id = ids[0]
+				} else if id = args[0]; !utils.IsValidUUIDv4(id) {
+					if id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil); err != nil {
+						fmt.Println(err)
+						return
+					}
+				}
+				w := utils.Watch{CmdName: "getNodes", Args: []string{id}, StreamURL: endpoints.GetEndpoints{}.ProjectNotifications(id), ProjectID: id, Interval: interval}
+				if err := utils.WatchList(cmd.Context(), cfg, w); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", multi, "project", "Project:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				projectNodes, err := utils.GetResourceWithContext(cfg, "getNodes", ids, "project", "Project:")
+				projectNodes, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getNodes", ids, "project", "Project:")
 				if err != nil {
 					fmt.Printf("Error getting nodes: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(projectNodes, "Project:")
+				utils.PrintResourceWithContext(cfg, "node", projectNodes, "Project:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "project", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getNodes", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodes", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple projects")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
+	utils.AddWatchFlags(cmd)
 	return cmd
 }
 
@@ -107,7 +139,7 @@ func NewGetNodeCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -118,7 +150,7 @@ func NewGetNodeCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getNodes", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getNodes", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting nodes: %v\n", err)
 					return
@@ -147,31 +179,32 @@ func NewGetNodeCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(nodeIDs, multi)
 
 				for _, nodeID := range results {
-					utils.ExecuteAndPrint(cfg, "getNode", []string{projectIDs[0], nodeID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getNode", []string{projectIDs[0], nodeID})
 				}
 			} else {
 				projectID := args[0]
 				nodeID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
 				if !utils.IsValidUUIDv4(args[1]) {
-					nodeID, err = utils.ResolveID(cfg, "node", args[1], []string{projectID})
+					nodeID, err = utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getNode", []string{projectID, nodeID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getNode", []string{projectID, nodeID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and node")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple nodes")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -208,7 +241,7 @@ func NewGetNodeLinksCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -219,7 +252,7 @@ func NewGetNodeLinksCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getNodes", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getNodes", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting nodes: %v\n", err)
 					return
@@ -248,31 +281,33 @@ func NewGetNodeLinksCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(nodeIDs, multi)
 
 				for _, nodeID := range results {
-					utils.ExecuteAndPrint(cfg, "getNodeLinks", []string{projectIDs[0], nodeID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeLinks", []string{projectIDs[0], nodeID})
 				}
 			} else {
 				projectID := args[0]
 				nodeID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
 				if !utils.IsValidUUIDv4(args[1]) {
-					nodeID, err = utils.ResolveID(cfg, "node", args[1], []string{projectID})
+					nodeID, err = utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getNodeLinks", []string{projectID, nodeID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeLinks", []string{projectID, nodeID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and node")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple nodes")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -309,7 +344,7 @@ func NewGetNodesAutoIdlePCCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -320,7 +355,7 @@ func NewGetNodesAutoIdlePCCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getNodes", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getNodes", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting nodes: %v\n", err)
 					return
@@ -349,31 +384,32 @@ func NewGetNodesAutoIdlePCCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(nodeIDs, multi)
 
 				for _, nodeID := range results {
-					utils.ExecuteAndPrint(cfg, "getNodeAutoIdlePc", []string{projectIDs[0], nodeID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeAutoIdlePc", []string{projectIDs[0], nodeID})
 				}
 			} else {
 				projectID := args[0]
 				nodeID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
 				if !utils.IsValidUUIDv4(args[1]) {
-					nodeID, err = utils.ResolveID(cfg, "node", args[1], []string{projectID})
+					nodeID, err = utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getNodeAutoIdlePc", []string{projectID, nodeID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeAutoIdlePc", []string{projectID, nodeID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and node")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple nodes")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -410,7 +446,7 @@ func NewGetNodesAutoIdlePCProposalsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				projectParams := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", false, "project", "Project:")
-				projectIDs, err := fuzzy.FuzzyInfoIDs(projectParams)
+				projectIDs, err := fuzzy.FuzzyInfoIDs(cmd.Context(), projectParams)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -421,7 +457,7 @@ func NewGetNodesAutoIdlePCProposalsCmd() *cobra.Command {
 					return
 				}
 
-				rawData, _, err := utils.CallClient(cfg, "getNodes", []string{projectIDs[0]}, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getNodes", []string{projectIDs[0]}, nil)
 				if err != nil {
 					fmt.Printf("Error getting nodes: %v\n", err)
 					return
@@ -450,30 +486,31 @@ func NewGetNodesAutoIdlePCProposalsCmd() *cobra.Command {
 				results := fuzzy.NewFuzzyFinder(nodeIDs, multi)
 
 				for _, nodeID := range results {
-					utils.ExecuteAndPrint(cfg, "getNodeAutoIdlePcProposals", []string{projectIDs[0], nodeID})
+					utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeAutoIdlePcProposals", []string{projectIDs[0], nodeID})
 				}
 			} else {
 				projectID := args[0]
 				nodeID := args[1]
 				if !utils.IsValidUUIDv4(args[0]) {
-					projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+					projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
 				if !utils.IsValidUUIDv4(args[1]) {
-					nodeID, err = utils.ResolveID(cfg, "node", args[1], []string{projectID})
+					nodeID, err = utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getNodeAutoIdlePcProposals", []string{projectID, nodeID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getNodeAutoIdlePcProposals", []string{projectID, nodeID})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project and node")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple nodes")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
@@ -38,16 +38,12 @@ func NewGetNotificationsCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-				api.WithTimeout(time.Duration(timeout)*time.Second),
-			)
+			settings := config.APISettings(cfg, token, api.WithTimeout(time.Duration(timeout)*time.Second))
 
 			ep := endpoints.GetEndpoints{}
 			client := api.NewGNS3Client(settings)
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(ep.Notifications()).
 				WithMethod(api.GET).
 				WithStream()
@@ -116,7 +112,7 @@ func NewGetProjectNotificationCmd() *cobra.Command {
 				return
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -129,16 +125,12 @@ func NewGetProjectNotificationCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-				api.WithTimeout(time.Duration(timeout)*time.Second),
-			)
+			settings := config.APISettings(cfg, token, api.WithTimeout(time.Duration(timeout)*time.Second))
 
 			ep := endpoints.GetEndpoints{}
 			client := api.NewGNS3Client(settings)
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(ep.ProjectNotifications(id)).
 				WithMethod(api.GET).
 				WithStream()
@@ -190,3 +182,9 @@ func NewGetProjectNotificationCmd() *cobra.Command {
 	cmd.Flags().IntVarP(&timeout, "timeout", "t", 5, "Timeout in seconds (0 for stream until cancellation)")
 	return cmd
 }
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("notifications", endpoints.Route{Method: string(api.GET), Path: ep.Get.Notifications()})
+	utils.RegisterRoutes("project notifications", endpoints.Route{Method: string(api.GET), Path: ep.Get.ProjectNotifications("{}")})
+}
@@ -20,9 +20,11 @@ func NewGetPoolsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getPools", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getPools", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -59,7 +61,7 @@ func NewGetPoolCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getPools", "name", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -67,18 +69,19 @@ func NewGetPoolCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "pool", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "pool", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getPool", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getPool", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a pool")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple pools")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -115,33 +118,35 @@ func NewGetPoolResourcesCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getPools", "name", multi, "pool", "Pool:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				poolResources, err := utils.GetResourceWithContext(cfg, "getPoolResources", ids, "pool", "Pool:")
+				poolResources, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getPoolResources", ids, "pool", "Pool:")
 				if err != nil {
 					fmt.Printf("Error getting pool resources: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(poolResources, "Pool:")
+				utils.PrintResourceWithContext(cfg, "", poolResources, "Pool:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "pool", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "pool", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getPoolResources", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getPoolResources", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a pool")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple pools")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
@@ -19,9 +19,12 @@ func NewGetPrivilegesCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "getPrivileges", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getPrivileges", nil)
 		},
 	}
 
+	utils.AddOutputFlag(cmd)
+
+	utils.AddListFlags(cmd)
 	return cmd
 }
@@ -1,6 +1,7 @@
 package get
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/authentication"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
@@ -25,9 +27,19 @@ func NewGetProjectsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getProjects", nil)
+			if watch, interval := utils.WatchFlags(cmd); watch {
+				w := utils.Watch{CmdName: "getProjects", StreamURL: endpoints.GetEndpoints{}.Notifications(), Interval: interval}
+				if err := utils.WatchList(cmd.Context(), cfg, w); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getProjects", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
+	utils.AddWatchFlags(cmd)
 	return cmd
 }
 
@@ -45,15 +57,16 @@ func NewGetProjectCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getProject", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getProject", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -71,15 +84,16 @@ func NewGetProjectStatsCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getProjectStats", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getProjectStats", []string{id})
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -99,17 +113,18 @@ func NewGetProjectLockedCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "getProjectLocked", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getProjectLocked", []string{id})
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple projects")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -139,7 +154,7 @@ func NewGetProjectExportCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -148,7 +163,7 @@ func NewGetProjectExportCmd() *cobra.Command {
 
 			projectName := args[0]
 			if utils.IsValidUUIDv4(args[0]) {
-				projectName, err = getProjectNameFromID(cfg, id)
+				projectName, err = getProjectNameFromID(cmd.Context(), cfg, id)
 				if err != nil {
 					fmt.Printf("failed to get project name: %v", err)
 					return
@@ -165,14 +180,11 @@ func NewGetProjectExportCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/export", id)).
 				WithMethod(api.GET)
 
@@ -213,20 +225,17 @@ func NewGetProjectExportCmd() *cobra.Command {
 	return cmd
 }
 
-func getProjectNameFromID(cfg config.GlobalOptions, projectID string) (string, error) {
+func getProjectNameFromID(ctx context.Context, cfg config.GlobalOptions, projectID string) (string, error) {
 	token, err := authentication.GetKeyForServer(cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to get token: %w", err)
 	}
 
-	settings := api.NewSettings(
-		api.WithBaseURL(cfg.Server),
-		api.WithVerify(cfg.Insecure),
-		api.WithToken(token),
-	)
+	settings := config.APISettings(cfg, token)
 	client := api.NewGNS3Client(settings)
 
 	reqOpts := api.NewRequestOptions(settings).
+		WithContext(ctx).
 		WithURL(fmt.Sprintf("/projects/%s", projectID)).
 		WithMethod(api.GET)
 
@@ -275,7 +284,7 @@ func NewGetProjectFileCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[0]) {
-				projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+				projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -292,14 +301,11 @@ func NewGetProjectFileCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/files/%s", projectID, filePath)).
 				WithMethod(api.GET)
 
@@ -354,7 +360,7 @@ func NewGetNodeFileCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[0]) {
-				projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+				projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -362,7 +368,7 @@ func NewGetNodeFileCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[1]) {
-				nodeID, err = utils.ResolveID(cfg, "node", args[1], nil)
+				nodeID, err = utils.ResolveID(cmd.Context(), cfg, "node", args[1], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -379,14 +385,11 @@ func NewGetNodeFileCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/%s/files/%s", projectID, nodeID, filePath)).
 				WithMethod(api.GET)
 
@@ -440,7 +443,7 @@ func NewStreamPcapCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[0]) {
-				projectID, err = utils.ResolveID(cfg, "project", args[0], nil)
+				projectID, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -448,7 +451,7 @@ func NewStreamPcapCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(args[1]) {
-				linkID, err = utils.ResolveID(cfg, "link", args[1], nil)
+				linkID, err = utils.ResolveID(cmd.Context(), cfg, "link", args[1], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -465,14 +468,11 @@ func NewStreamPcapCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/links/%s/capture/stream", projectID, linkID)).
 				WithMethod(api.GET)
 
@@ -506,3 +506,11 @@ func NewStreamPcapCmd() *cobra.Command {
 
 	return cmd
 }
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("project export", endpoints.Route{Method: string(api.GET), Path: ep.Get.ProjectExport("{}")})
+	utils.RegisterRoutes("project file", endpoints.Route{Method: string(api.GET), Path: ep.Get.ProjectFile("{}", "{}")})
+	utils.RegisterRoutes("project node-file", endpoints.Route{Method: string(api.GET), Path: ep.Get.NodeFile("{}", "{}", "{}")})
+	utils.RegisterRoutes("project stream-pcap", endpoints.Route{Method: string(api.GET), Path: ep.Get.StreamPcap("{}", "{}")})
+}
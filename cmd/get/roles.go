@@ -20,9 +20,11 @@ func NewGetRolesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getRoles", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getRoles", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -59,7 +61,7 @@ func NewGetRoleCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getRoles", "name", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -67,18 +69,19 @@ func NewGetRoleCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "role", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "role", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getRole", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getRole", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a role")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple roles")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -115,33 +118,35 @@ func NewGetRolePrivsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getRoles", "name", multi, "role", "Role:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				rolePrivs, err := utils.GetResourceWithContext(cfg, "getRolePrivs", ids, "role", "Role:")
+				rolePrivs, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getRolePrivs", ids, "role", "Role:")
 				if err != nil {
 					fmt.Printf("Error getting role privileges: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(rolePrivs, "Role:")
+				utils.PrintResourceWithContext(cfg, "privilege", rolePrivs, "Role:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "role", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "role", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getRolePrivs", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getRolePrivs", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a role")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple roles")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
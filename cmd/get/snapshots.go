@@ -42,33 +42,35 @@ func NewGetSnapshotsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getProjects", "name", multi, "project", "Project:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				snapshots, err := utils.GetResourceWithContext(cfg, "getSnapshots", ids, "project", "Project:")
+				snapshots, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getSnapshots", ids, "project", "Project:")
 				if err != nil {
 					fmt.Printf("Error getting snapshots: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(snapshots, "Project:")
+				utils.PrintResourceWithContext(cfg, "snapshot", snapshots, "Project:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "project", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getSnapshots", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getSnapshots", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a project")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get snapshots from multiple projects")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
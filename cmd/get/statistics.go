@@ -19,8 +19,9 @@ func NewGetStatisticsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getMe", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getMe", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
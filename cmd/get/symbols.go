@@ -20,9 +20,11 @@ func NewGetSymbolsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getSymbols", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getSymbols", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -59,7 +61,7 @@ func NewGetSymbolCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getSymbols", "symbol_id", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -67,18 +69,19 @@ func NewGetSymbolCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "symbol", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "symbol", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getSymbol", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getSymbol", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a symbol")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple symbols")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -115,7 +118,7 @@ func NewGetSymbolDimensionsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getSymbols", "symbol_id", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -123,18 +126,19 @@ func NewGetSymbolDimensionsCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "symbol", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "symbol", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getSymbolDimensions", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getSymbolDimensions", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a symbol")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get dimensions for multiple symbols")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -149,8 +153,9 @@ func NewGetDefaultSymbolsCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getDefaultSymbols", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getDefaultSymbols", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
@@ -20,9 +20,11 @@ func NewGetTemplatesCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getTemplates", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getTemplates", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -59,7 +61,7 @@ func NewGetTemplateCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getTemplates", "template_id", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -67,17 +69,18 @@ func NewGetTemplateCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "template", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "template", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getTemplate", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getTemplate", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Use fuzzy search to find a template")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Get multiple templates")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
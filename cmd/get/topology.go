@@ -0,0 +1,75 @@
+package get
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/stefanistkuhl/gns3util/pkg/topology"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewGetProjectTopologyCmd() *cobra.Command {
+	var file string
+	var cmd = &cobra.Command{
+		Use:   "export-topology [project-name/id]",
+		Short: "Export the nodes, links and drawings of a project as a topology file",
+		Long: `Write the nodes, links and drawings of a project in the topology format
+that "project build" reads. Nodes refer to their template by name and
+links are written as NODE:PORT -- NODE:PORT with the short port names.`,
+		Example: `  gns3util -s https://controller:3080 project export-topology my-project > lab.yaml
+  gns3util -s https://controller:3080 project export-topology my-project -f lab.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			id := args[0]
+			if !utils.IsValidUUIDv4(id) {
+				if id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil); err != nil {
+					return err
+				}
+			}
+			client, err := sdk.NewFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			t, err := topology.Export(cmd.Context(), client, id)
+			if err != nil {
+				return fmt.Errorf("failed to export topology: %w", err)
+			}
+			data, err := t.Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to encode topology: %w", err)
+			}
+			if file == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			if err := os.WriteFile(file, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write topology: %w", err)
+			}
+			fmt.Printf("%v topology of %s written to %s\n", messageUtils.SuccessMsg("Exported"), messageUtils.Bold(t.Name), messageUtils.Highlight(file))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the topology to a file instead of stdout")
+	return cmd
+}
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("project export-topology",
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Project("{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Templates()},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Nodes("{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Links("{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Drawings("{}")},
+	)
+}
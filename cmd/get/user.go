@@ -45,7 +45,7 @@ func NewGetUserCmd() *cobra.Command {
 
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParams(cfg, "getUsers", "username", multi)
-				err := fuzzy.FuzzyInfo(params)
+				err := fuzzy.FuzzyInfo(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -53,18 +53,19 @@ func NewGetUserCmd() *cobra.Command {
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "user", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "user", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getUser", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getUser", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Enable fuzzy search mode for interactive selection")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Allow selecting multiple items (requires --fuzzy)")
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
 
@@ -79,9 +80,11 @@ func NewGetUsersCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getUsers", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getUsers", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
 
@@ -120,33 +123,35 @@ func NewGetGroupMembershipsCmd() *cobra.Command {
 			}
 			if useFuzzy {
 				params := fuzzy.NewFuzzyInfoParamsWithContext(cfg, "getUsers", "username", multi, "user", "User:")
-				ids, err := fuzzy.FuzzyInfoIDs(params)
+				ids, err := fuzzy.FuzzyInfoIDs(cmd.Context(), params)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 
-				userMemberships, err := utils.GetResourceWithContext(cfg, "getGroupMemberships", ids, "user", "User:")
+				userMemberships, err := utils.GetResourceWithContext(cmd.Context(), cfg, "getGroupMemberships", ids, "user", "User:")
 				if err != nil {
 					fmt.Printf("Error getting group memberships: %v\n", err)
 					return
 				}
 
-				utils.PrintResourceWithContext(userMemberships, "User:")
+				utils.PrintResourceWithContext(cfg, "group", userMemberships, "User:")
 			} else {
 				id := args[0]
 				if !utils.IsValidUUIDv4(args[0]) {
-					id, err = utils.ResolveID(cfg, "user", args[0], nil)
+					id, err = utils.ResolveID(cmd.Context(), cfg, "user", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
 					}
 				}
-				utils.ExecuteAndPrint(cfg, "getGroupMemberships", []string{id})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "getGroupMemberships", []string{id})
 			}
 		},
 	}
 	cmd.Flags().BoolVarP(&useFuzzy, "fuzzy", "f", false, "Enable fuzzy search mode for interactive selection")
 	cmd.Flags().BoolVarP(&multi, "multi", "m", false, "Allow selecting multiple items (requires --fuzzy)")
+	utils.AddOutputFlag(cmd)
+	utils.AddListFlags(cmd)
 	return cmd
 }
@@ -19,8 +19,9 @@ func NewGetVersionCmd() *cobra.Command {
 			if err != nil {
 				fmt.Printf("failed to get global options: %v", err)
 			}
-			utils.ExecuteAndPrint(cfg, "getVersion", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "getVersion", nil)
 		},
 	}
+	utils.AddOutputFlag(cmd)
 	return cmd
 }
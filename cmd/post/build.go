@@ -0,0 +1,118 @@
+package post
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/stefanistkuhl/gns3util/pkg/topology"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/colorUtils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+func NewProjectBuildCmd() *cobra.Command {
+	var (
+		file    string
+		project string
+	)
+	var cmd = &cobra.Command{
+		Use:   "build",
+		Short: "Create or update a project from a topology file",
+		Long: `Create or update a project from a YAML topology file.
+
+The file has the project name, nodes with a template (or node_type), a
+position and properties, links written as NODE:PORT -- NODE:PORT and drawings
+given as svg or text with a position:
+
+  name: ospf-lab
+  nodes:
+    - name: R1
+      template: Cisco IOSv
+      position: [-200, 0]
+      properties: {ram: 1024}
+    - name: SW1
+      template: Ethernet switch
+      position: [0, 0]
+  links:
+    - R1:Gi0/0 -- SW1:e1
+  drawings:
+    - text: OSPF area 0
+      position: [-200, -100]
+
+Ports are matched by name, short name or adapter/port numbers (0/1).
+Nodes are matched by name, drawings by their SVG. The project, nodes,
+links and drawings that are missing are created and the positions and
+listed properties of existing ones updated, so building a file again only
+applies what changed. Nothing is deleted. "project export-topology"
+writes an existing project in this format.`,
+		Example: `  gns3util -s https://controller:3080 project build -f lab.yaml
+  gns3util -s https://controller:3080 project build -f lab.yaml --project lab-student1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			t, err := topology.Load(file)
+			if err != nil {
+				return err
+			}
+			client, err := sdk.NewFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			changes := 0
+			err = topology.Build(cmd.Context(), client, t, project, func(s topology.Step) {
+				changes++
+				symbol := colorUtils.Success("+")
+				if s.Action != "create" {
+					symbol = colorUtils.Warning("~")
+				}
+				fmt.Printf("%s %s %s %s\n", symbol, s.Action, s.Kind, messageUtils.Bold(s.Name))
+				for _, d := range s.Details {
+					fmt.Printf("    %s\n", d)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			name := project
+			if name == "" {
+				name = t.Name
+			}
+			if changes == 0 {
+				fmt.Printf("%v project %s already matches the topology\n", messageUtils.InfoMsg("No changes"), messageUtils.Bold(name))
+				return nil
+			}
+			fmt.Printf("%v project %s built with %d change(s)\n", messageUtils.SuccessMsg("Done"), messageUtils.Bold(name), changes)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Topology file to build")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project to build, instead of the name in the file")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("project build",
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Projects()},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateProject()},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.OpenProject("{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Templates()},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Nodes("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateNode("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateProjectNodeFromTemplate("{}", "{}")},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.UpdateNode("{}", "{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Links("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateLink("{}")},
+		endpoints.Route{Method: string(api.GET), Path: ep.Get.Drawings("{}")},
+		endpoints.Route{Method: string(api.POST), Path: ep.Post.CreateDrawing("{}")},
+		endpoints.Route{Method: string(api.PUT), Path: ep.Put.UpdateDrawing("{}", "{}")},
+	)
+}
@@ -53,7 +53,7 @@ func NewCheckVersionCmd() *cobra.Command {
 				}
 			}
 
-			utils.ExecuteAndPrintWithBody(cfg, "checkVersion", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "checkVersion", nil, payload)
 			return nil
 		},
 	}
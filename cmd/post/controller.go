@@ -36,7 +36,7 @@ func NewReloadCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "reloadController", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "reloadController", nil)
 		},
 	}
 
@@ -56,7 +56,7 @@ func NewShutdownCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "shutdownController", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "shutdownController", nil)
 		},
 	}
 
@@ -40,21 +40,21 @@ func NewCreateACLCmd() *cobra.Command {
 				}
 				// resolve non-UUID ids
 				if groupID != "" && !utils.IsValidUUIDv4(groupID) {
-					id, err := utils.ResolveID(cfg, "group", groupID, nil)
+					id, err := utils.ResolveID(cmd.Context(), cfg, "group", groupID, nil)
 					if err != nil {
 						return err
 					}
 					groupID = id
 				}
 				if userID != "" && !utils.IsValidUUIDv4(userID) {
-					id, err := utils.ResolveID(cfg, "user", userID, nil)
+					id, err := utils.ResolveID(cmd.Context(), cfg, "user", userID, nil)
 					if err != nil {
 						return err
 					}
 					userID = id
 				}
 				if !utils.IsValidUUIDv4(roleID) {
-					id, err := utils.ResolveID(cfg, "role", roleID, nil)
+					id, err := utils.ResolveID(cmd.Context(), cfg, "role", roleID, nil)
 					if err != nil {
 						return err
 					}
@@ -100,7 +100,7 @@ func NewCreateACLCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createACL", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createACL", nil, payload)
 			return nil
 		},
 	}
@@ -77,7 +77,7 @@ func NewCreateComputeCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createCompute", []string{fmt.Sprintf("%t", connect)}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createCompute", []string{fmt.Sprintf("%t", connect)}, payload)
 			return nil
 		},
 	}
@@ -61,7 +61,7 @@ func NewCreateDrawingCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createDrawing", []string{projectID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createDrawing", []string{projectID}, payload)
 			return nil
 		},
 	}
@@ -1,6 +1,7 @@
 package create
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -56,7 +57,7 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
 	confirm, _ := cmd.Flags().GetBool("confirm")
 
-	groupsBody, status, err := utils.CallClient(cfg, "getGroups", []string{}, nil)
+	groupsBody, status, err := utils.CallClient(cmd.Context(), cfg, "getGroups", []string{}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get groups: %w", err)
 	}
@@ -91,13 +92,13 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get the "User" role ID
-	roleID, err := getUserRoleID(cfg)
+	roleID, err := getUserRoleID(cmd.Context(), cfg)
 	if err != nil {
 		return fmt.Errorf("failed to get User role ID: %w", err)
 	}
 
 	// Check if groups already have exercises
-	existingExercises, err := checkExistingExercises(cfg, className, classGroups)
+	existingExercises, err := checkExistingExercises(cmd.Context(), cfg, className, classGroups)
 	if err != nil {
 		return fmt.Errorf("failed to check existing exercises: %w", err)
 	}
@@ -112,6 +113,9 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 
 	successCount := 0
 	for _, group := range classGroups {
+		if err := cmd.Context().Err(); err != nil {
+			return fmt.Errorf("exercise creation interrupted after %d/%d projects: %w", successCount, len(classGroups), err)
+		}
 		groupName := group.Name
 		groupID := group.UserGroupID.String()
 
@@ -130,7 +134,7 @@ func runCreateExercise(cmd *cobra.Command, args []string) error {
 
 		projectName := generateProjectName(format, className, exerciseName, groupNumber)
 
-		if err := createProjectForGroup(cfg, projectName, groupID, roleID); err != nil {
+		if err := createProjectForGroup(cmd.Context(), cfg, projectName, groupID, roleID); err != nil {
 			fmt.Printf("%v Failed to create project for group %s: %v\n",
 				messageUtils.ErrorMsg("Failed to create project for group"),
 				messageUtils.Bold(groupName),
@@ -185,8 +189,8 @@ func generateProjectName(format, className, exerciseName, groupNumber string) st
 	return projectName
 }
 
-func getUserRoleID(cfg config.GlobalOptions) (string, error) {
-	rolesBody, status, err := utils.CallClient(cfg, "getRoles", []string{}, nil)
+func getUserRoleID(ctx context.Context, cfg config.GlobalOptions) (string, error) {
+	rolesBody, status, err := utils.CallClient(ctx, cfg, "getRoles", []string{}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get roles: %w", err)
 	}
@@ -209,12 +213,12 @@ func getUserRoleID(cfg config.GlobalOptions) (string, error) {
 	return "", fmt.Errorf("user role not found")
 }
 
-func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleID string) error {
+func createProjectForGroup(ctx context.Context, cfg config.GlobalOptions, projectName, groupID, roleID string) error {
 	projectData := schemas.ProjectCreate{
 		Name: &projectName,
 	}
 
-	projectBody, status, err := utils.CallClient(cfg, "createProject", []string{}, projectData)
+	projectBody, status, err := utils.CallClient(ctx, cfg, "createProject", []string{}, projectData)
 	if err != nil {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
@@ -230,7 +234,7 @@ func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleI
 
 	projectID := projectResponse.ProjectID
 
-	_, status, err = utils.CallClient(cfg, "closeProject", []string{projectID}, nil)
+	_, status, err = utils.CallClient(ctx, cfg, "closeProject", []string{projectID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to close project: %w", err)
 	}
@@ -244,7 +248,7 @@ func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleI
 		Name: &poolName,
 	}
 
-	poolBody, status, err := utils.CallClient(cfg, "createPool", []string{}, poolData)
+	poolBody, status, err := utils.CallClient(ctx, cfg, "createPool", []string{}, poolData)
 	if err != nil {
 		return fmt.Errorf("failed to create resource pool: %w", err)
 	}
@@ -260,7 +264,7 @@ func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleI
 
 	poolID := poolResponse.ResourcePoolID
 
-	_, status, err = utils.CallClient(cfg, "addToPool", []string{poolID, projectID}, nil)
+	_, status, err = utils.CallClient(ctx, cfg, "addToPool", []string{poolID, projectID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to add project to pool: %w", err)
 	}
@@ -283,7 +287,7 @@ func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleI
 		RoleID:    &roleID,
 	}
 
-	_, status, err = utils.CallClient(cfg, "createACL", []string{}, aclData)
+	_, status, err = utils.CallClient(ctx, cfg, "createACL", []string{}, aclData)
 	if err != nil {
 		return fmt.Errorf("failed to create ACL: %w", err)
 	}
@@ -295,8 +299,8 @@ func createProjectForGroup(cfg config.GlobalOptions, projectName, groupID, roleI
 	return nil
 }
 
-func checkExistingExercises(cfg config.GlobalOptions, className string, classGroups []schemas.UserGroupResponse) ([]string, error) {
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+func checkExistingExercises(ctx context.Context, cfg config.GlobalOptions, className string, classGroups []schemas.UserGroupResponse) ([]string, error) {
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
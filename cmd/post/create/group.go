@@ -40,7 +40,7 @@ func NewCreateGroupCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createGroup", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createGroup", nil, payload)
 			return nil
 		},
 	}
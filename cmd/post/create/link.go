@@ -37,7 +37,7 @@ func NewCreateLinkCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createLink", []string{projectID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createLink", []string{projectID}, payload)
 			return nil
 		},
 	}
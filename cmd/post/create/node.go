@@ -161,7 +161,7 @@ func NewCreateNodeCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createNode", []string{projectID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createNode", []string{projectID}, payload)
 			return nil
 		},
 	}
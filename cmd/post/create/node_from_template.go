@@ -60,7 +60,7 @@ func NewCreateNodeFromTemplateCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createProjectNodeFromTemplate", []string{projectID, templateID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createProjectNodeFromTemplate", []string{projectID, templateID}, payload)
 			return nil
 		},
 	}
@@ -34,7 +34,7 @@ func NewCreatePoolCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createPool", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createPool", nil, payload)
 			return nil
 		},
 	}
@@ -125,9 +125,9 @@ func NewCreateProjectCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createProject", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createProject", nil, payload)
 			if closeAfterCreation && projectID != "" {
-				utils.ExecuteAndPrint(cfg, "closeProject", []string{projectID})
+				utils.ExecuteAndPrint(cmd.Context(), cfg, "closeProject", []string{projectID})
 			}
 			return nil
 		},
@@ -112,7 +112,7 @@ func NewCreateQemuDiskImageCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createDiskImage", []string{projectID, nodeID, diskName}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createDiskImage", []string{projectID, nodeID, diskName}, payload)
 			return nil
 		},
 	}
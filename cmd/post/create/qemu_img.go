@@ -112,7 +112,7 @@ func NewCreateQemuImageCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createQemuImage", []string{imagePath}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createQemuImage", []string{imagePath}, payload)
 			return nil
 		},
 	}
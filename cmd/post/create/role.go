@@ -44,7 +44,7 @@ func NewCreateRoleCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createRole", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createRole", nil, payload)
 			return nil
 		},
 	}
@@ -40,7 +40,7 @@ func NewCreateSnapshotCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createSnapshot", []string{projectID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createSnapshot", []string{projectID}, payload)
 			return nil
 		},
 	}
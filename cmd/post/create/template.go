@@ -89,7 +89,7 @@ func NewCreateTemplateCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createTemplate", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createTemplate", nil, payload)
 			return nil
 		},
 	}
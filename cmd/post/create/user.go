@@ -66,7 +66,7 @@ func NewCreateUserCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "createUser", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "createUser", nil, payload)
 			return nil
 		},
 	}
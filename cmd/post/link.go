@@ -40,7 +40,7 @@ func NewResetLinkCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -48,14 +48,14 @@ func NewResetLinkCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(linkID) {
-				id, err := utils.ResolveID(cfg, "link", linkID, []string{projectID})
+				id, err := utils.ResolveID(cmd.Context(), cfg, "link", linkID, []string{projectID})
 				if err != nil {
 					return fmt.Errorf("failed to resolve link ID: %w", err)
 				}
 				linkID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "resetLink", []string{projectID, linkID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "resetLink", []string{projectID, linkID})
 			return nil
 		},
 	}
@@ -79,7 +79,7 @@ func NewStartCaptureCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -87,14 +87,14 @@ func NewStartCaptureCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(linkID) {
-				id, err := utils.ResolveID(cfg, "link", linkID, []string{projectID})
+				id, err := utils.ResolveID(cmd.Context(), cfg, "link", linkID, []string{projectID})
 				if err != nil {
 					return fmt.Errorf("failed to resolve link ID: %w", err)
 				}
 				linkID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "startCapture", []string{projectID, linkID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "startCapture", []string{projectID, linkID})
 			return nil
 		},
 	}
@@ -118,7 +118,7 @@ func NewStopCaptureCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -126,14 +126,14 @@ func NewStopCaptureCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(linkID) {
-				id, err := utils.ResolveID(cfg, "link", linkID, []string{projectID})
+				id, err := utils.ResolveID(cmd.Context(), cfg, "link", linkID, []string{projectID})
 				if err != nil {
 					return fmt.Errorf("failed to resolve link ID: %w", err)
 				}
 				linkID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "stopCapture", []string{projectID, linkID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "stopCapture", []string{projectID, linkID})
 			return nil
 		},
 	}
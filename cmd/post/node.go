@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/authentication"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
@@ -49,7 +50,7 @@ func NewNodeDuplicateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -68,14 +69,11 @@ func NewNodeDuplicateCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/%s/duplicate", projectID, nodeID)).
 				WithMethod(api.POST)
 
@@ -118,7 +116,7 @@ func NewNodeConsoleResetCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -137,14 +135,11 @@ func NewNodeConsoleResetCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/%s/console/reset", projectID, nodeID)).
 				WithMethod(api.POST)
 
@@ -187,7 +182,7 @@ func NewNodeIsolateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -206,14 +201,11 @@ func NewNodeIsolateCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/%s/isolate", projectID, nodeID)).
 				WithMethod(api.POST)
 
@@ -256,7 +248,7 @@ func NewNodeUnisolateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -275,14 +267,11 @@ func NewNodeUnisolateCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/%s/unisolate", projectID, nodeID)).
 				WithMethod(api.POST)
 
@@ -324,7 +313,7 @@ func NewReloadNodesCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -338,14 +327,11 @@ func NewReloadNodesCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/reload", projectID)).
 				WithMethod(api.POST)
 
@@ -387,7 +373,7 @@ func NewStartNodesCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -401,14 +387,11 @@ func NewStartNodesCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/start", projectID)).
 				WithMethod(api.POST)
 
@@ -450,7 +433,7 @@ func NewStopNodesCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -464,14 +447,11 @@ func NewStopNodesCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/stop", projectID)).
 				WithMethod(api.POST)
 
@@ -513,7 +493,7 @@ func NewSuspendNodesCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -527,14 +507,11 @@ func NewSuspendNodesCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/nodes/suspend", projectID)).
 				WithMethod(api.POST)
 
@@ -559,3 +536,15 @@ func NewSuspendNodesCmd() *cobra.Command {
 
 	return cmd
 }
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("node duplicate", endpoints.Route{Method: string(api.POST), Path: ep.Post.DuplicateNode("{}", "{}")})
+	utils.RegisterRoutes("node console-reset", endpoints.Route{Method: string(api.POST), Path: ep.Post.NodeConsoleReset("{}", "{}")})
+	utils.RegisterRoutes("node node-isolate", endpoints.Route{Method: string(api.POST), Path: ep.Post.IsolateNode("{}", "{}")})
+	utils.RegisterRoutes("node node-unisolate", endpoints.Route{Method: string(api.POST), Path: ep.Post.UnisolateNode("{}", "{}")})
+	utils.RegisterRoutes("node reload-all", endpoints.Route{Method: string(api.POST), Path: ep.Post.ReloadNodes("{}")})
+	utils.RegisterRoutes("node start-all", endpoints.Route{Method: string(api.POST), Path: ep.Post.StartNodes("{}")})
+	utils.RegisterRoutes("node stop-all", endpoints.Route{Method: string(api.POST), Path: ep.Post.StopNodes("{}")})
+	utils.RegisterRoutes("node suspend-all", endpoints.Route{Method: string(api.POST), Path: ep.Post.SuspendNodes("{}")})
+}
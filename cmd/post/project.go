@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -74,7 +75,7 @@ func NewProjectDuplicateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -142,7 +143,7 @@ func NewProjectDuplicateCmd() *cobra.Command {
 				}
 			}
 
-			utils.ExecuteAndPrintWithBody(cfg, "duplicateProject", []string{projectID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "duplicateProject", []string{projectID}, payload)
 			return nil
 		},
 	}
@@ -187,14 +188,11 @@ func NewProjectLoadCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/load?path=%s", projectPath)).
 				WithMethod(api.POST)
 
@@ -236,7 +234,7 @@ func NewProjectCloseCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -250,14 +248,11 @@ func NewProjectCloseCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/close", projectID)).
 				WithMethod(api.POST)
 
@@ -331,11 +326,7 @@ gns3util -s https://controller:3080 post project import --name "my-project" /pat
 				return fmt.Errorf("failed to get token: %w", err)
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			ep := endpoints.Endpoints{}
@@ -346,6 +337,7 @@ gns3util -s https://controller:3080 post project import --name "my-project" /pat
 			}
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(urlStr).
 				WithMethod(api.POST).
 				WithData(buf.String())
@@ -389,7 +381,7 @@ func NewProjectLockCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -403,14 +395,11 @@ func NewProjectLockCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/lock", projectID)).
 				WithMethod(api.POST)
 
@@ -452,7 +441,7 @@ func NewProjectOpenCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -466,14 +455,11 @@ func NewProjectOpenCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/open", projectID)).
 				WithMethod(api.POST)
 
@@ -515,7 +501,7 @@ func NewProjectUnlockCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -529,14 +515,11 @@ func NewProjectUnlockCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
+				WithContext(cmd.Context()).
 				WithURL(fmt.Sprintf("/projects/%s/unlock", projectID)).
 				WithMethod(api.POST)
 
@@ -579,7 +562,7 @@ func NewProjectWriteFileCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -593,15 +576,12 @@ func NewProjectWriteFileCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
-				WithURL(fmt.Sprintf("/projects/%s/files%s", projectID, filePath)).
+				WithContext(cmd.Context()).
+				WithURL(endpoints.PostEndpoints{}.WriteProjectFile(projectID, strings.TrimPrefix(filePath, "/"))).
 				WithMethod(api.POST)
 
 			_, resp, err := client.Do(reqOpts)
@@ -643,7 +623,7 @@ func NewProjectStartCaptureCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(projectID) {
-				id, err := utils.ResolveID(cfg, "project", projectID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -662,15 +642,12 @@ func NewProjectStartCaptureCmd() *cobra.Command {
 				return
 			}
 
-			settings := api.NewSettings(
-				api.WithBaseURL(cfg.Server),
-				api.WithVerify(cfg.Insecure),
-				api.WithToken(token),
-			)
+			settings := config.APISettings(cfg, token)
 			client := api.NewGNS3Client(settings)
 
 			reqOpts := api.NewRequestOptions(settings).
-				WithURL(fmt.Sprintf("/projects/%s/links/%s/start_capture", projectID, linkID)).
+				WithContext(cmd.Context()).
+				WithURL(endpoints.PostEndpoints{}.StartCapture(projectID, linkID)).
 				WithMethod(api.POST)
 
 			_, resp, err := client.Do(reqOpts)
@@ -694,3 +671,15 @@ func NewProjectStartCaptureCmd() *cobra.Command {
 
 	return cmd
 }
+
+func init() {
+	ep := endpoints.Endpoints{}
+	utils.RegisterRoutes("project load", endpoints.Route{Method: string(api.POST), Path: ep.Post.LoadProject()})
+	utils.RegisterRoutes("project close", endpoints.Route{Method: string(api.POST), Path: ep.Post.CloseProject("{}")})
+	utils.RegisterRoutes("project import", endpoints.Route{Method: string(api.POST), Path: ep.Post.ProjectImport("{}")})
+	utils.RegisterRoutes("project lock", endpoints.Route{Method: string(api.POST), Path: ep.Post.LockProject("{}")})
+	utils.RegisterRoutes("project open", endpoints.Route{Method: string(api.POST), Path: ep.Post.OpenProject("{}")})
+	utils.RegisterRoutes("project unlock", endpoints.Route{Method: string(api.POST), Path: ep.Post.UnlockProject("{}")})
+	utils.RegisterRoutes("project write-file", endpoints.Route{Method: string(api.POST), Path: ep.Post.WriteProjectFile("{}", "{}")})
+	utils.RegisterRoutes("project start-capture", endpoints.Route{Method: string(api.POST), Path: ep.Post.StartCapture("{}", "{}")})
+}
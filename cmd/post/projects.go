@@ -21,13 +21,13 @@ func NewLockProjectCmd() *cobra.Command {
 				fmt.Printf("failed to get global options: %v", err)
 			}
 			if !utils.IsValidUUIDv4(args[0]) {
-				id, err = utils.ResolveID(cfg, "project", args[0], nil)
+				id, err = utils.ResolveID(cmd.Context(), cfg, "project", args[0], nil)
 				if err != nil {
 					fmt.Println(err)
 					return
 				}
 			}
-			utils.ExecuteAndPrint(cfg, "lockProject", []string{id})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "lockProject", []string{id})
 		},
 	}
 	return cmd
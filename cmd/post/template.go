@@ -38,7 +38,7 @@ func NewDuplicateTemplateCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(templateID) {
-				id, err := utils.ResolveID(cfg, "template", templateID, nil)
+				id, err := utils.ResolveID(cmd.Context(), cfg, "template", templateID, nil)
 				if err != nil {
 					fmt.Println(err)
 					return
@@ -46,7 +46,7 @@ func NewDuplicateTemplateCmd() *cobra.Command {
 				templateID = id
 			}
 
-			utils.ExecuteAndPrint(cfg, "duplicateTemplate", []string{templateID})
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "duplicateTemplate", []string{templateID})
 		},
 	}
 
@@ -21,7 +21,7 @@ func NewUserAuthenticateCmd() *cobra.Command {
 				return
 			}
 
-			utils.ExecuteAndPrint(cfg, "userAuthenticate", nil)
+			utils.ExecuteAndPrint(cmd.Context(), cfg, "userAuthenticate", nil)
 		},
 	}
 
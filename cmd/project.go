@@ -26,6 +26,7 @@ func NewProjectCmdGroup() *cobra.Command {
 	projectCmd.AddCommand(get.NewGetProjectFileCmd())
 	projectCmd.AddCommand(get.NewGetProjectLockedCmd())
 	projectCmd.AddCommand(get.NewGetProjectStatsCmd())
+	projectCmd.AddCommand(get.NewGetProjectTopologyCmd())
 
 	// Post subcommands
 	projectCmd.AddCommand(post.NewProjectCloseCmd())
@@ -37,6 +38,7 @@ func NewProjectCmdGroup() *cobra.Command {
 	projectCmd.AddCommand(post.NewProjectUnlockCmd())
 	projectCmd.AddCommand(post.NewProjectWriteFileCmd())
 	projectCmd.AddCommand(post.NewProjectStartCaptureCmd())
+	projectCmd.AddCommand(post.NewProjectBuildCmd())
 
 	// Update subcommands
 	projectCmd.AddCommand(update.NewUpdateProjectCmd())
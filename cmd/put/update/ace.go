@@ -45,7 +45,7 @@ func NewUpdateACECmd() *cobra.Command {
 			}
 
 			if userID != "" && !utils.IsValidUUIDv4(userID) {
-				resolvedID, err := utils.ResolveID(cfg, "user", userID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "user", userID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve user ID: %w", err)
 				}
@@ -53,7 +53,7 @@ func NewUpdateACECmd() *cobra.Command {
 			}
 
 			if groupID != "" && !utils.IsValidUUIDv4(groupID) {
-				resolvedID, err := utils.ResolveID(cfg, "group", groupID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "group", groupID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve group ID: %w", err)
 				}
@@ -61,7 +61,7 @@ func NewUpdateACECmd() *cobra.Command {
 			}
 
 			if roleID != "" && !utils.IsValidUUIDv4(roleID) {
-				resolvedID, err := utils.ResolveID(cfg, "role", roleID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "role", roleID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve role ID: %w", err)
 				}
@@ -118,7 +118,7 @@ func NewUpdateACECmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateACE", []string{aceID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateACE", []string{aceID}, payload)
 			return nil
 		},
 	}
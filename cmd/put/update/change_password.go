@@ -60,7 +60,7 @@ gns3util -s https://controller:3080 user change-password my-user -p "newpassword
 			var username string
 
 			if useFuzzy {
-				rawData, _, err := utils.CallClient(cfg, "getUsers", nil, nil)
+				rawData, _, err := utils.CallClient(cmd.Context(), cfg, "getUsers", nil, nil)
 				if err != nil {
 					fmt.Printf("%s %v\n", messageUtils.ErrorMsg("Error"), err)
 					return
@@ -106,7 +106,7 @@ gns3util -s https://controller:3080 user change-password my-user -p "newpassword
 				username = args[0]
 
 				if !utils.IsValidUUIDv4(args[0]) {
-					userID, err = utils.ResolveID(cfg, "user", args[0], nil)
+					userID, err = utils.ResolveID(cmd.Context(), cfg, "user", args[0], nil)
 					if err != nil {
 						fmt.Println(err)
 						return
@@ -147,7 +147,7 @@ gns3util -s https://controller:3080 user change-password my-user -p "newpassword
 				return
 			}
 
-			utils.ExecuteAndPrintWithBody(cfg, "updateUser", []string{userID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateUser", []string{userID}, payload)
 		},
 	}
 
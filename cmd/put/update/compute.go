@@ -80,7 +80,7 @@ func NewUpdateComputeCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateCompute", []string{computeIDArg}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateCompute", []string{computeIDArg}, payload)
 			return nil
 		},
 	}
@@ -36,7 +36,7 @@ func NewUpdateDrawingCmd() *cobra.Command {
 			drawingID := args[1]
 
 			if !utils.IsValidUUIDv4(projectID) {
-				resolvedID, err := utils.ResolveID(cfg, "project", projectID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -74,7 +74,7 @@ func NewUpdateDrawingCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateDrawing", []string{projectID, drawingID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateDrawing", []string{projectID, drawingID}, payload)
 			return nil
 		},
 	}
@@ -31,7 +31,7 @@ func NewUpdateGroupCmd() *cobra.Command {
 			groupID := args[0]
 
 			if !utils.IsValidUUIDv4(groupID) {
-				resolvedID, err := utils.ResolveID(cfg, "group", groupID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "group", groupID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve group ID: %w", err)
 				}
@@ -56,7 +56,7 @@ func NewUpdateGroupCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateGroup", []string{groupID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateGroup", []string{groupID}, payload)
 			return nil
 		},
 	}
@@ -50,7 +50,7 @@ func NewUpdateIOULicenseCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateIOULicense", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateIOULicense", nil, payload)
 			return nil
 		},
 	}
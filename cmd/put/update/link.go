@@ -35,7 +35,7 @@ func NewUpdateLinkCmd() *cobra.Command {
 				return fmt.Errorf("invalid JSON data: %w", err)
 			}
 
-			utils.ExecuteAndPrintWithBody(cfg, "updateLink", []string{projectID, linkID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateLink", []string{projectID, linkID}, payload)
 			return nil
 		},
 	}
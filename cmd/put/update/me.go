@@ -56,7 +56,7 @@ func NewUpdateMeCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateMe", nil, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateMe", nil, payload)
 			return nil
 		},
 	}
@@ -53,7 +53,7 @@ func NewUpdateNodeCmd() *cobra.Command {
 			nodeID := args[1]
 
 			if !utils.IsValidUUIDv4(projectID) {
-				resolvedID, err := utils.ResolveID(cfg, "project", projectID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -61,7 +61,7 @@ func NewUpdateNodeCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(nodeID) {
-				resolvedID, err := utils.ResolveID(cfg, "node", args[1], []string{projectID})
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 				if err != nil {
 					return fmt.Errorf("failed to resolve node ID: %w", err)
 				}
@@ -172,7 +172,7 @@ func NewUpdateNodeCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateNode", []string{projectID, nodeID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateNode", []string{projectID, nodeID}, payload)
 			return nil
 		},
 	}
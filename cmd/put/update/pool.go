@@ -30,7 +30,7 @@ func NewUpdatePoolCmd() *cobra.Command {
 
 			poolID := args[0]
 			if !utils.IsValidUUIDv4(args[0]) {
-				poolID, err = utils.ResolveID(cfg, "pool", args[0], nil)
+				poolID, err = utils.ResolveID(cmd.Context(), cfg, "pool", args[0], nil)
 				if err != nil {
 					return err
 				}
@@ -54,7 +54,7 @@ func NewUpdatePoolCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updatePool", []string{poolID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updatePool", []string{poolID}, payload)
 			return nil
 		},
 	}
@@ -47,7 +47,7 @@ func NewUpdateProjectCmd() *cobra.Command {
 			projectIDArg := args[0]
 
 			if !utils.IsValidUUIDv4(projectIDArg) {
-				resolvedID, err := utils.ResolveID(cfg, "project", projectIDArg, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "project", projectIDArg, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -128,7 +128,7 @@ func NewUpdateProjectCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateProject", []string{projectIDArg}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateProject", []string{projectIDArg}, payload)
 			return nil
 		},
 	}
@@ -41,7 +41,7 @@ func NewUpdateQemuDiskImageCmd() *cobra.Command {
 			diskName := args[2]
 
 			if !utils.IsValidUUIDv4(projectID) {
-				resolvedID, err := utils.ResolveID(cfg, "project", projectID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "project", projectID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve project ID: %w", err)
 				}
@@ -49,7 +49,7 @@ func NewUpdateQemuDiskImageCmd() *cobra.Command {
 			}
 
 			if !utils.IsValidUUIDv4(nodeID) {
-				resolvedID, err := utils.ResolveID(cfg, "node", args[1], []string{projectID})
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "node", args[1], []string{projectID})
 				if err != nil {
 					return fmt.Errorf("failed to resolve node ID: %w", err)
 				}
@@ -121,7 +121,7 @@ func NewUpdateQemuDiskImageCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateQemuDiskImage", []string{projectID, nodeID, diskName}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateQemuDiskImage", []string{projectID, nodeID, diskName}, payload)
 			return nil
 		},
 	}
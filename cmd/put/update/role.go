@@ -32,7 +32,7 @@ func NewUpdateRoleCmd() *cobra.Command {
 			roleID := args[0]
 
 			if !utils.IsValidUUIDv4(roleID) {
-				resolvedID, err := utils.ResolveID(cfg, "role", roleID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "role", roleID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve role ID: %w", err)
 				}
@@ -63,7 +63,7 @@ func NewUpdateRoleCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateRole", []string{roleID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateRole", []string{roleID}, payload)
 			return nil
 		},
 	}
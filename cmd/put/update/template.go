@@ -39,7 +39,7 @@ func NewUpdateTemplateCmd() *cobra.Command {
 			templateIDArg := args[0]
 
 			if !utils.IsValidUUIDv4(templateIDArg) {
-				resolvedID, err := utils.ResolveID(cfg, "template", templateIDArg, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "template", templateIDArg, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve template ID: %w", err)
 				}
@@ -92,7 +92,7 @@ func NewUpdateTemplateCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateTemplate", []string{templateIDArg}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateTemplate", []string{templateIDArg}, payload)
 			return nil
 		},
 	}
@@ -35,7 +35,7 @@ func NewUpdateUserCmd() *cobra.Command {
 			userID := args[0]
 
 			if !utils.IsValidUUIDv4(userID) {
-				resolvedID, err := utils.ResolveID(cfg, "user", userID, nil)
+				resolvedID, err := utils.ResolveID(cmd.Context(), cfg, "user", userID, nil)
 				if err != nil {
 					return fmt.Errorf("failed to resolve user ID: %w", err)
 				}
@@ -75,7 +75,7 @@ func NewUpdateUserCmd() *cobra.Command {
 					return fmt.Errorf("invalid JSON for --use-json: %w", err)
 				}
 			}
-			utils.ExecuteAndPrintWithBody(cfg, "updateUser", []string{userID}, payload)
+			utils.ExecuteAndPrintWithBody(cmd.Context(), cfg, "updateUser", []string{userID}, payload)
 			return nil
 		},
 	}
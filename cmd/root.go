@@ -1,14 +1,29 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stefanistkuhl/gns3util/cmd/auth"
 	"github.com/stefanistkuhl/gns3util/cmd/class"
 	"github.com/stefanistkuhl/gns3util/cmd/exercise"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/cluster"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
+	"github.com/stefanistkuhl/gns3util/pkg/ssh"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
 )
 
 var (
@@ -18,6 +33,34 @@ var (
 	raw      bool
 	noColor  bool
 	version  bool
+
+	retries      int
+	retryMaxWait time.Duration
+	retryPOST    bool
+
+	recordDir string
+	replayDir string
+
+	dryRun        bool
+	dryRunPlan    *api.DryRun
+	dryRunCleanup func()
+
+	trace     bool
+	traceCurl bool
+	tracer    *api.Tracer
+
+	caFile     string
+	clientCert string
+	clientKey  string
+
+	via       string
+	viaTunnel *ssh.Tunnel
+	proxy     string
+
+	contextName string
+	as          string
+
+	credentialHelper string
 )
 
 var Version = "1.2.7"
@@ -39,6 +82,9 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
+		if err := config.ApplyContext(cmd); err != nil {
+			return err
+		}
 		if err := validateGlobalFlags(); err != nil {
 			return err
 		}
@@ -62,14 +108,19 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
-		opts := config.GlobalOptions{
-			Server:   server,
-			Insecure: insecure,
-			KeyFile:  keyFile,
-			Raw:      raw,
+		opts, err := globalOptionsFromFlags()
+		if err != nil {
+			return err
+		}
+		if opts.Output = utils.OutputFlag(cmd); opts.Output != "" {
+			if _, err := utils.ParseOutputFormat(opts.Output); err != nil {
+				return err
+			}
 		}
-		ctx := config.WithGlobalOptions(cmd.Context(), opts)
-		cmd.SetContext(ctx)
+		if opts.List, err = utils.ListOptionsFromFlags(cmd); err != nil {
+			return err
+		}
+		cmd.SetContext(config.WithGlobalOptions(cmd.Context(), opts))
 
 		return nil
 	},
@@ -85,10 +136,26 @@ var rootCmd = &cobra.Command{
 func init() {
 	cobra.OnFinalize()
 	rootCmd.PersistentFlags().StringVarP(&server, "server", "s", "", "GNS3v3 Server URL (required for non cluster commands)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Use this context instead of the current one (env: GNS3UTIL_CONTEXT)")
+	rootCmd.PersistentFlags().StringVar(&as, "as", "", "Act as this user, one of the identities stored for the server with auth login")
 	rootCmd.PersistentFlags().StringVarP(&keyFile, "key-file", "k", "", "Set a location for a keyfile to use")
 	rootCmd.PersistentFlags().BoolVarP(&insecure, "insecure", "i", false, "Ignore unsigned SSL-Certificates")
 	rootCmd.PersistentFlags().BoolVarP(&raw, "raw", "", false, "Output all data in raw json")
 	rootCmd.PersistentFlags().BoolVarP(&noColor, "no-color", "", false, "Output all data in raw json and dont use a colored output")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", api.DefaultRetries, "Retry transient API failures (502/503/504/429, connection resets) this many times; only GET/PUT/DELETE are retried")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxWait, "retry-max-wait", api.DefaultRetryMaxWait, "Upper bound for a single backoff delay between retries, including Retry-After")
+	rootCmd.PersistentFlags().BoolVar(&retryPOST, "retry-post", false, "Also retry POST requests (may create duplicates if the server already processed the first attempt)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record every API request/response into this directory (tokens and passwords are redacted)")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Replay API responses recorded with --record from this directory instead of contacting the server")
+	rootCmd.PersistentFlags().StringVar(&caFile, "ca-file", "", "Trust the CA certificates in this PEM file in addition to the system ones")
+	rootCmd.PersistentFlags().StringVar(&clientCert, "client-cert", "", "PEM client certificate for servers that require client certificate authentication")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "", "PEM private key for --client-cert (defaults to the --client-cert file)")
+	rootCmd.PersistentFlags().StringVar(&credentialHelper, "credential-helper", "", "Command that provides usernames and passwords for logins, git credential helper protocol (env: GNS3UTIL_CREDENTIAL_HELPER)")
+	rootCmd.PersistentFlags().StringVar(&via, "via", "", "Reach the server through an SSH tunnel, e.g. ssh://user@bastion:22 (keys from ssh-agent or ~/.ssh, ?key=path for another key)")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "Send API requests through this http, https or socks5 proxy (env: GNS3_PROXY, otherwise HTTPS_PROXY/HTTP_PROXY/NO_PROXY apply)")
+	rootCmd.PersistentFlags().BoolVar(&trace, "trace", false, "Log every API request and response to stderr with timing and truncated bodies (also GNS3UTIL_TRACE=1)")
+	rootCmd.PersistentFlags().BoolVar(&traceCurl, "trace-curl", false, "Like --trace and also print an equivalent curl command for each request (also GNS3UTIL_TRACE=curl)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Do not send any changes to the server; GET requests still go through and the planned changes are printed at the end")
 	rootCmd.Flags().BoolVarP(&version, "version", "V", false, "Print version information")
 
 	rootCmd.AddCommand(auth.NewAuthCmdGroup())
@@ -120,24 +187,162 @@ func init() {
 
 	rootCmd.AddCommand(NewClusterCmdGroup())
 	rootCmd.AddCommand(NewShareCmdGroup())
+	rootCmd.AddCommand(NewDevCmdGroup())
+
+	rootCmd.AddCommand(NewAPICmd())
+	rootCmd.AddCommand(NewRunCmd())
+	rootCmd.AddCommand(NewApplyCmd())
+	rootCmd.AddCommand(NewExportCmdGroup())
+	rootCmd.AddCommand(NewContextCmdGroup())
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// The first Ctrl-C cancels the context so bulk operations can stop and
+	// report what they finished; a second one falls through to the default
+	// handler and kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil {
 		fmt.Printf("%v\n", err)
 	}
+	if dryRunPlan != nil {
+		utils.PrintDryRunPlan(dryRunPlan)
+		dryRunCleanup()
+	}
+	if viaTunnel != nil {
+		_ = viaTunnel.Close()
+	}
+	if err != nil {
+		stop()
+		os.Exit(1)
+	}
+}
+
+func globalOptionsFromFlags() (config.GlobalOptions, error) {
+	opts := config.GlobalOptions{
+		Server:       server,
+		As:           as,
+		Insecure:     insecure,
+		KeyFile:      keyFile,
+		Raw:          raw,
+		NoColors:     noColor,
+		Retries:      retries,
+		RetriesSet:   true,
+		RetryMaxWait: retryMaxWait,
+		RetryPOST:    retryPOST,
+
+		CredentialHelper: authentication.CredentialHelper(credentialHelper),
+	}
+
+	err := credstore.UseKeyFile(keyFile)
+	if err != nil {
+		return opts, err
+	}
+	switch {
+	case recordDir != "":
+		opts.Cassette, err = api.NewRecorder(recordDir)
+	case replayDir != "":
+		opts.Cassette, err = api.NewReplayer(replayDir)
+	}
+	if err != nil {
+		return opts, err
+	}
+
+	if dryRun {
+		if dryRunPlan == nil {
+			// Local bookkeeping (cluster database and config) runs against
+			// throwaway copies so it stays consistent with the plan.
+			dryRunCleanup, err = cluster.UseScratchCopy()
+			if err != nil {
+				return opts, fmt.Errorf("failed to prepare dry run: %w", err)
+			}
+			dryRunPlan = api.NewDryRun()
+			fmt.Fprintf(os.Stderr, "%v No changes will be sent to the server\n", messageUtils.WarningMsg("Dry run"))
+		}
+		opts.DryRun = dryRunPlan
+	}
+
+	if tracer == nil {
+		env := strings.ToLower(os.Getenv("GNS3UTIL_TRACE"))
+		curl := traceCurl || env == "curl"
+		if trace || curl || (env != "" && env != "0" && env != "false") {
+			tracer = api.NewTracer(os.Stderr, curl)
+		}
+	}
+	opts.Trace = tracer
+
+	if caFile != "" {
+		if opts.RootCAs, err = api.LoadCAFile(caFile); err != nil {
+			return opts, err
+		}
+	}
+	if clientCert != "" {
+		if opts.ClientCert, err = api.LoadClientCert(clientCert, clientKey); err != nil {
+			return opts, err
+		}
+	}
+	if via != "" {
+		if viaTunnel == nil {
+			if viaTunnel, err = ssh.ParseTunnelURL(via); err != nil {
+				return opts, err
+			}
+		}
+		opts.Dial = viaTunnel.DialContext
+	}
+
+	opts.Proxy = http.ProxyFromEnvironment
+	if p := cmp.Or(proxy, os.Getenv("GNS3_PROXY")); p != "" {
+		proxyURL, err := url.Parse(p)
+		if err != nil || proxyURL.Host == "" {
+			return opts, fmt.Errorf("invalid proxy URL %q", p)
+		}
+		opts.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Without pins the usual chain verification applies, so a keyfile that
+	// cannot be read is reported by the commands that need a token instead.
+	opts.CertPins, err = authentication.LoadCertPins(keyFile)
+	var conflict *authentication.CertPinConflictError
+	if errors.As(err, &conflict) {
+		fmt.Fprintf(os.Stderr, "%v %v\n", messageUtils.WarningMsg("Warning"), err)
+	}
+
+	// Clients log in again with saved or environment credentials when the
+	// server rejects an expired token.
+	reauthOpts := opts
+	opts.Reauth = func(ctx context.Context, server, expired string) (string, error) {
+		cfg := reauthOpts
+		cfg.Server = server
+		return authentication.Reauthenticate(ctx, cfg, expired)
+	}
+	return opts, nil
 }
 
 func validateGlobalFlags() error {
 	if noColor && !raw {
 		return fmt.Errorf("--no-color can only be used when --raw is also used")
 	}
+	if recordDir != "" && replayDir != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if clientKey != "" && clientCert == "" {
+		return fmt.Errorf("--client-key requires --client-cert")
+	}
+	if retries < 0 {
+		return fmt.Errorf("--retries must not be negative")
+	}
 	return nil
 }
 
 func validateRequiresServer() error {
 	if server == "" {
-		return fmt.Errorf("required flag(s) \"server\" not set")
+		return fmt.Errorf("required flag(s) \"server\" not set, pass --server or select a context with %s", messageUtils.Bold("context use"))
 	}
 	return nil
 }
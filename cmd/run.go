@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/stefanistkuhl/gns3util/pkg/workflow"
+)
+
+func NewRunCmd() *cobra.Command {
+	var vars []string
+	var cmd = &cobra.Command{
+		Use:   "run <workflow.yml>",
+		Short: "Run the jobs of a YAML workflow",
+		Long: `Run the jobs of a YAML workflow file against the server, in order.
+
+A workflow has vars, options and jobs. Each job has commands (create,
+update, delete, and open, close, start, stop for projects) with a repeat
+count and subcommands naming the resource (user, group, role, acl-rule,
+project, template, compute, pool) and its parameters. {{var}} in
+parameters and conditions is replaced by a variable, the repetition is
+in the variable named by options.iterations_var_name (i by default).
+
+Existing resources are selected with the id, friendly_name or filter
+parameters, a filter matching several needs <command>_all: true. A
+condition like "user.email.endswith('@example.com')" skips resources it
+is false for. The other parameters are the request body.
+
+All requests share one login. With --dry-run nothing is changed and the
+planned requests are printed at the end. See scripts/test.yml for an
+example.`,
+		Example: `  gns3util -s https://controller:3080 run scripts/test.yml
+  gns3util -s https://controller:3080 run setup.yml --var DEFAULT_EMAIL_DOMAIN=lab.local --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetGlobalOptionsFromContext(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get global options: %w", err)
+			}
+			w, err := workflow.Load(args[0])
+			if err != nil {
+				return err
+			}
+			for _, v := range vars {
+				key, value, ok := strings.Cut(v, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("invalid --var %q, use KEY=VALUE", v)
+				}
+				w.Vars[key] = value
+			}
+
+			if w.Options.Name != "" {
+				fmt.Printf("%v %s\n", messageUtils.Bold("Workflow"), messageUtils.Highlight(w.Options.Name))
+				if w.Options.Description != "" {
+					fmt.Println(w.Options.Description)
+				}
+				fmt.Println()
+			}
+
+			results, runErr := workflow.Run(cmd.Context(), cfg, w)
+			if len(results) > 0 {
+				fmt.Println()
+				utils.PrintTable(results, []utils.Column[workflow.JobResult]{
+					{Header: "Job", Value: func(r workflow.JobResult) string { return r.Name }},
+					{Header: "Done", Value: func(r workflow.JobResult) string { return strconv.Itoa(r.Done) }},
+					{Header: "Skipped", Value: func(r workflow.JobResult) string { return strconv.Itoa(r.Skipped) }},
+					{Header: "Failed", Value: func(r workflow.JobResult) string { return strconv.Itoa(r.Failed) }},
+				})
+			}
+			if runErr != nil {
+				return runErr
+			}
+			failed := 0
+			for _, r := range results {
+				failed += r.Failed
+			}
+			if failed > 0 {
+				return fmt.Errorf("%v %d step(s) failed", messageUtils.ErrorMsg("Workflow finished with errors"), failed)
+			}
+			fmt.Printf("%v all %d job(s) finished\n", messageUtils.SuccessMsg("Workflow finished"), len(results))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Set a workflow variable, KEY=VALUE (repeatable, overrides vars of the file)")
+	return cmd
+}
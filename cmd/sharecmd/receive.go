@@ -43,7 +43,7 @@ func NewReceiveCmd() *cobra.Command {
 				ServerKey: dk.Priv, // needed to derive SAS on server
 			}
 
-			ctx, cancel := context.WithCancel(context.Background())
+			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
 
 			addr, errs, closeFn, err := srv.Listen(ctx, ":0")
@@ -139,7 +139,7 @@ func NewSendCmd() *cobra.Command {
 				srcDir = filepath.Join(home, ".gns3")
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
 			defer cancel()
 
 			target, label, err := selectReceiver(ctx, to, discoverTimeout)
@@ -19,6 +19,7 @@ func NewSystemCmdGroup() *cobra.Command {
 	systemCmd.AddCommand(get.NewGetStatisticsCmd())
 	systemCmd.AddCommand(get.NewGetNotificationsCmd())
 	systemCmd.AddCommand(get.NewGetIouLicenseCmd())
+	systemCmd.AddCommand(get.NewGetAPICoverageCmd())
 
 	// Post subcommands
 	systemCmd.AddCommand(post.NewCheckVersionCmd())
@@ -0,0 +1,160 @@
+// Package access keeps the users, groups, roles, pools and ACL of a
+// controller in line with a YAML manifest that refers to everything by
+// name. Export dumps the server in the same format.
+package access
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Manifest struct {
+	Users  []User  `yaml:"users,omitempty"`
+	Groups []Group `yaml:"groups,omitempty"`
+	Roles  []Role  `yaml:"roles,omitempty"`
+	Pools  []Pool  `yaml:"pools,omitempty"`
+	ACL    []ACE   `yaml:"acl,omitempty"`
+}
+
+// User fields left out of the manifest are not changed on the server.
+type User struct {
+	Username string  `yaml:"username"`
+	Email    *string `yaml:"email,omitempty"`
+	FullName *string `yaml:"full_name,omitempty"`
+	IsActive *bool   `yaml:"is_active,omitempty"`
+	// Password is only used to create the user, it is never compared.
+	Password string `yaml:"password,omitempty"`
+}
+
+// Group members are usernames. Without a members key the membership of the
+// group is left alone, an empty list removes every member.
+type Group struct {
+	Name    string   `yaml:"name"`
+	Members []string `yaml:"members"`
+}
+
+type Role struct {
+	Name        string   `yaml:"name"`
+	Description *string  `yaml:"description,omitempty"`
+	Privileges  []string `yaml:"privileges"`
+}
+
+// Pool projects are project names.
+type Pool struct {
+	Name     string   `yaml:"name"`
+	Projects []string `yaml:"projects"`
+}
+
+// ACE grants Role on Path to either User or Group. Pools and projects in
+// the path can be given by name, e.g. /pools/lab1.
+type ACE struct {
+	Path      string `yaml:"path"`
+	User      string `yaml:"user,omitempty"`
+	Group     string `yaml:"group,omitempty"`
+	Role      string `yaml:"role"`
+	Allowed   *bool  `yaml:"allowed,omitempty"`
+	Propagate *bool  `yaml:"propagate,omitempty"`
+}
+
+func (a ACE) subject() (string, string) {
+	if a.User != "" {
+		return "user", a.User
+	}
+	return "group", a.Group
+}
+
+func (a ACE) allowed() bool {
+	return a.Allowed == nil || *a.Allowed
+}
+
+func (a ACE) propagate() bool {
+	return a.Propagate == nil || *a.Propagate
+}
+
+// Load reads and validates the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Marshal returns the manifest as YAML.
+func (m *Manifest) Marshal() ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (m *Manifest) validate() error {
+	seen := make(map[string]bool)
+	unique := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s without a name", kind)
+		}
+		if seen[kind+"/"+name] {
+			return fmt.Errorf("%s %q is listed twice", kind, name)
+		}
+		seen[kind+"/"+name] = true
+		return nil
+	}
+	for _, u := range m.Users {
+		if err := unique("user", u.Username); err != nil {
+			return err
+		}
+	}
+	for _, g := range m.Groups {
+		if err := unique("group", g.Name); err != nil {
+			return err
+		}
+	}
+	for _, r := range m.Roles {
+		if err := unique("role", r.Name); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.Pools {
+		if err := unique("pool", p.Name); err != nil {
+			return err
+		}
+	}
+	for i, a := range m.ACL {
+		switch {
+		case !strings.HasPrefix(a.Path, "/"):
+			return fmt.Errorf("acl entry %d: path must start with /", i+1)
+		case (a.User == "") == (a.Group == ""):
+			return fmt.Errorf("acl entry %d: set exactly one of user and group", i+1)
+		case a.Role == "":
+			return fmt.Errorf("acl entry %d: role is required", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,632 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is one step of a plan. Details are the attributes it sets, like
+// `email: "old" -> "new"` or "+ member alice".
+type Change struct {
+	Action  Action
+	Kind    string
+	Name    string
+	Details []string
+	run     func(ctx context.Context, r *runner) error
+}
+
+type Plan struct {
+	Changes []Change
+	// Unmanaged counts the objects on the server that are not in the
+	// manifest and are kept because prune is off.
+	Unmanaged int
+
+	state *state
+}
+
+func (p *Plan) Count(a Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// Diff compares m with the server and returns the changes that make the
+// server match it. Creates and updates come in dependency order (users,
+// groups, roles, pools, ACL), deletes after them in reverse. With prune,
+// objects missing from the manifest are deleted, except built-in groups
+// and roles, superadmins, the logged in user and anything the manifest
+// still refers to.
+func Diff(ctx context.Context, c *sdk.Client, m *Manifest, prune bool) (*Plan, error) {
+	s, err := fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	p := &planner{
+		m:          m,
+		s:          s,
+		prune:      prune,
+		plan:       &Plan{state: s},
+		deletes:    make(map[string][]Change),
+		managed:    make(map[string]bool),
+		referenced: make(map[string]bool),
+	}
+	for _, u := range m.Users {
+		p.managed["user/"+u.Username] = true
+	}
+	for _, g := range m.Groups {
+		p.managed["group/"+g.Name] = true
+		for _, u := range g.Members {
+			p.referenced["user/"+u] = true
+		}
+	}
+	for _, r := range m.Roles {
+		p.managed["role/"+r.Name] = true
+	}
+	for _, pool := range m.Pools {
+		p.managed["pool/"+pool.Name] = true
+	}
+	for _, a := range m.ACL {
+		kind, name := a.subject()
+		p.referenced[kind+"/"+name] = true
+		p.referenced["role/"+a.Role] = true
+		if segs := strings.Split(s.namePath(a.Path), "/"); len(segs) > 2 && segs[1] == "pools" {
+			p.referenced["pool/"+segs[2]] = true
+		}
+	}
+
+	for _, step := range []func() error{p.users, p.groups, p.roles, p.pools, p.acl} {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	for _, kind := range []string{"ace", "pool", "role", "group", "user"} {
+		p.plan.Changes = append(p.plan.Changes, p.deletes[kind]...)
+	}
+	return p.plan, nil
+}
+
+type planner struct {
+	m          *Manifest
+	s          *state
+	prune      bool
+	plan       *Plan
+	deletes    map[string][]Change
+	managed    map[string]bool
+	referenced map[string]bool
+}
+
+func (p *planner) add(c Change) {
+	p.plan.Changes = append(p.plan.Changes, c)
+}
+
+// unmanaged plans the delete of an object the manifest does not list when
+// prune is on, otherwise it is only counted.
+func (p *planner) unmanaged(kind, name string, del func(ctx context.Context, r *runner) error) {
+	if p.managed[kind+"/"+name] || p.referenced[kind+"/"+name] {
+		return
+	}
+	if !p.prune {
+		p.plan.Unmanaged++
+		return
+	}
+	p.deletes[kind] = append(p.deletes[kind], Change{Action: Delete, Kind: kind, Name: name, run: del})
+}
+
+// exists reports whether an object is in the manifest or on the server.
+func (p *planner) exists(kind, name string) bool {
+	if p.managed[kind+"/"+name] {
+		return true
+	}
+	var ok bool
+	switch kind {
+	case "user":
+		_, ok = p.s.users[name]
+	case "group":
+		_, ok = p.s.groups[name]
+	case "role":
+		_, ok = p.s.roles[name]
+	case "pool":
+		_, ok = p.s.pools[name]
+	case "project":
+		_, ok = p.s.projects[name]
+	}
+	return ok
+}
+
+func (p *planner) users() error {
+	for _, u := range p.m.Users {
+		cur, ok := p.s.users[u.Username]
+		if !ok {
+			if u.Password == "" {
+				return fmt.Errorf("user %s does not exist yet and needs a password", u.Username)
+			}
+			var details []string
+			if u.Email != nil {
+				details = append(details, fmt.Sprintf("email: %q", *u.Email))
+			}
+			if u.FullName != nil {
+				details = append(details, fmt.Sprintf("full_name: %q", *u.FullName))
+			}
+			if u.IsActive != nil {
+				details = append(details, fmt.Sprintf("is_active: %t", *u.IsActive))
+			}
+			data := schemas.UserCreate{
+				Username: &u.Username,
+				IsActive: u.IsActive == nil || *u.IsActive,
+				Email:    u.Email,
+				FullName: u.FullName,
+				Password: &u.Password,
+			}
+			p.add(Change{Action: Create, Kind: "user", Name: u.Username, Details: details, run: func(ctx context.Context, r *runner) error {
+				created, err := r.c.Users().Create(ctx, data)
+				if err != nil {
+					return err
+				}
+				r.ids["user/"+u.Username] = created.UserID.String()
+				return nil
+			}})
+			continue
+		}
+
+		var data schemas.UserUpdate
+		var details []string
+		if u.Email != nil && deref(cur.Email) != *u.Email {
+			data.Email = u.Email
+			details = append(details, fmt.Sprintf("email: %q -> %q", deref(cur.Email), *u.Email))
+		}
+		if u.FullName != nil && deref(cur.FullName) != *u.FullName {
+			data.FullName = u.FullName
+			details = append(details, fmt.Sprintf("full_name: %q -> %q", deref(cur.FullName), *u.FullName))
+		}
+		if u.IsActive != nil && cur.IsActive != *u.IsActive {
+			data.IsActive = u.IsActive
+			details = append(details, fmt.Sprintf("is_active: %t -> %t", cur.IsActive, *u.IsActive))
+		}
+		if len(details) > 0 {
+			id := cur.UserID.String()
+			p.add(Change{Action: Update, Kind: "user", Name: u.Username, Details: details, run: func(ctx context.Context, r *runner) error {
+				_, err := r.c.Users().Update(ctx, id, data)
+				return err
+			}})
+		}
+	}
+
+	for _, name := range sortedKeys(p.s.users) {
+		u := p.s.users[name]
+		if u.IsSuperadmin || name == p.s.me {
+			continue
+		}
+		id := u.UserID.String()
+		p.unmanaged("user", name, func(ctx context.Context, r *runner) error {
+			return r.c.Users().Delete(ctx, id)
+		})
+	}
+	return nil
+}
+
+func (p *planner) groups() error {
+	for _, g := range p.m.Groups {
+		for _, u := range g.Members {
+			if !p.exists("user", u) {
+				return fmt.Errorf("group %s: unknown user %s", g.Name, u)
+			}
+		}
+		cur, ok := p.s.groups[g.Name]
+		if !ok {
+			var details []string
+			if len(g.Members) > 0 {
+				details = append(details, "members: "+strings.Join(g.Members, ", "))
+			}
+			p.add(Change{Action: Create, Kind: "group", Name: g.Name, Details: details, run: func(ctx context.Context, r *runner) error {
+				created, err := r.c.Groups().Create(ctx, g.Name)
+				if err != nil {
+					return err
+				}
+				id := created.UserGroupID.String()
+				r.ids["group/"+g.Name] = id
+				return r.each("user", g.Members, func(userID string) error {
+					return r.c.Groups().AddMember(ctx, id, userID)
+				})
+			}})
+			continue
+		}
+		if g.Members == nil {
+			continue
+		}
+		add, remove := diffNames(g.Members, p.s.members[g.Name])
+		if len(add)+len(remove) == 0 {
+			continue
+		}
+		id := cur.UserGroupID.String()
+		p.add(Change{Action: Update, Kind: "group", Name: g.Name, Details: setDetails("member", add, remove), run: func(ctx context.Context, r *runner) error {
+			if err := r.each("user", add, func(userID string) error {
+				return r.c.Groups().AddMember(ctx, id, userID)
+			}); err != nil {
+				return err
+			}
+			return r.each("user", remove, func(userID string) error {
+				return r.c.Groups().RemoveMember(ctx, id, userID)
+			})
+		}})
+	}
+
+	for _, name := range sortedKeys(p.s.groups) {
+		g := p.s.groups[name]
+		if g.IsBuiltin {
+			continue
+		}
+		id := g.UserGroupID.String()
+		p.unmanaged("group", name, func(ctx context.Context, r *runner) error {
+			return r.c.Groups().Delete(ctx, id)
+		})
+	}
+	return nil
+}
+
+func (p *planner) roles() error {
+	for _, role := range p.m.Roles {
+		for _, priv := range role.Privileges {
+			if _, ok := p.s.privileges[priv]; !ok {
+				return fmt.Errorf("role %s: unknown privilege %s", role.Name, priv)
+			}
+		}
+		cur, ok := p.s.roles[role.Name]
+		if !ok {
+			var details []string
+			if role.Description != nil {
+				details = append(details, fmt.Sprintf("description: %q", *role.Description))
+			}
+			if len(role.Privileges) > 0 {
+				details = append(details, "privileges: "+strings.Join(role.Privileges, ", "))
+			}
+			data := schemas.RoleCreate{Name: &role.Name, Description: role.Description}
+			p.add(Change{Action: Create, Kind: "role", Name: role.Name, Details: details, run: func(ctx context.Context, r *runner) error {
+				created, err := r.c.Roles().Create(ctx, data)
+				if err != nil {
+					return err
+				}
+				r.ids["role/"+role.Name] = created.RoleID
+				return r.each("privilege", role.Privileges, func(privID string) error {
+					return r.c.Roles().AddPrivilege(ctx, created.RoleID, privID)
+				})
+			}})
+			continue
+		}
+
+		var details []string
+		var data *schemas.RoleUpdate
+		if role.Description != nil && deref(cur.Description) != *role.Description {
+			data = &schemas.RoleUpdate{Description: role.Description}
+			details = append(details, fmt.Sprintf("description: %q -> %q", deref(cur.Description), *role.Description))
+		}
+		var add, remove []string
+		if role.Privileges != nil {
+			add, remove = diffNames(role.Privileges, p.s.rolePrivs[role.Name])
+			details = append(details, setDetails("privilege", add, remove)...)
+		}
+		if len(details) == 0 {
+			continue
+		}
+		if cur.IsBuiltin {
+			return fmt.Errorf("role %s is built in and can not be changed", role.Name)
+		}
+		id := cur.RoleID
+		p.add(Change{Action: Update, Kind: "role", Name: role.Name, Details: details, run: func(ctx context.Context, r *runner) error {
+			if data != nil {
+				if _, err := r.c.Roles().Update(ctx, id, *data); err != nil {
+					return err
+				}
+			}
+			if err := r.each("privilege", add, func(privID string) error {
+				return r.c.Roles().AddPrivilege(ctx, id, privID)
+			}); err != nil {
+				return err
+			}
+			return r.each("privilege", remove, func(privID string) error {
+				return r.c.Roles().RemovePrivilege(ctx, id, privID)
+			})
+		}})
+	}
+
+	for _, name := range sortedKeys(p.s.roles) {
+		role := p.s.roles[name]
+		if role.IsBuiltin {
+			continue
+		}
+		p.unmanaged("role", name, func(ctx context.Context, r *runner) error {
+			return r.c.Roles().Delete(ctx, role.RoleID)
+		})
+	}
+	return nil
+}
+
+func (p *planner) pools() error {
+	for _, pool := range p.m.Pools {
+		for _, proj := range pool.Projects {
+			if !p.exists("project", proj) {
+				return fmt.Errorf("pool %s: unknown project %s", pool.Name, proj)
+			}
+		}
+		cur, ok := p.s.pools[pool.Name]
+		if !ok {
+			var details []string
+			if len(pool.Projects) > 0 {
+				details = append(details, "projects: "+strings.Join(pool.Projects, ", "))
+			}
+			p.add(Change{Action: Create, Kind: "pool", Name: pool.Name, Details: details, run: func(ctx context.Context, r *runner) error {
+				created, err := r.c.Pools().Create(ctx, pool.Name)
+				if err != nil {
+					return err
+				}
+				r.ids["pool/"+pool.Name] = created.ResourcePoolID
+				return r.each("project", pool.Projects, func(projectID string) error {
+					return r.c.Pools().AddResource(ctx, created.ResourcePoolID, projectID)
+				})
+			}})
+			continue
+		}
+		if pool.Projects == nil {
+			continue
+		}
+		add, remove := diffNames(pool.Projects, p.s.poolProjs[pool.Name])
+		if len(add)+len(remove) == 0 {
+			continue
+		}
+		id := cur.ResourcePoolID
+		p.add(Change{Action: Update, Kind: "pool", Name: pool.Name, Details: setDetails("project", add, remove), run: func(ctx context.Context, r *runner) error {
+			if err := r.each("project", add, func(projectID string) error {
+				return r.c.Pools().AddResource(ctx, id, projectID)
+			}); err != nil {
+				return err
+			}
+			return r.each("project", remove, func(projectID string) error {
+				return r.c.Pools().RemoveResource(ctx, id, projectID)
+			})
+		}})
+	}
+
+	for _, name := range sortedKeys(p.s.pools) {
+		id := p.s.pools[name].ResourcePoolID
+		p.unmanaged("pool", name, func(ctx context.Context, r *runner) error {
+			return r.c.Pools().Delete(ctx, id)
+		})
+	}
+	return nil
+}
+
+func aceKey(a ACE) string {
+	kind, name := a.subject()
+	return strings.Join([]string{kind, name, a.Role, a.Path}, "|")
+}
+
+func aceName(a ACE) string {
+	kind, name := a.subject()
+	return fmt.Sprintf("%s %s as %s on %s", kind, name, a.Role, a.Path)
+}
+
+func (p *planner) acl() error {
+	current := make(map[string][]serverACE)
+	for _, a := range p.s.acl {
+		current[aceKey(a.ACE)] = append(current[aceKey(a.ACE)], a)
+	}
+
+	for _, a := range p.m.ACL {
+		a.Path = p.s.namePath(a.Path)
+		kind, name := a.subject()
+		if !p.exists(kind, name) {
+			return fmt.Errorf("acl entry %s: unknown %s %s", aceName(a), kind, name)
+		}
+		if !p.exists("role", a.Role) {
+			return fmt.Errorf("acl entry %s: unknown role %s", aceName(a), a.Role)
+		}
+		if segs := strings.Split(a.Path, "/"); len(segs) > 2 && (segs[1] == "pools" || segs[1] == "projects") {
+			target := strings.TrimSuffix(segs[1], "s")
+			if !p.exists(target, segs[2]) {
+				return fmt.Errorf("acl entry %s: unknown %s %s", aceName(a), target, segs[2])
+			}
+		}
+
+		key := aceKey(a)
+		if matches := current[key]; len(matches) > 0 {
+			cur := matches[0]
+			current[key] = matches[1:]
+			var details []string
+			if cur.allowed() != a.allowed() {
+				details = append(details, fmt.Sprintf("allowed: %t -> %t", cur.allowed(), a.allowed()))
+			}
+			if cur.propagate() != a.propagate() {
+				details = append(details, fmt.Sprintf("propagate: %t -> %t", cur.propagate(), a.propagate()))
+			}
+			if len(details) > 0 {
+				p.add(Change{Action: Update, Kind: "ace", Name: aceName(a), Details: details, run: func(ctx context.Context, r *runner) error {
+					data, err := r.ace(a)
+					if err != nil {
+						return err
+					}
+					_, err = r.c.ACL().Update(ctx, cur.id, schemas.ACEUpdate(data))
+					return err
+				}})
+			}
+			continue
+		}
+
+		var details []string
+		if !a.allowed() {
+			details = append(details, "allowed: false")
+		}
+		if !a.propagate() {
+			details = append(details, "propagate: false")
+		}
+		p.add(Change{Action: Create, Kind: "ace", Name: aceName(a), Details: details, run: func(ctx context.Context, r *runner) error {
+			data, err := r.ace(a)
+			if err != nil {
+				return err
+			}
+			_, err = r.c.ACL().Create(ctx, data)
+			return err
+		}})
+	}
+
+	for _, a := range p.s.acl {
+		key := aceKey(a.ACE)
+		rest := current[key]
+		if len(rest) == 0 || rest[0].id != a.id {
+			continue
+		}
+		current[key] = rest[1:]
+		if !p.prune {
+			p.plan.Unmanaged++
+			continue
+		}
+		id := a.id
+		p.deletes["ace"] = append(p.deletes["ace"], Change{Action: Delete, Kind: "ace", Name: aceName(a.ACE), run: func(ctx context.Context, r *runner) error {
+			return r.c.ACL().Delete(ctx, id)
+		}})
+	}
+	return nil
+}
+
+// diffNames returns the names of want missing from have and the names of
+// have missing from want.
+func diffNames(want, have []string) (add, remove []string) {
+	in := func(list []string, s string) bool {
+		for _, e := range list {
+			if e == s {
+				return true
+			}
+		}
+		return false
+	}
+	for _, w := range want {
+		if !in(have, w) && !in(add, w) {
+			add = append(add, w)
+		}
+	}
+	for _, h := range have {
+		if !in(want, h) {
+			remove = append(remove, h)
+		}
+	}
+	return add, remove
+}
+
+func setDetails(noun string, add, remove []string) []string {
+	var details []string
+	for _, a := range add {
+		details = append(details, fmt.Sprintf("+ %s %s", noun, a))
+	}
+	for _, r := range remove {
+		details = append(details, fmt.Sprintf("- %s %s", noun, r))
+	}
+	return details
+}
+
+type runner struct {
+	c   *sdk.Client
+	ids map[string]string
+}
+
+// Apply runs the changes of the plan in order and calls done after each.
+// It stops at the first failed change since later ones may depend on it.
+func (p *Plan) Apply(ctx context.Context, c *sdk.Client, done func(Change, error)) error {
+	r := &runner{c: c, ids: make(map[string]string)}
+	s := p.state
+	for name, u := range s.users {
+		r.ids["user/"+name] = u.UserID.String()
+	}
+	for name, g := range s.groups {
+		r.ids["group/"+name] = g.UserGroupID.String()
+	}
+	for name, role := range s.roles {
+		r.ids["role/"+name] = role.RoleID
+	}
+	for name, id := range s.privileges {
+		r.ids["privilege/"+name] = id
+	}
+	for name, pool := range s.pools {
+		r.ids["pool/"+name] = pool.ResourcePoolID
+	}
+	for name, id := range s.projects {
+		r.ids["project/"+name] = id
+	}
+
+	for _, change := range p.Changes {
+		err := change.run(ctx, r)
+		if done != nil {
+			done(change, err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s %s: %w", change.Action, change.Kind, change.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *runner) id(kind, name string) (string, error) {
+	if id, ok := r.ids[kind+"/"+name]; ok {
+		return id, nil
+	}
+	if _, err := uuid.Parse(name); err == nil {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown %s %s", kind, name)
+}
+
+func (r *runner) each(kind string, names []string, fn func(id string) error) error {
+	for _, name := range names {
+		id, err := r.id(kind, name)
+		if err != nil {
+			return err
+		}
+		if err := fn(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ace builds the request body for a, with the names of the manifest
+// replaced by ids.
+func (r *runner) ace(a ACE) (schemas.ACECreate, error) {
+	kind, name := a.subject()
+	subjectID, err := r.id(kind, name)
+	if err != nil {
+		return schemas.ACECreate{}, err
+	}
+	roleID, err := r.id("role", a.Role)
+	if err != nil {
+		return schemas.ACECreate{}, err
+	}
+	path := a.Path
+	if segs := strings.Split(path, "/"); len(segs) > 2 && (segs[1] == "pools" || segs[1] == "projects") {
+		if segs[2], err = r.id(strings.TrimSuffix(segs[1], "s"), segs[2]); err != nil {
+			return schemas.ACECreate{}, err
+		}
+		path = strings.Join(segs, "/")
+	}
+	allowed, propagate := a.allowed(), a.propagate()
+	data := schemas.ACECreate{ACEType: &kind, Path: &path, Allowed: &allowed, Propagate: &propagate, RoleID: &roleID}
+	if kind == "user" {
+		data.UserID = &subjectID
+	} else {
+		data.GroupID = &subjectID
+	}
+	return data, nil
+}
@@ -0,0 +1,208 @@
+package access
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"empty", "", ""},
+		{"valid", "users:\n  - username: alice\ngroups:\n  - name: students\n    members: [alice]\nacl:\n  - path: /pools/lab\n    group: students\n    role: User\n", ""},
+		{"unknown field", "users:\n  - username: alice\n    mail: a@lab\n", "mail"},
+		{"duplicate", "roles:\n  - name: r\n  - name: r\n", "listed twice"},
+		{"no name", "pools:\n  - projects: [lab]\n", "without a name"},
+		{"relative path", "acl:\n  - path: pools\n    user: alice\n    role: User\n", "must start with /"},
+		{"user and group", "acl:\n  - path: /\n    user: alice\n    group: students\n    role: User\n", "exactly one"},
+		{"no role", "acl:\n  - path: /\n    user: alice\n", "role is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiffNames(t *testing.T) {
+	tests := []struct {
+		want, have  []string
+		add, remove []string
+	}{
+		{[]string{"a", "b"}, []string{"b", "c"}, []string{"a"}, []string{"c"}},
+		{[]string{"a", "a"}, nil, []string{"a"}, nil},
+		{nil, []string{"a"}, nil, []string{"a"}},
+		{[]string{"a"}, []string{"a"}, nil, nil},
+	}
+	for _, tt := range tests {
+		add, remove := diffNames(tt.want, tt.have)
+		if !slices.Equal(add, tt.add) || !slices.Equal(remove, tt.remove) {
+			t.Errorf("diffNames(%v, %v) = %v, %v, want %v, %v", tt.want, tt.have, add, remove, tt.add, tt.remove)
+		}
+	}
+}
+
+const manifest = `
+users:
+  - username: alice
+    email: alice@lab.local
+    password: Password123!
+  - username: bob
+    password: Password123!
+groups:
+  - name: students
+    members: [alice, bob]
+roles:
+  - name: lab-user
+    description: Works in the lab pool
+    privileges: [Project.Audit, Node.Console]
+pools:
+  - name: lab-pool
+    projects: [lab1]
+acl:
+  - path: /pools/lab-pool
+    group: students
+    role: lab-user
+`
+
+// changes lists the changes of plan as "action kind name".
+func changes(plan *Plan) []string {
+	var out []string
+	for _, c := range plan.Changes {
+		out = append(out, string(c.Action)+" "+c.Kind+" "+c.Name)
+	}
+	return out
+}
+
+func TestPlanApply(t *testing.T) {
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	defer ts.Close()
+	ctx := context.Background()
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(ctx, cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	c, err := sdk.NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := "lab1"
+	if _, err := c.Projects().Create(ctx, schemas.ProjectCreate{Name: &project}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := Diff(ctx, c, m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"create user alice", "create user bob", "create group students",
+		"create role lab-user", "create pool lab-pool", "create ace " + aceName(m.ACL[0]),
+	}
+	if got := changes(plan); !slices.Equal(got, want) {
+		t.Fatalf("Diff() = %q, want %q", got, want)
+	}
+
+	// A dry run plans the requests without changing the server.
+	dryCfg := cfg
+	dryCfg.DryRun = api.NewDryRun()
+	dry, err := sdk.NewFromConfig(dryCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(ctx, dry, nil); err != nil {
+		t.Fatalf("dry run Apply() = %v", err)
+	}
+	if len(dryCfg.DryRun.Planned()) == 0 {
+		t.Error("the dry run planned no requests")
+	}
+	if again, err := Diff(ctx, c, m, false); err != nil || len(again.Changes) != len(want) {
+		t.Fatalf("the dry run changed the server: %q, %v", changes(again), err)
+	}
+
+	var applied []string
+	if err := plan.Apply(ctx, c, func(ch Change, err error) {
+		if err == nil {
+			applied = append(applied, string(ch.Action)+" "+ch.Kind+" "+ch.Name)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(applied, want) {
+		t.Errorf("applied %q, want %q", applied, want)
+	}
+	if again, err := Diff(ctx, c, m, false); err != nil || len(again.Changes) != 0 {
+		t.Fatalf("Diff() after Apply = %q, %v, want no changes", changes(again), err)
+	}
+
+	exported, err := Export(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := Diff(ctx, c, exported, true); err != nil || len(again.Changes) != 0 {
+		t.Errorf("Diff() of the export = %q, %v, want no changes", changes(again), err)
+	}
+
+	// Dropping bob from the manifest removes him from the group, and with
+	// prune from the server.
+	m.Users = m.Users[:1]
+	email := "alice@example.com"
+	m.Users[0].Email = &email
+	m.Groups[0].Members = []string{"alice"}
+	plan, err = Diff(ctx, c, m, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"update user alice", "update group students", "delete user bob"}
+	if got := changes(plan); !slices.Equal(got, want) {
+		t.Fatalf("Diff() with prune = %q, want %q", got, want)
+	}
+	if err := plan.Apply(ctx, c, nil); err != nil {
+		t.Fatal(err)
+	}
+	users, err := c.Users().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"admin", "alice"}) {
+		t.Errorf("users after prune = %v, want [admin alice]", names)
+	}
+	if again, err := Diff(ctx, c, m, true); err != nil || len(again.Changes) != 0 {
+		t.Errorf("Diff() after prune = %q, %v, want no changes", changes(again), err)
+	}
+}
@@ -0,0 +1,226 @@
+package access
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+// state is what the controller has, keyed by name like the manifest.
+type state struct {
+	me         string
+	users      map[string]schemas.UserResponse
+	groups     map[string]schemas.UserGroupResponse
+	members    map[string][]string
+	roles      map[string]schemas.RoleResponse
+	rolePrivs  map[string][]string
+	privileges map[string]string
+	pools      map[string]schemas.ResourcePoolResponse
+	poolProjs  map[string][]string
+	projects   map[string]string
+	acl        []serverACE
+
+	// names maps the ids of every object above to its name.
+	names map[string]string
+}
+
+type serverACE struct {
+	ACE
+	id string
+}
+
+func fetch(ctx context.Context, c *sdk.Client) (*state, error) {
+	s := &state{
+		users:      make(map[string]schemas.UserResponse),
+		groups:     make(map[string]schemas.UserGroupResponse),
+		members:    make(map[string][]string),
+		roles:      make(map[string]schemas.RoleResponse),
+		rolePrivs:  make(map[string][]string),
+		privileges: make(map[string]string),
+		pools:      make(map[string]schemas.ResourcePoolResponse),
+		poolProjs:  make(map[string][]string),
+		projects:   make(map[string]string),
+		names:      make(map[string]string),
+	}
+
+	me, err := c.Users().Me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.me = me.Username
+
+	users, err := c.Users().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		s.users[u.Username] = u
+		s.names[u.UserID.String()] = u.Username
+	}
+
+	groups, err := c.Groups().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		s.groups[g.Name] = g
+		s.names[g.UserGroupID.String()] = g.Name
+		members, err := c.Groups().Members(ctx, g.UserGroupID.String())
+		if err != nil {
+			return nil, err
+		}
+		s.members[g.Name] = []string{}
+		for _, u := range members {
+			s.members[g.Name] = append(s.members[g.Name], u.Username)
+		}
+	}
+
+	privileges, err := c.Privileges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range privileges {
+		s.privileges[p.Name] = p.PrivilegeID
+	}
+
+	roles, err := c.Roles().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		s.roles[r.Name] = r
+		s.names[r.RoleID] = r.Name
+		privs, err := c.Roles().Privileges(ctx, r.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		s.rolePrivs[r.Name] = []string{}
+		for _, p := range privs {
+			s.rolePrivs[r.Name] = append(s.rolePrivs[r.Name], p.Name)
+		}
+	}
+
+	projects, err := c.Projects().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		s.projects[p.Name] = p.ProjectID
+		s.names[p.ProjectID] = p.Name
+	}
+
+	pools, err := c.Pools().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pools {
+		s.pools[p.Name] = p
+		s.names[p.ResourcePoolID] = p.Name
+		resources, err := c.Pools().Resources(ctx, p.ResourcePoolID)
+		if err != nil {
+			return nil, err
+		}
+		s.poolProjs[p.Name] = []string{}
+		for _, r := range resources {
+			name := r.Name
+			if n, ok := s.names[r.ResourceID]; ok {
+				name = n
+			}
+			s.poolProjs[p.Name] = append(s.poolProjs[p.Name], name)
+		}
+	}
+
+	acl, err := c.ACL().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range acl {
+		allowed, propagate := a.Allowed, a.Propagate
+		ace := ACE{
+			Path:      s.namePath(a.Path),
+			Role:      s.name(deref(a.RoleID)),
+			Allowed:   &allowed,
+			Propagate: &propagate,
+		}
+		if a.ACEType == "user" {
+			ace.User = s.name(deref(a.UserID))
+		} else {
+			ace.Group = s.name(deref(a.GroupID))
+		}
+		s.acl = append(s.acl, serverACE{ACE: ace, id: a.ACLID})
+	}
+	return s, nil
+}
+
+func (s *state) name(id string) string {
+	if n, ok := s.names[id]; ok {
+		return n
+	}
+	return id
+}
+
+// namePath replaces the pool or project id of an ACE path by its name.
+func (s *state) namePath(path string) string {
+	segs := strings.Split(path, "/")
+	if len(segs) > 2 && (segs[1] == "pools" || segs[1] == "projects") {
+		segs[2] = s.name(segs[2])
+	}
+	return strings.Join(segs, "/")
+}
+
+// Export returns the access control of the server as a manifest. Built-in
+// roles are left out since they can not be changed, ACEs still refer to
+// them by name.
+func Export(ctx context.Context, c *sdk.Client) (*Manifest, error) {
+	s, err := fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	for _, name := range sortedKeys(s.users) {
+		u := s.users[name]
+		active := u.IsActive
+		m.Users = append(m.Users, User{Username: name, Email: u.Email, FullName: u.FullName, IsActive: &active})
+	}
+	for _, name := range sortedKeys(s.groups) {
+		m.Groups = append(m.Groups, Group{Name: name, Members: sorted(s.members[name])})
+	}
+	for _, name := range sortedKeys(s.roles) {
+		if r := s.roles[name]; !r.IsBuiltin {
+			m.Roles = append(m.Roles, Role{Name: name, Description: r.Description, Privileges: sorted(s.rolePrivs[name])})
+		}
+	}
+	for _, name := range sortedKeys(s.pools) {
+		m.Pools = append(m.Pools, Pool{Name: name, Projects: sorted(s.poolProjs[name])})
+	}
+	for _, a := range s.acl {
+		m.ACL = append(m.ACL, a.ACE)
+	}
+	sort.SliceStable(m.ACL, func(i, j int) bool { return m.ACL[i].Path < m.ACL[j].Path })
+	return m, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sorted(s []string) []string {
+	out := append([]string{}, s...)
+	sort.Strings(out)
+	return out
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const redacted = "REDACTED"
+
+var (
+	jsonSecretRe = regexp.MustCompile(`("(?:password|access_token|refresh_token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	formSecretRe = regexp.MustCompile(`((?:^|&)password=)[^&]*`)
+	pathCharRe   = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// Interaction is one request/response pair stored in a cassette file.
+type Interaction struct {
+	Seq      int               `json:"seq"`
+	Request  CassetteRequest   `json:"request"`
+	Response *CassetteResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	// Transient marks a recorded transport error that Do would retry.
+	Transient bool `json:"transient,omitempty"`
+}
+
+type CassetteRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   cassetteBody `json:"body"`
+}
+
+type CassetteResponse struct {
+	StatusCode int          `json:"status_code"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       cassetteBody `json:"body"`
+}
+
+// cassetteBody keeps text bodies readable in the cassette and falls back to
+// base64 for binary payloads like project archives.
+type cassetteBody []byte
+
+func (b cassetteBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(map[string]string{"text": string(b)})
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *cassetteBody) UnmarshalJSON(data []byte) error {
+	var v map[string]string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if enc, ok := v["base64"]; ok {
+		raw, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return err
+		}
+		*b = raw
+		return nil
+	}
+	*b = []byte(v["text"])
+	return nil
+}
+
+// Cassette records every request made through its transport into a
+// directory, or replays a previously recorded directory without touching the
+// network. One cassette is shared by all clients of a command run so the
+// sequence numbers follow the order in which requests were issued.
+type Cassette struct {
+	dir    string
+	replay bool
+
+	mu      sync.Mutex
+	seq     int
+	pending map[string][]*Interaction
+}
+
+func NewRecorder(dir string) (*Cassette, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory %q: %w", dir, err)
+	}
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	return &Cassette{dir: dir, seq: len(existing)}, nil
+}
+
+func NewReplayer(dir string) (*Cassette, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no cassette files found in %q", dir)
+	}
+
+	var interactions []*Interaction
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %q: %w", f, err)
+		}
+		interactions = append(interactions, &in)
+	}
+	sort.SliceStable(interactions, func(i, j int) bool { return interactions[i].Seq < interactions[j].Seq })
+
+	c := &Cassette{dir: dir, replay: true, pending: make(map[string][]*Interaction)}
+	for _, in := range interactions {
+		key := interactionKey(in.Request.Method, in.Request.URL)
+		c.pending[key] = append(c.pending[key], in)
+	}
+	return c, nil
+}
+
+func (c *Cassette) Replaying() bool {
+	return c != nil && c.replay
+}
+
+func (c *Cassette) Dir() string {
+	return c.dir
+}
+
+// Transport wraps base for recording; when replaying base is never used.
+func (c *Cassette) Transport(base http.RoundTripper) http.RoundTripper {
+	if c.replay {
+		return replayTransport{c}
+	}
+	return recordTransport{c: c, base: base}
+}
+
+func interactionKey(method, rawURL string) string {
+	return method + " " + rawURL
+}
+
+func redactBody(b []byte) []byte {
+	b = jsonSecretRe.ReplaceAll(b, []byte(`$1"`+redacted+`"`))
+	return formSecretRe.ReplaceAll(b, []byte(`${1}`+redacted))
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	if out.Get("Authorization") != "" {
+		out.Set("Authorization", "Bearer "+redacted)
+	}
+	return out
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+type recordTransport struct {
+	c    *Cassette
+	base http.RoundTripper
+}
+
+func (t recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.c.mu.Lock()
+	t.c.seq++
+	in := &Interaction{
+		Seq: t.c.seq,
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(body),
+		},
+	}
+	t.c.mu.Unlock()
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		in.Error = err.Error()
+		in.Transient = shouldRetry(err)
+		if werr := t.c.write(in); werr != nil {
+			fmt.Fprintf(os.Stderr, "failed to write cassette: %v\n", werr)
+		}
+		return nil, err
+	}
+
+	in.Response = &CassetteResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, c: t.c, in: in}
+	return resp, nil
+}
+
+// recordingBody writes the interaction once the caller is done with the
+// response, so streamed bodies are stored with whatever was actually read.
+type recordingBody struct {
+	io.ReadCloser
+	c    *Cassette
+	in   *Interaction
+	buf  bytes.Buffer
+	once sync.Once
+}
+
+func (r *recordingBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.buf.Write(p[:n])
+	if err == io.EOF {
+		r.flush()
+	}
+	return n, err
+}
+
+func (r *recordingBody) Close() error {
+	err := r.ReadCloser.Close()
+	r.flush()
+	return err
+}
+
+func (r *recordingBody) flush() {
+	r.once.Do(func() {
+		r.in.Response.Body = redactBody(r.buf.Bytes())
+		if err := r.c.write(r.in); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write cassette: %v\n", err)
+		}
+	})
+}
+
+func (c *Cassette) write(in *Interaction) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := strings.Trim(pathCharRe.ReplaceAllString(requestPath(in.Request.URL), "_"), "_")
+	if len(path) > 80 {
+		path = path[:80]
+	}
+	name := fmt.Sprintf("%05d-%s-%s.json", in.Seq, in.Request.Method, path)
+	return os.WriteFile(filepath.Join(c.dir, name), data, 0o600)
+}
+
+func requestPath(rawURL string) string {
+	if i := strings.Index(rawURL, API_VERSION+"/"); i >= 0 {
+		rawURL = rawURL[i+len(API_VERSION):]
+	}
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	return rawURL
+}
+
+type replayTransport struct {
+	c *Cassette
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	body = redactBody(body)
+
+	key := interactionKey(req.Method, req.URL.String())
+
+	t.c.mu.Lock()
+	queue := t.c.pending[key]
+	idx := -1
+	for i, in := range queue {
+		if bytes.Equal(in.Request.Body, body) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 && len(queue) > 0 {
+		idx = 0
+	}
+	var in *Interaction
+	if idx >= 0 {
+		in = queue[idx]
+		t.c.pending[key] = append(queue[:idx:idx], queue[idx+1:]...)
+	}
+	t.c.mu.Unlock()
+
+	if in == nil {
+		return nil, fmt.Errorf("%w in cassette %s", errNotRecorded, t.c.dir)
+	}
+	if in.Response == nil {
+		return nil, &replayedError{msg: in.Error, transient: in.Transient}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+var errNotRecorded = errors.New("no recorded interaction left")
+
+// replayedError reproduces a recorded transport error. It implements
+// net.Error so the retry policy treats it like the original failure.
+type replayedError struct {
+	msg       string
+	transient bool
+}
+
+func (e *replayedError) Error() string   { return e.msg }
+func (e *replayedError) Timeout() bool   { return e.transient }
+func (e *replayedError) Temporary() bool { return e.transient }
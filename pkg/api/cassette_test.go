@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"password", `{"username": "admin", "password": "s3cret"}`, `{"username": "admin", "password": "REDACTED"}`},
+		{"escaped quote", `{"password":"a\"b"}`, `{"password":"REDACTED"}`},
+		{"tokens", `{"access_token": "eyJ.x.y", "refresh_token": "r", "token_type": "bearer"}`,
+			`{"access_token": "REDACTED", "refresh_token": "REDACTED", "token_type": "bearer"}`},
+		{"form", `username=admin&password=s3cret&grant_type=password`, `username=admin&password=REDACTED&grant_type=password`},
+		{"nothing secret", `{"name": "lab"}`, `{"name": "lab"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactBody([]byte(tt.body))); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{"Authorization": {"Bearer eyJ.x.y"}, "Content-Type": {"application/json"}}
+	got := redactHeader(h)
+	if got.Get("Authorization") != "Bearer "+redacted {
+		t.Errorf("Authorization = %q", got.Get("Authorization"))
+	}
+	if h.Get("Authorization") != "Bearer eyJ.x.y" {
+		t.Errorf("redactHeader changed the request header")
+	}
+	if got.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", got.Get("Content-Type"))
+	}
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/access/users/authenticate":
+			_, _ = w.Write([]byte(`{"access_token": "eyJ.secret.token", "token_type": "bearer"}`))
+		case "/projects":
+			_, _ = w.Write([]byte(`[{"name": "lab"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(c *Cassette, method HTTPMethod, path, data string) (string, error) {
+		settings := NewSettings(WithCassette(c), WithToken("eyJ.old.token"), withRawBaseURL(srv.URL))
+		body, _, err := NewGNS3Client(settings).Do(NewRequestOptions(settings).WithURL(path).WithMethod(method).WithData(data))
+		return string(body), err
+	}
+
+	if _, err := do(recorder, POST, "/access/users/authenticate", `{"username": "admin", "password": "s3cret"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := do(recorder, GET, "/projects", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) == 0 {
+		t.Fatal("nothing recorded")
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"s3cret", "eyJ.secret.token", "eyJ.old.token"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s contains %q", filepath.Base(f), secret)
+			}
+		}
+	}
+
+	srv.Close()
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replayer.Replaying() {
+		t.Fatal("Replaying() = false")
+	}
+	body, err := do(replayer, GET, "/projects", "")
+	if err != nil || body != `[{"name": "lab"}]` {
+		t.Errorf("replayed GET = %s, %v", body, err)
+	}
+	body, err = do(replayer, POST, "/access/users/authenticate", `{"username": "admin", "password": "other"}`)
+	if err != nil || !strings.Contains(body, `"access_token": "REDACTED"`) {
+		t.Errorf("replayed login = %s, %v", body, err)
+	}
+	if _, err := do(replayer, GET, "/projects", ""); err == nil {
+		t.Error("replaying a request twice succeeded")
+	}
+}
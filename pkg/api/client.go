@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -16,30 +18,58 @@ type HTTPMethod string
 
 type SettingOption func(*Settings)
 
+// ReauthFunc logs in again after the server rejected the expired token and
+// returns the new one, or ErrNoCredentials if there is nothing to log in with.
+type ReauthFunc func(ctx context.Context, expired string) (string, error)
+
+var ErrNoCredentials = errors.New("no credentials to log in again")
+
+// DialFunc opens the connections to the server, e.g. through an SSH tunnel.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
 const (
 	GET            HTTPMethod = "GET"
 	POST           HTTPMethod = "POST"
 	PUT            HTTPMethod = "PUT"
 	DELETE         HTTPMethod = "DELETE"
+	PATCH          HTTPMethod = "PATCH"
 	DefaultTimeout            = 30 * time.Second
 	API_VERSION               = "/v3"
 )
 
 type Settings struct {
-	BaseURL string
-	Token   string
-	Verify  bool
-	Timeout time.Duration
+	BaseURL  string
+	Token    string
+	Verify   bool
+	Timeout  time.Duration
+	Retry    RetryPolicy
+	Cassette *Cassette
+	DryRun   *DryRun
+	Trace    *Tracer
+	// RootCAs, PinnedCert and ClientCert configure TLS; see tls.go.
+	RootCAs    *x509.CertPool
+	PinnedCert string
+	ClientCert *tls.Certificate
+	// Dial replaces the direct connection when set. Proxy is used like
+	// http.Transport.Proxy and takes http, https and socks5 URLs.
+	Dial  DialFunc
+	Proxy func(*http.Request) (*url.URL, error)
+	// Reauth is asked for a new token once when the server rejects Token.
+	Reauth ReauthFunc
 }
 
 type requestOptions struct {
+	ctx      context.Context
 	settings Settings
-	URL      string
-	header   http.Header
-	method   HTTPMethod
-	data     string
-	stream   bool
-	params   map[string]string
+	// base replaces the detected API prefix, for requests that detect it.
+	base      string
+	URL       string
+	header    http.Header
+	method    HTTPMethod
+	data      string
+	stream    bool
+	retryable bool
+	params    map[string]string
 }
 
 type GNS3ApiClient struct {
@@ -53,6 +83,7 @@ func NewSettings(opts ...SettingOption) Settings {
 		Token:   "",
 		Verify:  true,
 		Timeout: DefaultTimeout,
+		Retry:   DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
 		opt(&s)
@@ -61,14 +92,22 @@ func NewSettings(opts ...SettingOption) Settings {
 }
 
 func NewGNS3Client(settings Settings) *GNS3ApiClient {
-	tr := &http.Transport{}
-	if !settings.Verify {
-		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	tr := &http.Transport{
+		TLSClientConfig: settings.tlsConfig(),
+		Proxy:           settings.Proxy,
+		DialContext:     settings.Dial,
+	}
+	var transport http.RoundTripper = tr
+	if settings.Cassette != nil {
+		transport = settings.Cassette.Transport(tr)
+	}
+	if settings.Trace != nil {
+		transport = settings.Trace.Transport(transport, !settings.Verify)
 	}
 	return &GNS3ApiClient{
 		settings: settings,
 		client: &http.Client{
-			Transport: tr,
+			Transport: transport,
 			Timeout:   settings.Timeout,
 		},
 	}
@@ -106,6 +145,30 @@ func WithVerify(verify bool) SettingOption {
 	}
 }
 
+func WithCassette(c *Cassette) SettingOption {
+	return func(s *Settings) {
+		s.Cassette = c
+	}
+}
+
+func WithDialer(dial DialFunc) SettingOption {
+	return func(s *Settings) {
+		s.Dial = dial
+	}
+}
+
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) SettingOption {
+	return func(s *Settings) {
+		s.Proxy = proxy
+	}
+}
+
+func WithReauth(reauth ReauthFunc) SettingOption {
+	return func(s *Settings) {
+		s.Reauth = reauth
+	}
+}
+
 func WithTimeout(d time.Duration) SettingOption {
 	return func(s *Settings) {
 		if d > 0 {
@@ -138,8 +201,61 @@ func (r *requestOptions) WithStream() *requestOptions {
 	return r
 }
 
+// WithRetryable marks a non-idempotent request as safe to repeat so the
+// settings' RetryPolicy applies to it as well.
+func (r *requestOptions) WithRetryable() *requestOptions {
+	r.retryable = true
+	return r
+}
+
+func (r *requestOptions) WithContext(ctx context.Context) *requestOptions {
+	r.ctx = ctx
+	return r
+}
+
 func (c *GNS3ApiClient) Do(opts *requestOptions) ([]byte, *http.Response, error) {
-	fullURL := c.settings.BaseURL + opts.URL
+	parent := opts.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	policy := c.settings.Retry
+	canRetry := policy.allows(opts)
+	reauthed := false
+	for attempt := 0; ; attempt++ {
+		base := opts.base
+		if base == "" {
+			var err error
+			// Detecting the server retries on its own.
+			if base, err = c.apiBase(parent, opts.URL); err != nil {
+				return nil, nil, err
+			}
+		}
+		body, resp, err := c.send(parent, opts, base)
+		if IsUnauthorized(err) && c.settings.Reauth != nil && !reauthed {
+			reauthed = true
+			token, reauthErr := c.settings.Reauth(parent, c.settings.Token)
+			if reauthErr == nil {
+				c.settings.Token = token
+				opts.header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+				attempt--
+				continue
+			}
+			if !errors.Is(reauthErr, ErrNoCredentials) {
+				err = fmt.Errorf("%w (logging in again failed: %v)", err, reauthErr)
+			}
+		}
+		if !canRetry || attempt >= policy.MaxRetries || parent.Err() != nil || !shouldRetry(err) {
+			return body, resp, err
+		}
+		if sleepErr := sleepCtx(parent, policy.backoff(attempt, resp)); sleepErr != nil {
+			return body, resp, err
+		}
+	}
+}
+
+func (c *GNS3ApiClient) send(parent context.Context, opts *requestOptions, base string) ([]byte, *http.Response, error) {
+	fullURL := base + opts.URL
 
 	if len(opts.params) > 0 {
 		q := url.Values{}
@@ -149,32 +265,72 @@ func (c *GNS3ApiClient) Do(opts *requestOptions) ([]byte, *http.Response, error)
 		fullURL += "?" + q.Encode()
 	}
 
+	dryRun := c.settings.DryRun
+	if dryRun != nil && opts.method != GET && !passesDryRun(opts.method, opts.URL) {
+		body, resp := dryRun.plan(opts, base, fullURL)
+		if c.settings.Trace != nil {
+			c.settings.Trace.printf("--> %s %s (dry run, not sent)\n", opts.method, fullURL)
+		}
+		return body, resp, nil
+	}
+	if dryRun != nil && opts.method == GET && !opts.stream {
+		if body, resp, ok := dryRun.answer(fullURL); ok {
+			return body, resp, nil
+		}
+	}
+
+	body, resp, err := c.doOnce(parent, opts, fullURL)
+	if dryRun != nil && err == nil && !opts.stream {
+		body = dryRun.observe(fullURL, body)
+	}
+	return body, resp, err
+}
+
+func (c *GNS3ApiClient) doOnce(parent context.Context, opts *requestOptions, fullURL string) ([]byte, *http.Response, error) {
 	if opts.stream {
 		streamClient := *c.client
 		streamClient.Timeout = 0
 
-		req, err := http.NewRequest(string(opts.method), fullURL, bytes.NewBufferString(opts.data))
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if c.settings.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(parent, c.settings.Timeout)
+		} else {
+			ctx, cancel = context.WithCancel(parent)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, string(opts.method), fullURL, bytes.NewBufferString(opts.data))
 		if err != nil {
+			cancel()
 			return nil, nil, err
 		}
 		req.Header = opts.header
 
 		resp, err := streamClient.Do(req)
 		if err != nil {
+			cancel()
 			return nil, nil, err
 		}
 
-		if c.settings.Timeout > 0 {
-			go func() {
-				<-time.After(c.settings.Timeout)
-				_ = resp.Body.Close()
-			}()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			cancel()
+			return body, resp, newAPIError(opts.method, fullURL, resp.StatusCode, body)
 		}
 
+		// The caller owns the body; closing it releases the request context.
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 		return nil, resp, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.settings.Timeout)
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if c.settings.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, c.settings.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx,
@@ -203,33 +359,19 @@ func (c *GNS3ApiClient) Do(opts *requestOptions) ([]byte, *http.Response, error)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		if resp.StatusCode == 422 {
-			var anyPayload any
-			if err := json.Unmarshal(body, &anyPayload); err == nil {
-				switch v := anyPayload.(type) {
-				case map[string]any:
-					if msg, ok := v["message"]; ok {
-						if b, err := json.MarshalIndent(msg, "", "  "); err == nil {
-							return body, resp, fmt.Errorf("validation error (422):\n%s", string(b))
-						}
-					}
-				case []any:
-					if b, err := json.MarshalIndent(v, "", "  "); err == nil {
-						return body, resp, fmt.Errorf("validation error (422):\n%s", string(b))
-					}
-				}
-			}
-			return body, resp, fmt.Errorf("validation error (422): %s", string(body))
-		}
-		if resp.StatusCode == 403 {
-			var errorMsg map[string]string
-			if err := json.Unmarshal(body, &errorMsg); err == nil {
-				return body, resp, fmt.Errorf("%s", errorMsg["message"])
-			}
-			return body, resp, fmt.Errorf("unknown forbidden 403 error. ")
-		}
-		return body, resp, fmt.Errorf("bad status %d: %s", resp.StatusCode, body)
+		return body, resp, newAPIError(opts.method, fullURL, resp.StatusCode, body)
 	}
 
 	return body, resp, nil
 }
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,331 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// PlannedRequest is a mutating request that a dry run kept from being sent.
+type PlannedRequest struct {
+	Seq    int
+	Method HTTPMethod
+	// Server is the controller URL without the API prefix.
+	Server string
+	// Path is relative to the API prefix, e.g. /access/users.
+	Path string
+	Body string
+}
+
+// DryRun makes every client sharing it answer non-GET requests locally with
+// a plausible success response instead of sending them. GETs still reach the
+// server so lookups see real state; names found in their responses are kept
+// to describe the plan. One DryRun is shared by all clients of a command run.
+type DryRun struct {
+	mu      sync.Mutex
+	planned []PlannedRequest
+	names   map[string]string
+	// created and deleted let later GETs see the plan's own changes, so
+	// orchestration that lists what it just created keeps working.
+	created map[string][]map[string]any
+	deleted map[string]bool
+	fakeIDs map[string]bool
+}
+
+func NewDryRun() *DryRun {
+	return &DryRun{
+		names:   make(map[string]string),
+		created: make(map[string][]map[string]any),
+		deleted: make(map[string]bool),
+		fakeIDs: make(map[string]bool),
+	}
+}
+
+func WithDryRun(d *DryRun) SettingOption {
+	return func(s *Settings) {
+		s.DryRun = d
+	}
+}
+
+func (d *DryRun) Planned() []PlannedRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedRequest(nil), d.planned...)
+}
+
+// Name returns the name of the object with the given id if the dry run has
+// seen it in a response or created it itself.
+func (d *DryRun) Name(id string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	name, ok := d.names[id]
+	return name, ok
+}
+
+// idFields is ordered so that an object's own id wins over the ids of the
+// objects it references (a node carries both node_id and project_id).
+var idFields = []string{
+	"user_id", "user_group_id", "role_id", "privilege_id", "resource_pool_id", "ace_id",
+	"node_id", "link_id", "drawing_id", "snapshot_id", "template_id", "compute_id", "project_id",
+}
+
+var collectionIDFields = map[string]string{
+	"users":      "user_id",
+	"groups":     "user_group_id",
+	"roles":      "role_id",
+	"privileges": "privilege_id",
+	"pools":      "resource_pool_id",
+	"acl":        "ace_id",
+	"nodes":      "node_id",
+	"links":      "link_id",
+	"drawings":   "drawing_id",
+	"snapshots":  "snapshot_id",
+	"templates":  "template_id",
+	"computes":   "compute_id",
+	"projects":   "project_id",
+}
+
+func (d *DryRun) learnValue(v any) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			d.learnValue(item)
+		}
+	case map[string]any:
+		d.learnObject(v)
+	}
+}
+
+func (d *DryRun) learnObject(obj map[string]any) {
+	name, _ := obj["username"].(string)
+	if name == "" {
+		name, _ = obj["name"].(string)
+	}
+	if name == "" {
+		return
+	}
+	for _, f := range idFields {
+		if id, ok := obj[f].(string); ok && id != "" {
+			d.names[id] = name
+			return
+		}
+	}
+}
+
+// plan records the request and builds the response the controller would
+// most likely have sent. Created objects get a fresh id so callers that
+// chain requests (create group, then add members) keep going.
+func (d *DryRun) plan(opts *requestOptions, baseURL, fullURL string) ([]byte, *http.Response) {
+	path := strings.TrimPrefix(fullURL, baseURL)
+	server := strings.TrimSuffix(strings.TrimSuffix(baseURL, API_VERSION), legacyAPIVersion)
+
+	status := plannedStatus(opts.method, path)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var body []byte
+	if status != http.StatusNoContent {
+		obj := map[string]any{}
+		_ = json.Unmarshal([]byte(opts.data), &obj)
+		if field := createdIDField(opts.method, path); field != "" {
+			if _, ok := obj[field].(string); !ok {
+				id := uuid.NewString()
+				obj[field] = id
+				d.fakeIDs[id] = true
+			}
+			if isCollection(fullURL) {
+				key := stripQuery(fullURL)
+				d.created[key] = append(d.created[key], obj)
+			}
+		}
+		d.learnObject(obj)
+		body, _ = json.Marshal(obj)
+	}
+	if opts.method == DELETE {
+		d.deleted[stripQuery(fullURL)] = true
+	}
+	// POST /projects/{id}/import names the new project in the query only.
+	if segs := pathSegments(path); opts.method == POST && len(segs) == 3 && segs[0] == "projects" && segs[2] == "import" {
+		if u, err := url.Parse(fullURL); err == nil && u.Query().Get("name") != "" {
+			obj := map[string]any{"project_id": segs[1], "name": u.Query().Get("name")}
+			key := strings.TrimSuffix(stripQuery(fullURL), "/"+segs[1]+"/import")
+			d.created[key] = append(d.created[key], obj)
+			d.fakeIDs[segs[1]] = true
+			d.learnObject(obj)
+		}
+	}
+
+	d.planned = append(d.planned, PlannedRequest{
+		Seq:    len(d.planned) + 1,
+		Method: opts.method,
+		Server: server,
+		Path:   path,
+		Body:   opts.data,
+	})
+
+	return body, syntheticResponse(status, body)
+}
+
+// answer serves GETs that only make sense with the plan applied: objects the
+// plan created and collections below them. The server does not know them.
+func (d *DryRun) answer(fullURL string) ([]byte, *http.Response, bool) {
+	key := stripQuery(fullURL)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fake := false
+	for _, seg := range pathSegments(key) {
+		if d.fakeIDs[seg] {
+			fake = true
+			break
+		}
+	}
+	if !fake {
+		return nil, nil, false
+	}
+
+	if isCollection(key) {
+		body := d.overlayLocked(key, []byte("[]"))
+		return body, syntheticResponse(http.StatusOK, body), true
+	}
+	parent, id := key[:strings.LastIndexByte(key, '/')], key[strings.LastIndexByte(key, '/')+1:]
+	for _, obj := range d.created[parent] {
+		if obj[collectionIDFields[lastSegment(parent)]] == id && !d.deleted[key] {
+			body, _ := json.Marshal(obj)
+			return body, syntheticResponse(http.StatusOK, body), true
+		}
+	}
+	return nil, nil, false
+}
+
+// observe learns names from a GET response and applies the plan to lists.
+func (d *DryRun) observe(fullURL string, body []byte) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	body = d.overlayLocked(stripQuery(fullURL), body)
+	var v any
+	if json.Unmarshal(body, &v) == nil {
+		d.learnValue(v)
+	}
+	return body
+}
+
+func (d *DryRun) overlayLocked(key string, body []byte) []byte {
+	if !isCollection(key) {
+		return body
+	}
+	var items []map[string]any
+	if json.Unmarshal(body, &items) != nil {
+		return body
+	}
+	idField := collectionIDFields[lastSegment(key)]
+	out := items[:0]
+	for _, item := range items {
+		if id, _ := item[idField].(string); !d.deleted[key+"/"+id] {
+			out = append(out, item)
+		}
+	}
+	for _, item := range d.created[key] {
+		if id, _ := item[idField].(string); !d.deleted[key+"/"+id] {
+			out = append(out, item)
+		}
+	}
+	if len(out) == len(items) && len(d.created[key]) == 0 {
+		return body
+	}
+	merged, err := json.Marshal(out)
+	if err != nil {
+		return body
+	}
+	return merged
+}
+
+func syntheticResponse(status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+func stripQuery(u string) string {
+	if i := strings.IndexByte(u, '?'); i >= 0 {
+		return u[:i]
+	}
+	return u
+}
+
+func pathSegments(path string) []string {
+	return strings.Split(strings.Trim(stripQuery(path), "/"), "/")
+}
+
+func lastSegment(path string) string {
+	segs := pathSegments(path)
+	return segs[len(segs)-1]
+}
+
+func isCollection(path string) bool {
+	_, ok := collectionIDFields[lastSegment(path)]
+	return ok
+}
+
+// plannedStatus mirrors the status codes of the GNS3v3 controller closely
+// enough for the status checks in this repository.
+func plannedStatus(method HTTPMethod, path string) int {
+	segs := pathSegments(path)
+	last := segs[len(segs)-1]
+	switch method {
+	case DELETE:
+		return http.StatusNoContent
+	case PUT:
+		if len(segs) >= 2 {
+			switch segs[len(segs)-2] {
+			case "members", "privileges", "resources":
+				return http.StatusNoContent
+			}
+		}
+		return http.StatusOK
+	}
+	switch last {
+	case "close", "lock", "unlock", "start", "stop", "suspend", "reload",
+		"isolate", "unisolate", "reset":
+		return http.StatusNoContent
+	}
+	return http.StatusCreated
+}
+
+// passesDryRun reports whether a non-GET request is sent even during a dry
+// run because it does not change anything on the controller.
+func passesDryRun(method HTTPMethod, path string) bool {
+	return method == POST && strings.HasSuffix(path, "/access/users/authenticate")
+}
+
+// createdIDField returns the id field of the object a POST creates.
+func createdIDField(method HTTPMethod, path string) string {
+	if method != POST {
+		return ""
+	}
+	segs := pathSegments(path)
+	// POST /projects/{id}/templates/{id} creates a node from a template.
+	if len(segs) == 4 && segs[0] == "projects" && segs[2] == "templates" {
+		return "node_id"
+	}
+	for i := len(segs) - 1; i >= 0; i-- {
+		if field, ok := collectionIDFields[segs[i]]; ok {
+			return field
+		}
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// withRawBaseURL points a client at a test server as it is, without the
+// version prefix WithBaseURL adds.
+func withRawBaseURL(u string) SettingOption {
+	return func(s *Settings) {
+		s.BaseURL = u
+	}
+}
+
+func TestPlannedStatus(t *testing.T) {
+	tests := []struct {
+		method HTTPMethod
+		path   string
+		want   int
+	}{
+		{POST, "/projects", http.StatusCreated},
+		{POST, "/projects/p1/nodes", http.StatusCreated},
+		{POST, "/projects/p1/close", http.StatusNoContent},
+		{POST, "/projects/p1/nodes/start", http.StatusNoContent},
+		{POST, "/projects/p1/nodes/n1/isolate", http.StatusNoContent},
+		{PUT, "/projects/p1", http.StatusOK},
+		{PUT, "/access/groups/g1/members/u1", http.StatusNoContent},
+		{PUT, "/access/roles/r1/privileges/x1", http.StatusNoContent},
+		{PUT, "/pools/p1/resources/r1", http.StatusNoContent},
+		{DELETE, "/projects/p1", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if got := plannedStatus(tt.method, tt.path); got != tt.want {
+			t.Errorf("plannedStatus(%s %s) = %d, want %d", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCreatedIDField(t *testing.T) {
+	tests := []struct {
+		method HTTPMethod
+		path   string
+		want   string
+	}{
+		{POST, "/projects", "project_id"},
+		{POST, "/access/users", "user_id"},
+		{POST, "/projects/p1/nodes", "node_id"},
+		{POST, "/projects/p1/templates/t1", "node_id"},
+		{POST, "/projects/p1/links", "link_id"},
+		{POST, "/projects/p1/duplicate", "project_id"},
+		{PUT, "/projects/p1", ""},
+		{DELETE, "/projects/p1", ""},
+	}
+	for _, tt := range tests {
+		if got := createdIDField(tt.method, tt.path); got != tt.want {
+			t.Errorf("createdIDField(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPassesDryRun(t *testing.T) {
+	if !passesDryRun(POST, "/access/users/authenticate") {
+		t.Error("login is not sent during a dry run")
+	}
+	if passesDryRun(POST, "/access/users") || passesDryRun(DELETE, "/access/users/authenticate") {
+		t.Error("a mutating request is sent during a dry run")
+	}
+}
+
+func TestDryRunPlan(t *testing.T) {
+	var sent atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			sent.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		switch r.URL.Path {
+		case "/projects":
+			_, _ = w.Write([]byte(`[{"project_id": "p-old", "name": "old"}, {"project_id": "p-keep", "name": "keep"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dryRun := NewDryRun()
+	settings := NewSettings(WithDryRun(dryRun), withRawBaseURL(srv.URL))
+	client := NewGNS3Client(settings)
+	do := func(method HTTPMethod, path, data string) ([]byte, int) {
+		t.Helper()
+		body, resp, err := client.Do(NewRequestOptions(settings).WithURL(path).WithMethod(method).WithData(data))
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		return body, resp.StatusCode
+	}
+
+	body, status := do(POST, "/projects", `{"name": "new"}`)
+	if status != http.StatusCreated {
+		t.Errorf("create status = %d", status)
+	}
+	var created struct {
+		ProjectID string `json:"project_id"`
+		Name      string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.ProjectID == "" || created.Name != "new" {
+		t.Fatalf("create response = %s", body)
+	}
+	if _, status := do(DELETE, "/projects/p-old", ""); status != http.StatusNoContent {
+		t.Errorf("delete status = %d", status)
+	}
+	if sent.Load() != 0 {
+		t.Errorf("%d mutating requests reached the server", sent.Load())
+	}
+
+	body, _ = do(GET, "/projects", "")
+	var projects []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &projects); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if len(names) != 2 || names[0] != "keep" || names[1] != "new" {
+		t.Errorf("projects with the plan applied = %v, want [keep new]", names)
+	}
+
+	if body, _ := do(GET, "/projects/"+created.ProjectID, ""); !json.Valid(body) || string(body) == "" {
+		t.Errorf("planned project not answered locally: %s", body)
+	}
+	if body, _ := do(GET, "/projects/"+created.ProjectID+"/nodes", ""); string(body) != "[]" {
+		t.Errorf("nodes of the planned project = %s, want []", body)
+	}
+
+	planned := dryRun.Planned()
+	if len(planned) != 2 {
+		t.Fatalf("planned %d requests, want 2", len(planned))
+	}
+	if p := planned[0]; p.Seq != 1 || p.Method != POST || p.Path != "/projects" || p.Server != srv.URL {
+		t.Errorf("planned[0] = %+v", p)
+	}
+	if p := planned[1]; p.Method != DELETE || p.Path != "/projects/p-old" {
+		t.Errorf("planned[1] = %+v", p)
+	}
+	for id, want := range map[string]string{created.ProjectID: "new", "p-keep": "keep"} {
+		if name, ok := dryRun.Name(id); !ok || name != want {
+			t.Errorf("Name(%s) = %q, %v, want %q", id, name, ok, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package endpoints
+
+import "strings"
+
+// Feature is an area of the controller API that older servers lack.
+type Feature struct {
+	Name string
+	// Prefix is the path prefix of the feature's endpoints.
+	Prefix string
+	// MinMajor is the first major GNS3 version providing it.
+	MinMajor int
+}
+
+// Access control and the image/pool endpoints came with GNS3v3; a 2.2
+// controller has none of them.
+var features = []Feature{
+	{Name: "user accounts", Prefix: "/access/users", MinMajor: 3},
+	{Name: "user groups", Prefix: "/access/groups", MinMajor: 3},
+	{Name: "roles", Prefix: "/access/roles", MinMajor: 3},
+	{Name: "privileges", Prefix: "/access/privileges", MinMajor: 3},
+	{Name: "ACLs", Prefix: "/access/acl", MinMajor: 3},
+	{Name: "access control", Prefix: "/access", MinMajor: 3},
+	{Name: "resource pools", Prefix: "/pools", MinMajor: 3},
+	{Name: "image management", Prefix: "/images", MinMajor: 3},
+}
+
+// Unsupported returns the feature an endpoint path belongs to if a server
+// with the given major version does not provide it.
+func Unsupported(path string, major int) (Feature, bool) {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	for _, f := range features {
+		if path != f.Prefix && !strings.HasPrefix(path, f.Prefix+"/") {
+			continue
+		}
+		if major < f.MinMajor {
+			return f, true
+		}
+		return Feature{}, false
+	}
+	return Feature{}, false
+}
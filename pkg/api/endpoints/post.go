@@ -172,6 +172,10 @@ func (PostEndpoints) ProjectImport(projectID string) string {
 	return fmt.Sprintf("/projects/%s/import", projectID)
 }
 
+func (PostEndpoints) WriteProjectFile(projectID, filePath string) string {
+	return fmt.Sprintf("/projects/%s/files/%s", projectID, filePath)
+}
+
 func (PostEndpoints) Reload() string {
 	return "/reload"
 }
@@ -0,0 +1,71 @@
+package endpoints
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Route is the method and path template of an endpoint, with every path
+// parameter written as {}.
+type Route struct {
+	Method string
+	Path   string
+	// Name is the endpoint method or command the route belongs to.
+	Name string
+}
+
+// Routes lists the routes of all endpoint methods. The paths are found by
+// calling each method with placeholder arguments.
+func Routes() []Route {
+	sets := []struct {
+		method string
+		prefix string
+		v      any
+	}{
+		{"GET", "Get", GetEndpoints{}},
+		{"POST", "Post", PostEndpoints{}},
+		{"PUT", "Put", PutEndpoints{}},
+		{"DELETE", "Delete", DeleteEndpoints{}},
+	}
+
+	var routes []Route
+	for _, set := range sets {
+		v := reflect.ValueOf(set.v)
+		for i := 0; i < v.NumMethod(); i++ {
+			m := v.Method(i)
+			mt := m.Type()
+			if mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.String {
+				continue
+			}
+			args := make([]reflect.Value, mt.NumIn())
+			for j := range args {
+				if mt.In(j).Kind() == reflect.String {
+					args[j] = reflect.ValueOf("{}")
+				} else {
+					args[j] = reflect.Zero(mt.In(j))
+				}
+			}
+			routes = append(routes, Route{
+				Method: set.method,
+				Path:   RouteTemplate(m.Call(args)[0].String()),
+				Name:   set.prefix + "." + v.Type().Method(i).Name,
+			})
+		}
+	}
+	return routes
+}
+
+// RouteTemplate drops the query of a path and replaces every {param} with {}
+// so paths from different sources compare equal.
+func RouteTemplate(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segs := strings.Split(path, "/")
+	for i, s := range segs {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			segs[i] = "{}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ValidationError is a single entry of a 422 response. Loc is the path to the
+// offending field as reported by the controller, e.g. ["body", "name"].
+type ValidationError struct {
+	Loc     []string
+	Message string
+	Type    string
+}
+
+func (v ValidationError) Field() string {
+	return strings.Join(v.Loc, ".")
+}
+
+// APIError is returned by GNS3ApiClient.Do for every non 2xx response.
+// Use errors.As or the Is* helpers to branch on it.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Message    string
+	Validation []ValidationError
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	path := e.URL
+	if u, err := url.Parse(e.URL); err == nil && u.Path != "" {
+		path = u.Path
+	}
+
+	if len(e.Validation) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "validation error (%d) on %s %s:", e.StatusCode, e.Method, path)
+		for _, v := range e.Validation {
+			if field := v.Field(); field != "" {
+				fmt.Fprintf(&b, "\n  - %s: %s", field, v.Message)
+			} else {
+				fmt.Fprintf(&b, "\n  - %s", v.Message)
+			}
+		}
+		return b.String()
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = strings.TrimSpace(string(e.Body))
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, path, e.StatusCode, msg)
+}
+
+func newAPIError(method HTTPMethod, rawURL string, status int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: status,
+		Method:     string(method),
+		URL:        rawURL,
+		Body:       body,
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		var list []any
+		if err := json.Unmarshal(body, &list); err == nil {
+			e.Validation = parseValidationEntries(list)
+		}
+		return e
+	}
+
+	for _, key := range []string{"message", "detail"} {
+		switch v := payload[key].(type) {
+		case string:
+			if e.Message == "" {
+				e.Message = v
+			}
+		case []any:
+			e.Validation = append(e.Validation, parseValidationEntries(v)...)
+		}
+	}
+	return e
+}
+
+func parseValidationEntries(list []any) []ValidationError {
+	var out []ValidationError
+	for _, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			if s, ok := item.(string); ok {
+				out = append(out, ValidationError{Message: s})
+			}
+			continue
+		}
+		var v ValidationError
+		if loc, ok := entry["loc"].([]any); ok {
+			for _, part := range loc {
+				v.Loc = append(v.Loc, fmt.Sprint(part))
+			}
+		}
+		if msg, ok := entry["msg"].(string); ok {
+			v.Message = msg
+		}
+		if typ, ok := entry["type"].(string); ok {
+			v.Type = typ
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// StatusCode returns the HTTP status carried by err or 0 if err did not come
+// from a controller response.
+func StatusCode(err error) int {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+func IsValidation(err error) bool {
+	return StatusCode(err) == http.StatusUnprocessableEntity
+}
+
+// IsInvalidField reports whether err is a validation error for the field at
+// loc, e.g. IsInvalidField(err, "path", "project_id").
+func IsInvalidField(err error, loc ...string) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	for _, v := range apiErr.Validation {
+		if slices.Equal(v.Loc, loc) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		message    string
+		validation []ValidationError
+		errText    string
+	}{
+		{
+			name:    "message",
+			status:  http.StatusNotFound,
+			body:    `{"message": "Project ID abc doesn't exist"}`,
+			message: "Project ID abc doesn't exist",
+			errText: "GET /v3/projects/abc: status 404: Project ID abc doesn't exist",
+		},
+		{
+			name:    "detail string",
+			status:  http.StatusUnauthorized,
+			body:    `{"detail": "Could not validate credentials"}`,
+			message: "Could not validate credentials",
+			errText: "status 401: Could not validate credentials",
+		},
+		{
+			name:   "detail list",
+			status: http.StatusUnprocessableEntity,
+			body:   `{"detail": [{"loc": ["path", "project_id"], "msg": "Input should be a valid UUID", "type": "uuid_parsing"}]}`,
+			validation: []ValidationError{
+				{Loc: []string{"path", "project_id"}, Message: "Input should be a valid UUID", Type: "uuid_parsing"},
+			},
+			errText: "path.project_id: Input should be a valid UUID",
+		},
+		{
+			name:   "top level list",
+			status: http.StatusUnprocessableEntity,
+			body:   `[{"loc": ["body", 0], "msg": "bad"}, "plain"]`,
+			validation: []ValidationError{
+				{Loc: []string{"body", "0"}, Message: "bad"},
+				{Message: "plain"},
+			},
+			errText: "body.0: bad",
+		},
+		{
+			name:    "plain text",
+			status:  http.StatusBadGateway,
+			body:    "upstream down\n",
+			errText: "status 502: upstream down",
+		},
+		{
+			name:    "empty body",
+			status:  http.StatusServiceUnavailable,
+			errText: "status 503: Service Unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newAPIError(GET, "http://lab:3080/v3/projects/abc", tt.status, []byte(tt.body))
+			if e.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", e.StatusCode, tt.status)
+			}
+			if e.Message != tt.message {
+				t.Errorf("Message = %q, want %q", e.Message, tt.message)
+			}
+			if !reflect.DeepEqual(e.Validation, tt.validation) {
+				t.Errorf("Validation = %#v, want %#v", e.Validation, tt.validation)
+			}
+			if !strings.Contains(e.Error(), tt.errText) {
+				t.Errorf("Error() = %q, want it to contain %q", e.Error(), tt.errText)
+			}
+		})
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	uuidErr := newAPIError(DELETE, "http://lab:3080/v3/projects/lab1", http.StatusUnprocessableEntity,
+		[]byte(`{"detail": [{"loc": ["path", "project_id"], "msg": "Input should be a valid UUID"}]}`))
+	bodyErr := newAPIError(POST, "http://lab:3080/v3/projects", http.StatusUnprocessableEntity,
+		[]byte(`{"detail": [{"loc": ["body", "name"], "msg": "Field required"}]}`))
+	wrapped := fmt.Errorf("failed to delete project: %w", uuidErr)
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"IsValidation", IsValidation(wrapped), true},
+		{"IsNotFound", IsNotFound(wrapped), false},
+		{"IsInvalidField path", IsInvalidField(wrapped, "path", "project_id"), true},
+		{"IsInvalidField other field", IsInvalidField(bodyErr, "path", "project_id"), false},
+		{"IsInvalidField partial loc", IsInvalidField(uuidErr, "path"), false},
+		{"IsInvalidField not an API error", IsInvalidField(fmt.Errorf("boom"), "path", "project_id"), false},
+		{"StatusCode plain error", StatusCode(fmt.Errorf("boom")) == 0, true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultRetries      = 2
+	DefaultRetryWait    = 500 * time.Millisecond
+	DefaultRetryMaxWait = 10 * time.Second
+)
+
+// RetryPolicy controls how Do repeats requests that failed with a transient
+// error (connection reset/refused, timeouts, 429, 502, 503, 504).
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts made after the first one.
+	MaxRetries int
+	// BaseWait is the delay before the first retry, doubled on every attempt.
+	BaseWait time.Duration
+	// MaxWait caps a single delay, including one requested via Retry-After.
+	MaxWait time.Duration
+	// RetryPOST also retries POST requests. By default only idempotent
+	// methods are repeated; single requests can opt in with WithRetryable.
+	RetryPOST bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultRetries,
+		BaseWait:   DefaultRetryWait,
+		MaxWait:    DefaultRetryMaxWait,
+	}
+}
+
+func WithRetry(p RetryPolicy) SettingOption {
+	return func(s *Settings) {
+		s.Retry = p
+	}
+}
+
+func (m HTTPMethod) Idempotent() bool {
+	switch m {
+	case GET, PUT, DELETE:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) allows(opts *requestOptions) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	return opts.retryable || p.RetryPOST || opts.method.Idempotent()
+}
+
+// backoff returns the delay before retry number attempt (starting at 0).
+// A Retry-After header on resp takes precedence over the exponential delay.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	maxWait := p.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+
+	if d, ok := retryAfter(resp); ok {
+		return min(d, maxWait)
+	}
+
+	base := p.BaseWait
+	if base <= 0 {
+		base = DefaultRetryWait
+	}
+	d := base << attempt
+	if d <= 0 || d > maxWait {
+		d = maxWait
+	}
+	// Jitter in [d/2, d) so parallel workers do not retry in lockstep.
+	half := d / 2
+	return half + rand.N(d-half)
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func shouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := AsAPIError(err); ok {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
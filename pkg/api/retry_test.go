@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		method    HTTPMethod
+		retryable bool
+		want      bool
+	}{
+		{"GET", DefaultRetryPolicy(), GET, false, true},
+		{"PUT", DefaultRetryPolicy(), PUT, false, true},
+		{"DELETE", DefaultRetryPolicy(), DELETE, false, true},
+		{"POST", DefaultRetryPolicy(), POST, false, false},
+		{"retryable POST", DefaultRetryPolicy(), POST, true, true},
+		{"RetryPOST", RetryPolicy{MaxRetries: 1, RetryPOST: true}, POST, false, true},
+		{"no retries", RetryPolicy{}, GET, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &requestOptions{method: tt.method, retryable: tt.retryable}
+			if got := tt.policy.allows(opts); got != tt.want {
+				t.Errorf("allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseWait: 100 * time.Millisecond, MaxWait: time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"first", 0, "", 50 * time.Millisecond, 100 * time.Millisecond},
+		{"third", 2, "", 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped", 10, "", 500 * time.Millisecond, time.Second},
+		{"retry after", 0, "1", time.Second, time.Second},
+		{"retry after capped", 0, "30", time.Second, time.Second},
+		{"retry after invalid", 0, "soon", 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.retryAfter != "" {
+				resp = &http.Response{Header: http.Header{"Retry-After": {tt.retryAfter}}}
+			}
+			for range 20 {
+				d := p.backoff(tt.attempt, resp)
+				if d < tt.min || d > tt.max || (tt.min != tt.max && d == tt.max) {
+					t.Fatalf("backoff(%d) = %v, want in [%v, %v)", tt.attempt, d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"503", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"502", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"504", &APIError{StatusCode: http.StatusGatewayTimeout}, true},
+		{"404", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"500", &APIError{StatusCode: http.StatusInternalServerError}, false},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"canceled", context.Canceled, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   HTTPMethod
+		failures int
+		policy   RetryPolicy
+		status   int
+		attempts int32
+	}{
+		{"recovers", GET, 2, RetryPolicy{MaxRetries: 2}, http.StatusOK, 3},
+		{"gives up", GET, 5, RetryPolicy{MaxRetries: 2}, http.StatusServiceUnavailable, 3},
+		{"no retries", GET, 1, RetryPolicy{}, http.StatusServiceUnavailable, 1},
+		{"POST not retried", POST, 1, RetryPolicy{MaxRetries: 2}, http.StatusServiceUnavailable, 1},
+		{"RetryPOST", POST, 1, RetryPolicy{MaxRetries: 2, RetryPOST: true}, http.StatusOK, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(attempts.Add(1)) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			tt.policy.BaseWait = time.Millisecond
+			settings := NewSettings(WithRetry(tt.policy), withRawBaseURL(srv.URL))
+			_, resp, err := NewGNS3Client(settings).Do(NewRequestOptions(settings).WithURL("/projects").WithMethod(tt.method))
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+			status := StatusCode(err)
+			if err == nil {
+				status = resp.StatusCode
+			}
+			if status != tt.status {
+				t.Errorf("status = %d (err %v), want %d", status, err, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package schemas
+
+type DrawingCreate struct {
+	SVG      *string `json:"svg,omitempty"`
+	X        *int    `json:"x,omitempty"`
+	Y        *int    `json:"y,omitempty"`
+	Z        *int    `json:"z,omitempty"`
+	Rotation *int    `json:"rotation,omitempty"`
+	Locked   *bool   `json:"locked,omitempty"`
+}
+
+type DrawingUpdate struct {
+	SVG      *string `json:"svg,omitempty"`
+	X        *int    `json:"x,omitempty"`
+	Y        *int    `json:"y,omitempty"`
+	Z        *int    `json:"z,omitempty"`
+	Rotation *int    `json:"rotation,omitempty"`
+	Locked   *bool   `json:"locked,omitempty"`
+}
@@ -0,0 +1,20 @@
+package schemas
+
+type LinkNode struct {
+	NodeID        string `json:"node_id"`
+	AdapterNumber int    `json:"adapter_number"`
+	PortNumber    int    `json:"port_number"`
+	Label         *Label `json:"label,omitempty"`
+}
+
+type LinkCreate struct {
+	Nodes   []LinkNode     `json:"nodes"`
+	Suspend *bool          `json:"suspend,omitempty"`
+	Filters map[string]any `json:"filters,omitempty"`
+}
+
+type LinkUpdate struct {
+	Nodes   []LinkNode     `json:"nodes,omitempty"`
+	Suspend *bool          `json:"suspend,omitempty"`
+	Filters map[string]any `json:"filters,omitempty"`
+}
@@ -0,0 +1,35 @@
+package schemas
+
+// RequestTypes maps the names of the controller's OpenAPI request schemas to
+// the types in this package that model them. system api-coverage compares
+// their fields; add new request types here.
+var RequestTypes = map[string]any{
+	"ACECreate":           ACECreate{},
+	"ACEUpdate":           ACEUpdate{},
+	"ComputeCreate":       ComputeCreate{},
+	"ComputeUpdate":       ComputeUpdate{},
+	"Credentials":         Credentials{},
+	"DrawingCreate":       DrawingCreate{},
+	"IOULicense":          IOULicense{},
+	"LinkCreate":          LinkCreate{},
+	"LinkUpdate":          LinkUpdate{},
+	"LoggedInUserUpdate":  LoggedInUserUpdate{},
+	"NodeCreate":          NodeCreate{},
+	"NodeUpdate":          NodeUpdate{},
+	"ProjectCreate":       ProjectCreate{},
+	"ProjectDuplicate":    ProjectDuplicate{},
+	"ProjectUpdate":       ProjectUpdate{},
+	"QemuDiskImageCreate": QemuDiskImageCreate{},
+	"ResourcePoolCreate":  ResourcePoolCreate{},
+	"ResourcePoolUpdate":  ResourcePoolUpdate{},
+	"RoleCreate":          RoleCreate{},
+	"RoleUpdate":          RoleUpdate{},
+	"SnapshotCreate":      SnapshotCreate{},
+	"TemplateCreate":      TemplateCreate{},
+	"TemplateUpdate":      TemplateUpdate{},
+	"TemplateUsage":       TemplateUsage{},
+	"UserCreate":          UserCreate{},
+	"UserGroupCreate":     UserGroupCreate{},
+	"UserGroupUpdate":     UserGroupUpdate{},
+	"UserUpdate":          UserUpdate{},
+}
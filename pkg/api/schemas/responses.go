@@ -7,6 +7,7 @@ import (
 type UserGroupResponse struct {
 	UserGroupID uuid.UUID `json:"user_group_id"`
 	Name        string    `json:"name"`
+	IsBuiltin   bool      `json:"is_builtin"`
 }
 
 type UserResponse struct {
@@ -24,6 +25,7 @@ type UserResponse struct {
 type ProjectResponse struct {
 	ProjectID           string    `json:"project_id"`
 	Name                string    `json:"name"`
+	Status              *string   `json:"status,omitempty"`
 	Path                *string   `json:"path,omitempty"`
 	AutoClose           *bool     `json:"auto_close,omitempty"`
 	AutoOpen            *bool     `json:"auto_open,omitempty"`
@@ -46,8 +48,10 @@ type ResourcePoolResponse struct {
 }
 
 type RoleResponse struct {
-	RoleID string `json:"role_id"`
-	Name   string `json:"name"`
+	RoleID      string  `json:"role_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	IsBuiltin   bool    `json:"is_builtin"`
 }
 
 type ACLResponse struct {
@@ -60,3 +64,94 @@ type ACLResponse struct {
 	RoleID    *string `json:"role_id,omitempty"`
 	UserID    *string `json:"user_id,omitempty"`
 }
+
+type PrivilegeResponse struct {
+	PrivilegeID string  `json:"privilege_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+type ResourceResponse struct {
+	ResourceID   string `json:"resource_id"`
+	ResourceType string `json:"resource_type"`
+	Name         string `json:"name"`
+}
+
+type NodePort struct {
+	Name          string `json:"name"`
+	ShortName     string `json:"short_name"`
+	AdapterNumber int    `json:"adapter_number"`
+	PortNumber    int    `json:"port_number"`
+	LinkType      string `json:"link_type"`
+}
+
+type NodeResponse struct {
+	NodeID      string         `json:"node_id"`
+	ProjectID   string         `json:"project_id"`
+	ComputeID   string         `json:"compute_id"`
+	TemplateID  *string        `json:"template_id,omitempty"`
+	Name        string         `json:"name"`
+	NodeType    string         `json:"node_type"`
+	Status      string         `json:"status"`
+	Console     *int           `json:"console,omitempty"`
+	ConsoleHost *string        `json:"console_host,omitempty"`
+	ConsoleType *string        `json:"console_type,omitempty"`
+	Symbol      *string        `json:"symbol,omitempty"`
+	Label       *Label         `json:"label,omitempty"`
+	X           int            `json:"x"`
+	Y           int            `json:"y"`
+	Z           int            `json:"z"`
+	Locked      bool           `json:"locked"`
+	Ports       []NodePort     `json:"ports,omitempty"`
+	Properties  map[string]any `json:"properties,omitempty"`
+}
+
+type LinkResponse struct {
+	LinkID    string         `json:"link_id"`
+	ProjectID string         `json:"project_id"`
+	LinkType  string         `json:"link_type"`
+	Nodes     []LinkNode     `json:"nodes"`
+	Suspend   bool           `json:"suspend"`
+	Capturing bool           `json:"capturing"`
+	Filters   map[string]any `json:"filters,omitempty"`
+}
+
+type DrawingResponse struct {
+	DrawingID string `json:"drawing_id"`
+	ProjectID string `json:"project_id"`
+	SVG       string `json:"svg"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Z         int    `json:"z"`
+	Rotation  int    `json:"rotation"`
+	Locked    bool   `json:"locked"`
+}
+
+type SnapshotResponse struct {
+	SnapshotID string `json:"snapshot_id"`
+	ProjectID  string `json:"project_id"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+type TemplateResponse struct {
+	TemplateID   string  `json:"template_id"`
+	Name         string  `json:"name"`
+	Category     string  `json:"category"`
+	TemplateType string  `json:"template_type"`
+	ComputeID    *string `json:"compute_id,omitempty"`
+	Symbol       *string `json:"symbol,omitempty"`
+	Builtin      bool    `json:"builtin"`
+}
+
+type ComputeResponse struct {
+	ComputeID          string   `json:"compute_id"`
+	Name               string   `json:"name"`
+	Protocol           string   `json:"protocol"`
+	Host               string   `json:"host"`
+	Port               int      `json:"port"`
+	User               *string  `json:"user,omitempty"`
+	Connected          bool     `json:"connected"`
+	CPUUsagePercent    *float64 `json:"cpu_usage_percent,omitempty"`
+	MemoryUsagePercent *float64 `json:"memory_usage_percent,omitempty"`
+}
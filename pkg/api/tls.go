@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// CertPinError is returned when a server presents a certificate other than
+// the one pinned for it.
+type CertPinError struct {
+	Server string
+	Want   string
+	Got    string
+}
+
+func (e *CertPinError) Error() string {
+	return fmt.Sprintf("certificate of %s changed: pinned SHA-256 fingerprint %s, got %s; if the certificate was replaced on purpose, run auth login --trust-cert",
+		e.Server, e.Want, e.Got)
+}
+
+// CertPins maps servers to the SHA-256 fingerprint of their pinned
+// certificate. Servers are compared by host and port.
+type CertPins map[string]string
+
+func (p CertPins) For(server string) string {
+	if p == nil {
+		return ""
+	}
+	return p[pinKey(server)]
+}
+
+func (p CertPins) Set(server, fingerprint string) {
+	p[pinKey(server)] = fingerprint
+}
+
+func (p CertPins) Delete(server string) {
+	delete(p, pinKey(server))
+}
+
+func pinKey(server string) string {
+	u, err := url.Parse(server)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(server)
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// CertFingerprint returns the SHA-256 fingerprint of a DER encoded
+// certificate as colon separated upper-case hex, like openssl prints it.
+func CertFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+func normalizeFingerprint(fp string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fp))
+}
+
+// LoadCAFile reads PEM encoded CA certificates and returns a pool holding
+// them in addition to the system roots.
+func LoadCAFile(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// LoadClientCert loads a client certificate and key. keyFile may be empty
+// when certFile holds both.
+func LoadClientCert(certFile, keyFile string) (*tls.Certificate, error) {
+	if keyFile == "" {
+		keyFile = certFile
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+func WithRootCAs(pool *x509.CertPool) SettingOption {
+	return func(s *Settings) {
+		s.RootCAs = pool
+	}
+}
+
+// WithPinnedCert makes the client accept exactly the certificate with this
+// SHA-256 fingerprint instead of verifying the chain.
+func WithPinnedCert(fingerprint string) SettingOption {
+	return func(s *Settings) {
+		s.PinnedCert = fingerprint
+	}
+}
+
+func WithClientCert(cert *tls.Certificate) SettingOption {
+	return func(s *Settings) {
+		s.ClientCert = cert
+	}
+}
+
+func (s Settings) tlsConfig() *tls.Config {
+	cfg := &tls.Config{RootCAs: s.RootCAs}
+	if s.ClientCert != nil {
+		cfg.Certificates = []tls.Certificate{*s.ClientCert}
+	}
+	switch {
+	case !s.Verify:
+		cfg.InsecureSkipVerify = true
+	case s.PinnedCert != "":
+		// The pin replaces chain verification, self-signed certificates of
+		// remote install https would not pass it anyway.
+		cfg.InsecureSkipVerify = true
+		want := normalizeFingerprint(s.PinnedCert)
+		server := strings.TrimSuffix(s.BaseURL, API_VERSION)
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			got := CertFingerprint(cs.PeerCertificates[0].Raw)
+			if normalizeFingerprint(got) != want {
+				return &CertPinError{Server: server, Want: s.PinnedCert, Got: got}
+			}
+			return nil
+		}
+	}
+	return cfg
+}
+
+// ProbeCertificate connects to the https server of settings the same way
+// the client would and returns the certificate it presents. verifyErr
+// reports whether the certificate passes normal verification against
+// settings.RootCAs (the system roots if nil).
+func ProbeCertificate(ctx context.Context, settings Settings) (leaf *x509.Certificate, verifyErr error, err error) {
+	settings.Verify = false
+	settings.PinnedCert = ""
+	settings.Cassette = nil
+	client := NewGNS3Client(settings)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, settings.BaseURL+"/version", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := client.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil, nil, errors.New("server presented no certificate")
+	}
+
+	certs := resp.TLS.PeerCertificates
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, verifyErr = certs[0].Verify(x509.VerifyOptions{
+		Roots:         settings.RootCAs,
+		Intermediates: intermediates,
+		DNSName:       req.URL.Hostname(),
+	})
+	return certs[0], verifyErr, nil
+}
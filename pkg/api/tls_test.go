@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTLSServer starts an https server answering every request with body.
+// Rejected handshakes are expected and not logged.
+func newTLSServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPinnedCert(t *testing.T) {
+	srv := newTLSServer(t, `[]`)
+	pin := CertFingerprint(srv.Certificate().Raw)
+	other := strings.Repeat("AB:", 31) + "AB"
+
+	tests := []struct {
+		name    string
+		opts    []SettingOption
+		wantErr func(error) bool
+	}{
+		{"matching pin", []SettingOption{WithPinnedCert(pin)}, nil},
+		{"pin in another notation", []SettingOption{WithPinnedCert(strings.ToLower(strings.ReplaceAll(pin, ":", "")))}, nil},
+		{"mismatching pin", []SettingOption{WithPinnedCert(other)}, func(err error) bool {
+			var pinErr *CertPinError
+			return errors.As(err, &pinErr) && pinErr.Want == other && pinErr.Got == pin
+		}},
+		{"no pin", nil, func(err error) bool {
+			var unknown x509.UnknownAuthorityError
+			return errors.As(err, &unknown)
+		}},
+		{"no pin, insecure", []SettingOption{WithVerify(false)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := NewSettings(append(tt.opts, withRawBaseURL(srv.URL))...)
+			settings.Retry = RetryPolicy{}
+			_, _, err := NewGNS3Client(settings).Do(NewRequestOptions(settings).WithURL("/projects").WithMethod(GET))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Do() = %v", err)
+				}
+				return
+			}
+			if !tt.wantErr(err) {
+				t.Errorf("Do() = %v", err)
+			}
+		})
+	}
+}
+
+func TestProbeCertificate(t *testing.T) {
+	srv := newTLSServer(t, `{"version": "3.0.0"}`)
+
+	// A pin for another certificate does not get in the way of probing.
+	settings := NewSettings(withRawBaseURL(srv.URL), WithPinnedCert(strings.Repeat("AB:", 31)+"AB"))
+	leaf, verifyErr, err := ProbeCertificate(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !leaf.Equal(srv.Certificate()) {
+		t.Errorf("ProbeCertificate() returned %s, not the server certificate", leaf.Subject)
+	}
+	if verifyErr == nil {
+		t.Error("the self-signed certificate passed verification against the system roots")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	if _, verifyErr, err = ProbeCertificate(context.Background(), NewSettings(withRawBaseURL(srv.URL), WithRootCAs(pool))); err != nil || verifyErr != nil {
+		t.Errorf("ProbeCertificate() with the server as CA = %v, %v", verifyErr, err)
+	}
+}
+
+func TestCertPins(t *testing.T) {
+	pins := CertPins{}
+	pins.Set("https://Lab:3080", "AA")
+	pins.Set("https://proxy/gns3", "BB")
+	tests := []struct {
+		server, want string
+	}{
+		{"https://lab:3080", "AA"},
+		{"https://lab:3080/v3", "AA"},
+		{"https://lab", ""},
+		{"https://proxy:443", "BB"},
+		{"http://proxy", ""},
+	}
+	for _, tt := range tests {
+		if got := pins.For(tt.server); got != tt.want {
+			t.Errorf("For(%q) = %q, want %q", tt.server, got, tt.want)
+		}
+	}
+	if got := CertPins(nil).For("https://lab:3080"); got != "" {
+		t.Errorf("nil For() = %q", got)
+	}
+}
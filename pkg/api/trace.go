@@ -0,0 +1,211 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultTraceBodyLimit is how many bytes of a request or response body a
+// trace shows before truncating it.
+const DefaultTraceBodyLimit = 2048
+
+// Tracer logs every request sent through a client and the response to it.
+// Authorization headers and password/token fields are redacted. With Curl
+// set an equivalent curl command line is printed for each request; it reads
+// the token from $GNS3_TOKEN.
+type Tracer struct {
+	Out       io.Writer
+	Curl      bool
+	BodyLimit int
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewTracer(out io.Writer, curl bool) *Tracer {
+	return &Tracer{Out: out, Curl: curl, BodyLimit: DefaultTraceBodyLimit}
+}
+
+func WithTrace(t *Tracer) SettingOption {
+	return func(s *Settings) {
+		s.Trace = t
+	}
+}
+
+func (t *Tracer) Transport(base http.RoundTripper, insecure bool) http.RoundTripper {
+	return traceTransport{t: t, base: base, insecure: insecure}
+}
+
+func (t *Tracer) printf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = fmt.Fprintf(t.Out, format, args...)
+}
+
+// cutSecretRe matches a secret whose closing quote was cut off with the end
+// of the captured body.
+var cutSecretRe = regexp.MustCompile(`("(?:password|access_token|refresh_token)"\s*:\s*)"(?:[^"\\]|\\.)*\\?$`)
+
+// body formats the start of a body of total bytes for the trace. b may be
+// only the start of the body.
+func (t *Tracer) body(b []byte, total int) string {
+	if total == 0 {
+		return ""
+	}
+	cut := len(b) < total
+	if cut {
+		b = trimCutRune(b)
+	}
+	if !utf8.Valid(b) {
+		return fmt.Sprintf("    <%d bytes of binary data>\n", total)
+	}
+	// Redact before truncating, a secret cut in half by the limit would not
+	// be found anymore.
+	b = redactBody(b)
+	if cut {
+		b = cutSecretRe.ReplaceAll(b, []byte(`$1"`+redacted))
+	}
+	if t.BodyLimit > 0 && len(b) > t.BodyLimit {
+		b = trimCutRune(b[:t.BodyLimit])
+		cut = true
+	}
+	suffix := ""
+	if cut {
+		suffix = fmt.Sprintf(" ... (%d bytes total)", total)
+	}
+	return "    " + strings.ReplaceAll(strings.TrimRight(string(b), "\n"), "\n", "\n    ") + suffix + "\n"
+}
+
+// trimCutRune drops the start of a rune cut in half at the end of b, so it
+// is not mistaken for binary data.
+func trimCutRune(b []byte) []byte {
+	for i := 0; i < utf8.UTFMax-1 && len(b) > 0 && !utf8.Valid(b); i++ {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+type traceTransport struct {
+	t        *Tracer
+	base     http.RoundTripper
+	insecure bool
+}
+
+func (tt traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tt.t.mu.Lock()
+	tt.t.seq++
+	seq := tt.t.seq
+	tt.t.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> #%d %s %s\n", seq, req.Method, req.URL)
+	hdr := redactHeader(req.Header)
+	keys := make([]string, 0, len(hdr))
+	for k := range hdr {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "    %s: %s\n", k, strings.Join(hdr[k], ", "))
+	}
+	b.WriteString(tt.t.body(body, len(body)))
+	if tt.t.Curl {
+		fmt.Fprintf(&b, "    %s\n", curlCommand(req, body, tt.insecure))
+	}
+	tt.t.printf("%s", b.String())
+
+	start := time.Now()
+	resp, err := tt.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Microsecond)
+	if err != nil {
+		tt.t.printf("<-- #%d error after %v: %v\n", seq, elapsed, err)
+		return resp, err
+	}
+	tt.t.printf("<-- #%d %s (%v)\n", seq, resp.Status, elapsed)
+	resp.Body = &tracingBody{ReadCloser: resp.Body, t: tt.t, seq: seq}
+	return resp, nil
+}
+
+// tracingBody logs the response body once the caller has read or closed it,
+// so streamed responses are not buffered up front.
+type tracingBody struct {
+	io.ReadCloser
+	t     *Tracer
+	seq   int
+	buf   bytes.Buffer
+	total int
+	once  sync.Once
+}
+
+func (r *tracingBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.total += n
+	if r.t.BodyLimit <= 0 || r.buf.Len() <= r.t.BodyLimit {
+		r.buf.Write(p[:n])
+	}
+	if err == io.EOF {
+		r.flush()
+	}
+	return n, err
+}
+
+func (r *tracingBody) Close() error {
+	err := r.ReadCloser.Close()
+	r.flush()
+	return err
+}
+
+func (r *tracingBody) flush() {
+	r.once.Do(func() {
+		if body := r.t.body(r.buf.Bytes(), r.total); body != "" {
+			r.t.printf("<-- #%d body\n%s", r.seq, body)
+		}
+	})
+}
+
+func curlCommand(req *http.Request, body []byte, insecure bool) string {
+	parts := []string{"curl"}
+	if insecure {
+		parts = append(parts, "-k")
+	}
+	parts = append(parts, "-X", req.Method, shellQuote(req.URL.String()))
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			parts = append(parts, "-H", `"Authorization: Bearer $GNS3_TOKEN"`)
+			continue
+		}
+		for _, v := range req.Header[k] {
+			parts = append(parts, "-H", shellQuote(k+": "+v))
+		}
+	}
+	if len(body) > 0 {
+		if utf8.Valid(body) {
+			parts = append(parts, "--data-raw", shellQuote(string(redactBody(body))))
+		} else {
+			parts = append(parts, "--data-binary", "@body.bin")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracerBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		body  string
+		total int
+		want  string
+	}{
+		{"whole body", 2048, `{"name": "lab"}`, 0, "    {\"name\": \"lab\"}\n"},
+		{"password", 2048, `{"password": "s3cret"}`, 0, "    {\"password\": \"REDACTED\"}\n"},
+		{"secret at the limit", 16, `{"password":"s3cret"}`, 0, "    {\"password\":\"RED ... (21 bytes total)\n"},
+		{"form secret at the limit", 22, `username=a&password=s3cret`, 0, "    username=a&password=RE ... (26 bytes total)\n"},
+		{"secret cut by the capture", 2048, `{"name":"a","password":"s3cr`, 100, "    {\"name\":\"a\",\"password\":\"REDACTED ... (100 bytes total)\n"},
+		{"rune cut by the limit", 4, "aaa\u00e9", 0, "    aaa ... (5 bytes total)\n"},
+		{"binary", 2048, "\xff\xfe\x00", 0, "    <3 bytes of binary data>\n"},
+		{"empty", 2048, "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := tt.total
+			if total == 0 {
+				total = len(tt.body)
+			}
+			tr := &Tracer{BodyLimit: tt.limit}
+			got := tr.body([]byte(tt.body), total)
+			if got != tt.want {
+				t.Errorf("body() = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(got, "s3c") {
+				t.Errorf("body() = %q shows part of the secret", got)
+			}
+		})
+	}
+}
+
+func TestTracerRedacts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token_type":"bearer","access_token":"eyJ.new.token"}`))
+	}))
+	defer srv.Close()
+
+	var out strings.Builder
+	tr := NewTracer(&out, true)
+	// The limit cuts the access token in the response in half.
+	tr.BodyLimit = 45
+	settings := NewSettings(WithTrace(tr), WithToken("eyJ.old.token"), withRawBaseURL(srv.URL))
+	_, _, err := NewGNS3Client(settings).Do(NewRequestOptions(settings).
+		WithURL("/access/users/authenticate").
+		WithMethod(POST).
+		WithData(`{"username":"admin","password":"s3cret"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"Authorization: Bearer REDACTED",
+		`"Authorization: Bearer $GNS3_TOKEN"`,
+		`{"username":"admin","password":"REDACTED"}`,
+		`"access_token":"REDACT ... (54 bytes total)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("trace lacks %s:\n%s", want, got)
+		}
+	}
+	for _, secret := range []string{"eyJ.old", "eyJ.ne", "s3cret"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("trace shows %s:\n%s", secret, got)
+		}
+	}
+}
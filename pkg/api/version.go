@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+const legacyAPIVersion = "/v2"
+
+// serverInfoTTL is how long a version detected by an earlier run is used
+// before the server is asked again, so upgrades are noticed.
+const serverInfoTTL = 24 * time.Hour
+
+// ServerInfo describes the controller API a client talks to. It is detected
+// through /version on first contact and cached per server, on disk for
+// later runs as well.
+type ServerInfo struct {
+	Version string `json:"version"`
+	Major   int    `json:"major"`
+	// Prefix is the API prefix the server answers on, /v3 or /v2.
+	Prefix string `json:"prefix"`
+}
+
+type cachedServerInfo struct {
+	ServerInfo
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+var defaultServerInfo = ServerInfo{Major: 3, Prefix: API_VERSION}
+
+var (
+	serverInfoMu sync.Mutex
+	serverInfos  = make(map[string]ServerInfo)
+)
+
+// UnsupportedError is returned without contacting the server when a request
+// needs an API the detected server does not provide.
+type UnsupportedError struct {
+	Server   string
+	Version  string
+	Feature  string
+	MinMajor int
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s not supported by %s: it runs GNS3 %s, this needs GNS3v%d or newer",
+		e.Feature, e.Server, e.Version, e.MinMajor)
+}
+
+func IsUnsupported(err error) bool {
+	var e *UnsupportedError
+	return errors.As(err, &e)
+}
+
+// ServerInfo returns the detected API of the client's server. Clients with
+// a base URL not built by WithBaseURL are assumed to speak GNS3v3.
+func (c *GNS3ApiClient) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	server, ok := strings.CutSuffix(c.settings.BaseURL, API_VERSION)
+	if !ok || server == "" {
+		return defaultServerInfo, nil
+	}
+
+	// Recording and replaying skip the file so the cassette always holds
+	// the detection.
+	onDisk := c.settings.Cassette == nil
+
+	serverInfoMu.Lock()
+	info, ok := serverInfos[server]
+	if !ok && onDisk {
+		if info, ok = loadServerInfo(server); ok {
+			serverInfos[server] = info
+		}
+	}
+	serverInfoMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info, cache, err := c.detectServer(ctx, server)
+	if err != nil {
+		return ServerInfo{}, err
+	}
+	if cache {
+		serverInfoMu.Lock()
+		serverInfos[server] = info
+		if onDisk && c.settings.DryRun == nil {
+			saveServerInfo(server, info)
+		}
+		serverInfoMu.Unlock()
+	}
+	return info, nil
+}
+
+func serverInfoPath() (string, error) {
+	dir, err := pathUtils.GetGNS3Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "server_versions.json"), nil
+}
+
+func readServerInfos() (string, map[string]cachedServerInfo) {
+	infos := make(map[string]cachedServerInfo)
+	path, err := serverInfoPath()
+	if err != nil {
+		return "", infos
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &infos)
+	}
+	return path, infos
+}
+
+// loadServerInfo returns the version an earlier run detected for server if
+// it is recent enough.
+func loadServerInfo(server string) (ServerInfo, bool) {
+	_, infos := readServerInfos()
+	cached, ok := infos[server]
+	if !ok || cached.Prefix == "" || time.Since(cached.DetectedAt) > serverInfoTTL {
+		return ServerInfo{}, false
+	}
+	return cached.ServerInfo, true
+}
+
+// saveServerInfo keeps info for later runs. Failing to write it only means
+// the next run detects the version again.
+func saveServerInfo(server string, info ServerInfo) {
+	path, infos := readServerInfos()
+	if path == "" {
+		return
+	}
+	infos[server] = cachedServerInfo{ServerInfo: info, DetectedAt: time.Now()}
+	data, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
+
+// detectServer asks /v3/version and falls back to /v2/version. Failures that
+// say nothing about the API (a cassette without the request, auth errors)
+// give the default without caching it.
+func (c *GNS3ApiClient) detectServer(ctx context.Context, server string) (ServerInfo, bool, error) {
+	for _, prefix := range []string{API_VERSION, legacyAPIVersion} {
+		opts := NewRequestOptions(c.settings).WithContext(ctx).WithURL("/version")
+		opts.base = server + prefix
+		body, _, err := c.Do(opts)
+		switch {
+		case IsNotFound(err):
+			continue
+		case errors.Is(err, errNotRecorded):
+			return defaultServerInfo, false, nil
+		case err != nil:
+			if _, ok := AsAPIError(err); ok && !shouldRetry(err) {
+				return defaultServerInfo, false, nil
+			}
+			return ServerInfo{}, false, fmt.Errorf("failed to detect the API version of %s: %w", server, err)
+		}
+
+		var v struct {
+			Version string `json:"version"`
+		}
+		_ = json.Unmarshal(body, &v)
+		info := ServerInfo{Version: v.Version, Prefix: prefix}
+		major, _, _ := strings.Cut(v.Version, ".")
+		if info.Major, err = strconv.Atoi(major); err != nil {
+			info.Major, _ = strconv.Atoi(strings.TrimPrefix(prefix, "/v"))
+		}
+		return info, true, nil
+	}
+	// Neither prefix answered, let the request itself report the problem.
+	return defaultServerInfo, false, nil
+}
+
+// apiBase returns the URL the request path is appended to for the detected
+// server, or an UnsupportedError if the server lacks the endpoint.
+func (c *GNS3ApiClient) apiBase(ctx context.Context, path string) (string, error) {
+	server, ok := strings.CutSuffix(c.settings.BaseURL, API_VERSION)
+	if !ok || server == "" {
+		return c.settings.BaseURL, nil
+	}
+	info, err := c.ServerInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if f, unsupported := endpoints.Unsupported(path, info.Major); unsupported {
+		return "", &UnsupportedError{Server: server, Version: info.Version, Feature: f.Name, MinMajor: f.MinMajor}
+	}
+	return server + info.Prefix, nil
+}
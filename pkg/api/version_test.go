@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+func TestServerInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		v3      string
+		v2      string
+		want    ServerInfo
+		queries int32
+	}{
+		{"v3", `{"version": "3.0.2"}`, "", ServerInfo{Version: "3.0.2", Major: 3, Prefix: "/v3"}, 1},
+		{"v2 fallback", "", `{"version": "2.2.46"}`, ServerInfo{Version: "2.2.46", Major: 2, Prefix: "/v2"}, 2},
+		{"no version", `{}`, "", ServerInfo{Major: 3, Prefix: "/v3"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homedir.DisableCache = true
+			t.Setenv("HOME", t.TempDir())
+
+			var queries atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries.Add(1)
+				switch {
+				case r.URL.Path == "/v3/version" && tt.v3 != "":
+					_, _ = w.Write([]byte(tt.v3))
+				case r.URL.Path == "/v2/version" && tt.v2 != "":
+					_, _ = w.Write([]byte(tt.v2))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			detect := func() ServerInfo {
+				t.Helper()
+				info, err := NewGNS3Client(NewSettings(WithBaseURL(srv.URL))).ServerInfo(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return info
+			}
+			if got := detect(); got != tt.want {
+				t.Errorf("ServerInfo() = %+v, want %+v", got, tt.want)
+			}
+			if got := queries.Load(); got != tt.queries {
+				t.Errorf("detection made %d requests, want %d", got, tt.queries)
+			}
+
+			// A later run reads the version from disk instead of asking again.
+			serverInfoMu.Lock()
+			delete(serverInfos, srv.URL)
+			serverInfoMu.Unlock()
+			if got := detect(); got != tt.want {
+				t.Errorf("cached ServerInfo() = %+v, want %+v", got, tt.want)
+			}
+			if got := queries.Load(); got != tt.queries {
+				t.Errorf("cached detection made %d more requests", got-tt.queries)
+			}
+			path, _ := serverInfoPath()
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("version cache not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestServerInfoNotCached(t *testing.T) {
+	homedir.DisableCache = true
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	info, err := NewGNS3Client(NewSettings(WithBaseURL(srv.URL))).ServerInfo(context.Background())
+	if err != nil || info != defaultServerInfo {
+		t.Errorf("ServerInfo() = %+v, %v, want the default", info, err)
+	}
+	serverInfoMu.Lock()
+	_, cached := serverInfos[srv.URL]
+	serverInfoMu.Unlock()
+	if cached {
+		t.Error("a failed detection was cached")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".gns3", "server_versions.json")); !os.IsNotExist(err) {
+		t.Errorf("a failed detection was written to disk: %v", err)
+	}
+}
@@ -1,90 +1,268 @@
 package authentication
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/stefanistkuhl/gns3util/pkg/api"
 	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
 	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
 	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
 )
 
 func LoadKeys(keyFileLocation string) ([]pathUtils.GNS3Key, error) {
-	var filePath string
+	filePath, err := keyFilePath(keyFileLocation)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := pathUtils.LoadGNS3KeysFile(filePath)
+	return keys, err
+}
 
+func keyFilePath(keyFileLocation string) (string, error) {
 	if keyFileLocation == "" {
 		dir, err := pathUtils.GetGNS3Dir()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		filePath = filepath.Join(dir, "gns3key")
-	} else {
-		expanded, err := pathUtils.ExpandPath(keyFileLocation)
-		if err != nil {
-			return nil, err
+		return filepath.Join(dir, "gns3key"), nil
+	}
+
+	filePath, err := pathUtils.ExpandPath(keyFileLocation)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		filePath = filepath.Join(filePath, "gns3key")
+	} else if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// WriteKeys replaces the keyfile with keys. Only the owner can read it.
+func WriteKeys(keyFileLocation string, keys []pathUtils.GNS3Key) error {
+	filePath, err := keyFilePath(keyFileLocation)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open key file %q: %w", filePath, err)
+	}
+	defer func() {
+		if f != nil {
+			_ = f.Close()
 		}
-		filePath = expanded
+	}()
+	if err := f.Chmod(0600); err != nil {
+		return fmt.Errorf("failed to restrict permissions of key file %q: %w", filePath, err)
+	}
 
-		if info, err := os.Stat(filePath); err == nil && info.IsDir() {
-			filePath = filepath.Join(filePath, "gns3key")
-		} else if err != nil && !os.IsNotExist(err) {
-			return nil, err
+	for _, key := range keys {
+		buff_key, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(buff_key)
+		if err != nil {
+			return fmt.Errorf("failed to write key to file: %w", err)
+		}
+		_, err = f.WriteString("\n")
+		if err != nil {
+			return fmt.Errorf("failed to write newline to file: %w", err)
 		}
 	}
+	return nil
+}
 
-	keys, err := pathUtils.LoadGNS3KeysFile(filePath)
-	return keys, err
+// CertPinConflictError is returned by LoadCertPins when the identities of a
+// server pin different certificates.
+type CertPinConflictError struct {
+	Servers []string
 }
 
-func TryKeys(keys []pathUtils.GNS3Key, cfg config.GlobalOptions) ([]byte, error) {
+func (e *CertPinConflictError) Error() string {
+	return fmt.Sprintf("the keyfile pins different certificates for %s, none of them is used; run auth login --trust-cert to pin the current one",
+		strings.Join(e.Servers, ", "))
+}
+
+// LoadCertPins returns the pinned certificate fingerprints stored in the
+// keyfile. Servers whose entries disagree get no pin, the pins of the
+// others are returned with a *CertPinConflictError.
+func LoadCertPins(keyFileLocation string) (api.CertPins, error) {
+	keys, err := LoadKeys(keyFileLocation)
+	if err != nil {
+		return nil, err
+	}
+	pins := api.CertPins{}
+	var conflicts []string
 	for _, key := range keys {
-		if normalizeURL(cfg.Server) == normalizeURL(key.ServerURL) {
-			result, success := tryKey(key, cfg)
-			if success {
-				return result, nil
+		if key.CertSHA256 == "" {
+			continue
+		}
+		if pin := pins.For(key.ServerURL); pin != "" && pin != key.CertSHA256 {
+			if !slices.Contains(conflicts, key.ServerURL) {
+				conflicts = append(conflicts, key.ServerURL)
 			}
+			continue
+		}
+		pins.Set(key.ServerURL, key.CertSHA256)
+	}
+	if len(conflicts) > 0 {
+		for _, server := range conflicts {
+			pins.Delete(server)
 		}
+		return pins, &CertPinConflictError{Servers: conflicts}
+	}
+	return pins, nil
+}
+
+func TryKeys(ctx context.Context, keys []pathUtils.GNS3Key, cfg config.GlobalOptions) ([]byte, error) {
+	exact, legacy := matchingKeys(keys, cfg.Server, cfg.As)
+	for _, key := range exact {
+		result, success, err := tryKey(ctx, key, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if success {
+			return result, nil
+		}
+	}
+	for _, key := range legacy {
+		result, success, err := tryKey(ctx, key, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if success {
+			warnLegacyKey(key, cfg.Server)
+			return result, nil
+		}
+	}
+	if cfg.As != "" {
+		return nil, fmt.Errorf("no working API-Key found for the user %s on the server %s. Please use %s to authenticate. ", messageUtils.Bold(cfg.As), messageUtils.Bold(cfg.Server), messageUtils.Bold("auth login -u "+cfg.As))
 	}
 	return nil, fmt.Errorf("no working API-Key found for the server %s. Please use the %s command to authenticate. ", messageUtils.Bold(cfg.Server), messageUtils.Bold("auth login"))
 }
 
-func normalizeURL(url string) string {
-	if strings.HasPrefix(url, "http://") {
-		url = url[7:]
-	} else if strings.HasPrefix(url, "https://") {
-		url = url[8:]
+// CanonicalServerURL returns the origin keyfile entries are matched by: the
+// scheme, host and port, and the path prefix of a server behind a reverse
+// proxy. A missing scheme means http and a missing port the scheme's default.
+func CanonicalServerURL(server string) string {
+	s := strings.TrimSpace(server)
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimRight(server, "/"))
 	}
 
-	if colonIndex := strings.Index(url, ":"); colonIndex != -1 {
-		url = url[:colonIndex]
+	scheme := strings.ToLower(u.Scheme)
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if scheme == "https" {
+			port = "443"
+		}
 	}
+	path := strings.TrimSuffix(strings.TrimRight(u.Path, "/"), api.API_VERSION)
+	return scheme + "://" + net.JoinHostPort(strings.ToLower(u.Hostname()), port) + strings.TrimRight(path, "/")
+}
 
-	return url
+// isLegacyServerURL reports whether a keyfile entry lacks the scheme or the
+// port. Older versions matched entries by host alone, so such an entry may
+// have been used for any server on its host.
+func isLegacyServerURL(server string) bool {
+	u, err := url.Parse(strings.TrimSpace(server))
+	return err != nil || u.Host == "" || u.Port() == ""
 }
 
-func tryKey(key pathUtils.GNS3Key, cfg config.GlobalOptions) ([]byte, bool) {
-	settings := api.NewSettings(
-		api.WithBaseURL(cfg.Server),
-		api.WithVerify(!cfg.Insecure),
-		api.WithToken(key.AccessToken),
-	)
+func serverHost(server string) string {
+	u, err := url.Parse(CanonicalServerURL(server))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// matchingKeys returns the entries stored for exactly the server's origin
+// and the legacy entries for its host, which are only a fallback. A server
+// can have an entry per user; user selects one of them, otherwise they are
+// returned in keyfile order so the first one is the default identity.
+func matchingKeys(keys []pathUtils.GNS3Key, server, user string) (exact, legacy []pathUtils.GNS3Key) {
+	origin := CanonicalServerURL(server)
+	for _, key := range keys {
+		switch {
+		case user != "" && key.User != user:
+		case CanonicalServerURL(key.ServerURL) == origin:
+			exact = append(exact, key)
+		case isLegacyServerURL(key.ServerURL) && serverHost(key.ServerURL) == serverHost(server):
+			legacy = append(legacy, key)
+		}
+	}
+	return exact, legacy
+}
+
+func warnLegacyKey(key pathUtils.GNS3Key, server string) {
+	fmt.Fprintf(os.Stderr, "%v using the token stored for %s for %s. Keyfile entries are matched by scheme, host and port now, use %s to store a token for this server.\n",
+		messageUtils.WarningMsg("Ambiguous keyfile entry"), key.ServerURL, server, messageUtils.Bold("auth login"))
+}
+
+// migrateKeys stores entries under their canonical origin and drops
+// duplicates of the same user. Legacy entries are kept as they are, their
+// port is unknown.
+func migrateKeys(keys []pathUtils.GNS3Key) []pathUtils.GNS3Key {
+	seen := make(map[[2]string]bool)
+	var migrated []pathUtils.GNS3Key
+	for _, key := range keys {
+		if !isLegacyServerURL(key.ServerURL) {
+			key.ServerURL = CanonicalServerURL(key.ServerURL)
+		}
+		id := [2]string{key.ServerURL, key.User}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		migrated = append(migrated, key)
+	}
+	return migrated
+}
+
+func tryKey(ctx context.Context, key pathUtils.GNS3Key, cfg config.GlobalOptions) ([]byte, bool, error) {
+	token, err := credstore.Decrypt(key.AccessToken)
+	if err != nil {
+		return nil, false, err
+	}
+	settings := config.APISettings(cfg, token)
+	// A rejected key is reported, logging in again would hide it.
+	settings.Reauth = nil
 
 	ep := endpoints.GetEndpoints{}
 
 	client := api.NewGNS3Client(settings)
 	reqOpts := api.
 		NewRequestOptions(settings).
+		WithContext(ctx).
 		WithURL(ep.Me()).
 		WithMethod(api.GET)
 
 	body, resp, err := client.Do(reqOpts)
+	if api.IsUnauthorized(err) {
+		return body, false, nil
+	}
 	if err != nil {
 		log.Fatalf("API error: %v", err)
 	}
@@ -94,29 +272,19 @@ func tryKey(key pathUtils.GNS3Key, cfg config.GlobalOptions) ([]byte, bool) {
 		}
 	}()
 	if resp.StatusCode == 200 {
-		return body, true
+		return body, true, nil
 	} else {
-		return body, false
+		return body, false, nil
 	}
 
 }
 
 func SaveAuthData(cfg config.GlobalOptions, token schemas.Token, username string) error {
-	var keyFileLocation string
-	if cfg.KeyFile != "" {
-		k, err := pathUtils.ExpandPath(cfg.KeyFile)
-		if err != nil {
-			return err
-		}
-		keyFileLocation = k
-	} else {
-		k, err := pathUtils.GetGNS3Dir()
-		if err != nil {
-			return err
-		}
-		keyFileLocation = filepath.Join(k, "gns3key")
+	// Replayed tokens are redacted and a dry run changes nothing locally
+	// either, keep the real token in the keyfile.
+	if cfg.Cassette.Replaying() || cfg.DryRun != nil {
+		return nil
 	}
-
 	keys, err := LoadKeys(cfg.KeyFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -124,16 +292,28 @@ func SaveAuthData(cfg config.GlobalOptions, token schemas.Token, username string
 		}
 	}
 
+	accessToken, err := credstore.Encrypt(*token.AccessToken)
+	if err != nil {
+		return err
+	}
 	newKey := pathUtils.GNS3Key{
-		ServerURL:   cfg.Server,
+		ServerURL:   CanonicalServerURL(cfg.Server),
 		User:        username,
-		AccessToken: *token.AccessToken,
+		AccessToken: accessToken,
 		TokenType:   *token.TokenType,
+		CertSHA256:  cfg.CertPins.For(cfg.Server),
+	}
+	if exp, ok := TokenExpiry(*token.AccessToken); ok {
+		newKey.ExpiresAt = exp.Unix()
 	}
 
+	// Other users keep their entries, so a server can have several
+	// identities to pick from with --as.
+	keys = migrateKeys(keys)
 	found := false
 	for i, key := range keys {
-		if key.ServerURL == newKey.ServerURL {
+		if key.ServerURL == newKey.ServerURL && key.User == newKey.User {
+			newKey.Password = key.Password
 			keys[i] = newKey
 			found = true
 			break
@@ -142,62 +322,42 @@ func SaveAuthData(cfg config.GlobalOptions, token schemas.Token, username string
 	if !found {
 		keys = append(keys, newKey)
 	}
-
-	f, err := os.OpenFile(keyFileLocation, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open key file %q: %w", keyFileLocation, err)
-	}
-	defer func() {
-		if f != nil {
-			_ = f.Close()
-		}
-	}()
-
-	for _, key := range keys {
-		buff_key, err := json.Marshal(key)
-		if err != nil {
-			return err
-		}
-		_, err = f.Write(buff_key)
-		if err != nil {
-			return fmt.Errorf("failed to write key to file: %w", err)
-		}
-		_, err = f.WriteString("\n")
-		if err != nil {
-			return fmt.Errorf("failed to write newline to file: %w", err)
+	// A certificate trusted again replaces the pin of every identity.
+	if newKey.CertSHA256 != "" {
+		server := api.CertPins{}
+		server.Set(cfg.Server, newKey.CertSHA256)
+		for i := range keys {
+			if server.For(keys[i].ServerURL) != "" {
+				keys[i].CertSHA256 = newKey.CertSHA256
+			}
 		}
 	}
 
-	return nil
+	return WriteKeys(cfg.KeyFile, keys)
 }
 
 func GetKeyForServer(cfg config.GlobalOptions) (string, error) {
-
-	var keyFileLocation string
-	if cfg.KeyFile != "" {
-		k, err := pathUtils.ExpandPath(cfg.KeyFile)
-		if err != nil {
-			return "", err
-		}
-		keyFileLocation = k
-	} else {
-		k, err := pathUtils.GetGNS3Dir()
-		if err != nil {
-			return "", err
-		}
-		keyFileLocation = filepath.Join(k, "gns3key")
-	}
-
-	keys, err := LoadKeys(keyFileLocation)
+	keys, err := LoadKeys(cfg.KeyFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			panic(err)
 		}
 	}
-	for _, key := range keys {
-		if normalizeURL(key.ServerURL) == normalizeURL(cfg.Server) {
-			return key.AccessToken, nil
-		}
+	exact, legacy := matchingKeys(keys, cfg.Server, cfg.As)
+	if len(exact) > 0 {
+		return credstore.Decrypt(exact[0].AccessToken)
+	}
+	if len(legacy) > 0 {
+		warnLegacyKey(legacy[0], cfg.Server)
+		return credstore.Decrypt(legacy[0].AccessToken)
+	}
+	if cfg.Cassette.Replaying() {
+		// Replayed responses do not depend on the token, so a machine without
+		// a login for the recorded server can still run the command.
+		return "", nil
+	}
+	if cfg.As != "" {
+		return "", fmt.Errorf("could not find an access token for the user %s on the server %s, please use %s to login as them. ", cfg.As, cfg.Server, messageUtils.Bold("auth login -u "+cfg.As))
 	}
 	return "", fmt.Errorf("could not find find a matching access token for the server %s, please use the %s command to login to the server. ", cfg.Server, messageUtils.Bold("auth login"))
 }
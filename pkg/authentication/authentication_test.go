@@ -0,0 +1,196 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+func TestSaveAuthDataSkipped(t *testing.T) {
+	dir := t.TempDir()
+	cassetteDir := filepath.Join(dir, "cassette")
+	if err := os.MkdirAll(cassetteDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cassetteDir, "00001-GET-version.json"),
+		[]byte(`{"seq": 1, "request": {"method": "GET", "url": "http://lab:3080/v3/version", "body": {"text": ""}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	replayer, err := api.NewReplayer(cassetteDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.GlobalOptions
+	}{
+		{"replay", config.GlobalOptions{Cassette: replayer}},
+		{"dry run", config.GlobalOptions{DryRun: api.NewDryRun()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "gns3key")
+			cfg := tt.cfg
+			cfg.Server, cfg.KeyFile = "http://lab:3080", keyFile
+			token, tokenType := "REDACTED", "bearer"
+			if err := SaveAuthData(cfg, schemas.Token{AccessToken: &token, TokenType: &tokenType}, "admin"); err != nil {
+				t.Fatal(err)
+			}
+			if err := SavePassword(cfg, "admin", "s3cret"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(keyFile); !os.IsNotExist(err) {
+				t.Errorf("login wrote the keyfile: %v", err)
+			}
+		})
+	}
+}
+
+func TestCanonicalServerURL(t *testing.T) {
+	tests := []struct {
+		server string
+		want   string
+	}{
+		{"http://lab:3080", "http://lab:3080"},
+		{"lab:3080", "http://lab:3080"},
+		{"lab", "http://lab:80"},
+		{"https://lab", "https://lab:443"},
+		{"HTTP://Lab.Example:3080/", "http://lab.example:3080"},
+		{"http://lab:3080/v3", "http://lab:3080"},
+		{"https://proxy/gns3/v3/", "https://proxy:443/gns3"},
+		{"  http://lab:3080  ", "http://lab:3080"},
+		{"http://[::1]:3080", "http://[::1]:3080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.server, func(t *testing.T) {
+			if got := CanonicalServerURL(tt.server); got != tt.want {
+				t.Errorf("CanonicalServerURL(%q) = %q, want %q", tt.server, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []pathUtils.GNS3Key
+		want []pathUtils.GNS3Key
+	}{
+		{
+			"canonicalized",
+			[]pathUtils.GNS3Key{{ServerURL: "HTTP://Lab:3080/", User: "admin", AccessToken: "a"}},
+			[]pathUtils.GNS3Key{{ServerURL: "http://lab:3080", User: "admin", AccessToken: "a"}},
+		},
+		{
+			"duplicate of the same user dropped",
+			[]pathUtils.GNS3Key{
+				{ServerURL: "http://lab:3080", User: "admin", AccessToken: "a"},
+				{ServerURL: "http://lab:3080/v3", User: "admin", AccessToken: "b"},
+			},
+			[]pathUtils.GNS3Key{{ServerURL: "http://lab:3080", User: "admin", AccessToken: "a"}},
+		},
+		{
+			"other users kept",
+			[]pathUtils.GNS3Key{
+				{ServerURL: "http://lab:3080", User: "admin", AccessToken: "a"},
+				{ServerURL: "http://lab:3080", User: "alice", AccessToken: "b"},
+			},
+			[]pathUtils.GNS3Key{
+				{ServerURL: "http://lab:3080", User: "admin", AccessToken: "a"},
+				{ServerURL: "http://lab:3080", User: "alice", AccessToken: "b"},
+			},
+		},
+		{
+			"legacy entries kept as they are",
+			[]pathUtils.GNS3Key{
+				{ServerURL: "lab", User: "admin", AccessToken: "a"},
+				{ServerURL: "http://lab", User: "admin", AccessToken: "b"},
+			},
+			[]pathUtils.GNS3Key{
+				{ServerURL: "lab", User: "admin", AccessToken: "a"},
+				{ServerURL: "http://lab", User: "admin", AccessToken: "b"},
+			},
+		},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := migrateKeys(tt.keys); !slices.Equal(got, tt.want) {
+				t.Errorf("migrateKeys() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryKeysDoesNotReauth(t *testing.T) {
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	defer ts.Close()
+	reauths := 0
+	cfg := config.GlobalOptions{
+		Server: ts.URL,
+		Reauth: func(ctx context.Context, server, expired string) (string, error) {
+			reauths++
+			return "", api.ErrNoCredentials
+		},
+	}
+	keys := []pathUtils.GNS3Key{{ServerURL: CanonicalServerURL(ts.URL), User: "admin", AccessToken: "expired"}}
+	_, err := TryKeys(context.Background(), keys, cfg)
+	if err == nil || !strings.Contains(err.Error(), "no working API-Key") {
+		t.Errorf("TryKeys() = %v, want no working key", err)
+	}
+	if reauths != 0 {
+		t.Errorf("TryKeys() logged in again %d times", reauths)
+	}
+}
+
+func TestLoadCertPins(t *testing.T) {
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	keyFile := filepath.Join(t.TempDir(), "gns3key")
+	if err := WriteKeys(keyFile, []pathUtils.GNS3Key{
+		{ServerURL: "https://lab:3080", User: "admin", CertSHA256: "AA"},
+		{ServerURL: "https://lab:3080", User: "alice", CertSHA256: "AA"},
+		{ServerURL: "https://lab:3080", User: "bob"},
+		{ServerURL: "https://exam:3080", User: "admin", CertSHA256: "BB"},
+		{ServerURL: "https://exam:3080", User: "alice", CertSHA256: "CC"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pins, err := LoadCertPins(keyFile)
+	var conflict *CertPinConflictError
+	if !errors.As(err, &conflict) || !slices.Equal(conflict.Servers, []string{"https://exam:3080"}) {
+		t.Fatalf("LoadCertPins() error = %v, want a conflict for exam", err)
+	}
+	if got := pins.For("https://lab:3080"); got != "AA" {
+		t.Errorf("pin of lab = %q, want AA", got)
+	}
+	if got := pins.For("https://exam:3080"); got != "" {
+		t.Errorf("pin of exam = %q, want none", got)
+	}
+
+	// Trusting the certificate again as one user pins it for all of them.
+	cfg := config.GlobalOptions{Server: "https://exam:3080", KeyFile: keyFile, CertPins: pins}
+	cfg.CertPins.Set(cfg.Server, "DD")
+	token, tokenType := "token", "bearer"
+	if err := SaveAuthData(cfg, schemas.Token{AccessToken: &token, TokenType: &tokenType}, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	pins, err = LoadCertPins(keyFile)
+	if err != nil || pins.For("https://exam:3080") != "DD" || pins.For("https://lab:3080") != "AA" {
+		t.Errorf("LoadCertPins() after trusting again = %v, %v", pins, err)
+	}
+}
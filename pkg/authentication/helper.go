@@ -0,0 +1,131 @@
+package authentication
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// CredentialHelperEnv names the credential helper when neither
+// --credential-helper nor the context sets one.
+const CredentialHelperEnv = "GNS3UTIL_CREDENTIAL_HELPER"
+
+// Credential is what a credential helper gets on stdin and answers with, in
+// the key=value lines of git's credential helper protocol.
+type Credential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// CredentialFor describes the origin of server for a helper. username is
+// passed along if it is already known.
+func CredentialFor(server, username string) Credential {
+	c := Credential{Username: username}
+	u, err := url.Parse(CanonicalServerURL(server))
+	if err != nil {
+		c.Host = server
+		return c
+	}
+	c.Protocol = u.Scheme
+	c.Host = u.Host
+	c.Path = strings.TrimPrefix(u.Path, "/")
+	return c
+}
+
+func (c Credential) encode(withPassword bool) []byte {
+	var b bytes.Buffer
+	write := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s=%s\n", key, value)
+		}
+	}
+	write("protocol", c.Protocol)
+	write("host", c.Host)
+	write("path", c.Path)
+	write("username", c.Username)
+	if withPassword {
+		write("password", c.Password)
+	}
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+// CredentialHelper returns the helper command to use, helper if it is set
+// and GNS3UTIL_CREDENTIAL_HELPER otherwise.
+func CredentialHelper(helper string) string {
+	if helper != "" {
+		return helper
+	}
+	return os.Getenv(CredentialHelperEnv)
+}
+
+// runHelper runs the helper command with the action appended, like git
+// does for "!" helpers, and returns its stdout.
+func runHelper(ctx context.Context, helper, action string, input []byte) ([]byte, error) {
+	line := helper + " " + action
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", line)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", line)
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %q failed: %w", helper, err)
+	}
+	return out, nil
+}
+
+// HelperGet asks the helper for the credentials of c. ok is false if it
+// has no username and password for it.
+func HelperGet(ctx context.Context, helper string, c Credential) (Credential, bool, error) {
+	out, err := runHelper(ctx, helper, "get", c.encode(false))
+	if err != nil {
+		return c, false, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "username":
+			c.Username = value
+		case "password":
+			c.Password = value
+		case "quit":
+			if value == "1" || value == "true" {
+				return c, false, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return c, false, err
+	}
+	return c, c.Username != "" && c.Password != "", nil
+}
+
+// HelperStore tells the helper that c worked, so caching helpers can keep
+// it.
+func HelperStore(ctx context.Context, helper string, c Credential) error {
+	_, err := runHelper(ctx, helper, "store", c.encode(true))
+	return err
+}
+
+// HelperErase tells the helper that c was rejected by the server.
+func HelperErase(ctx context.Context, helper string, c Credential) error {
+	_, err := runHelper(ctx, helper, "erase", c.encode(true))
+	return err
+}
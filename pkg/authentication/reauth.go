@@ -0,0 +1,313 @@
+package authentication
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+// TokenExpiry reads the exp claim of a JWT access token. The signature is
+// not checked, that is up to the server.
+func TokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+// credentialSource returns the user and password to log in to a server
+// again, ok is false if it has none. key is the keyfile entry of the
+// server and may be empty.
+type credentialSource func(ctx context.Context, cfg config.GlobalOptions, key pathUtils.GNS3Key) (username, password string, ok bool, err error)
+
+var credentialSources = []credentialSource{envCredentials, storedCredentials, helperCredentials}
+
+// envCredentials uses GNS3_USER and GNS3_PASSWORD like auth login. They
+// only apply to the user the token belonged to.
+func envCredentials(ctx context.Context, cfg config.GlobalOptions, key pathUtils.GNS3Key) (string, string, bool, error) {
+	password := os.Getenv("GNS3_PASSWORD")
+	username := cmp.Or(os.Getenv("GNS3_USER"), key.User)
+	if password == "" || username == "" || (key.User != "" && username != key.User) {
+		return "", "", false, nil
+	}
+	return username, password, true, nil
+}
+
+// storedCredentials uses the password auth login kept in the encrypted
+// credential store.
+func storedCredentials(ctx context.Context, cfg config.GlobalOptions, key pathUtils.GNS3Key) (string, string, bool, error) {
+	if key.Password == "" || key.User == "" {
+		return "", "", false, nil
+	}
+	password, err := credstore.Decrypt(key.Password)
+	if err != nil {
+		return "", "", false, err
+	}
+	return key.User, password, true, nil
+}
+
+// helperCredentials asks the credential helper for the password of the
+// user the token belonged to.
+func helperCredentials(ctx context.Context, cfg config.GlobalOptions, key pathUtils.GNS3Key) (string, string, bool, error) {
+	if cfg.CredentialHelper == "" {
+		return "", "", false, nil
+	}
+	c, ok, err := HelperGet(ctx, cfg.CredentialHelper, CredentialFor(cfg.Server, cmp.Or(key.User, cfg.As)))
+	if err != nil || !ok || (key.User != "" && c.Username != key.User) {
+		return "", "", false, err
+	}
+	return c.Username, c.Password, true, nil
+}
+
+var (
+	reauthMu sync.Mutex
+	// reauthMu also serializes the keyfile writes of concurrent logins.
+	// reauthed holds the tokens logged in again during this run by server
+	// and user, so concurrent requests that all failed with the old token
+	// log in once.
+	reauthed = make(map[string]string)
+)
+
+// Reauthenticate logs in to cfg.Server again after expired was rejected,
+// saves the new token and returns it. It returns api.ErrNoCredentials if no
+// credential source knows the password.
+func Reauthenticate(ctx context.Context, cfg config.GlobalOptions, expired string) (string, error) {
+	reauthMu.Lock()
+	defer reauthMu.Unlock()
+
+	key, _ := KeyForServer(cfg)
+	id := CanonicalServerURL(cfg.Server) + "\x00" + key.User
+	if token, ok := reauthed[id]; ok && token != expired {
+		return token, nil
+	}
+
+	for _, source := range credentialSources {
+		username, password, ok, err := source(ctx, cfg, key)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+
+		token, err := authenticate(ctx, cfg, username, password)
+		ReportCredentials(ctx, cfg, username, password, err)
+		if err != nil {
+			return "", err
+		}
+		if err := SaveAuthData(cfg, token, username); err != nil {
+			return "", fmt.Errorf("failed to save the new token: %w", err)
+		}
+		reauthed[id] = *token.AccessToken
+		fmt.Fprintf(os.Stderr, "%v the token for %s was rejected, logged in again as %s\n",
+			messageUtils.InfoMsg("Logged in again"), cfg.Server, messageUtils.Bold(username))
+		return *token.AccessToken, nil
+	}
+	return "", api.ErrNoCredentials
+}
+
+func authenticate(ctx context.Context, cfg config.GlobalOptions, username, password string) (schemas.Token, error) {
+	cfg.Reauth = nil
+	settings := config.APISettings(cfg, "")
+	data, err := json.Marshal(schemas.Credentials{Username: username, Password: password})
+	if err != nil {
+		return schemas.Token{}, err
+	}
+
+	client := api.NewGNS3Client(settings)
+	body, _, err := client.Do(api.NewRequestOptions(settings).
+		WithContext(ctx).
+		WithURL(endpoints.PostEndpoints{}.UserAuthenticate()).
+		WithMethod(api.POST).
+		WithData(string(data)))
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("failed to log in as %s: %w", username, err)
+	}
+
+	var token schemas.Token
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == nil {
+		return schemas.Token{}, fmt.Errorf("failed to read the token of %s", username)
+	}
+	if token.TokenType == nil {
+		tokenType := "bearer"
+		token.TokenType = &tokenType
+	}
+	return token, nil
+}
+
+// Login authenticates as username on cfg.Server and saves the token like
+// auth login. Failing to save the password only costs logging in again
+// later, it is reported as a warning.
+func Login(ctx context.Context, cfg config.GlobalOptions, username, password string) error {
+	reauthMu.Lock()
+	defer reauthMu.Unlock()
+
+	token, err := authenticate(ctx, cfg, username, password)
+	ReportCredentials(ctx, cfg, username, password, err)
+	if err != nil {
+		return err
+	}
+	if err := SaveAuthData(cfg, token, username); err != nil {
+		return fmt.Errorf("failed to write authentication data to the keyfile: %w", err)
+	}
+	if err := SavePassword(cfg, username, password); err != nil {
+		fmt.Fprintf(os.Stderr, "%v failed to save the password for logging in again: %v\n", messageUtils.WarningMsg("Warning"), err)
+	}
+	return nil
+}
+
+// LoginCredentials completes username and password from the credential
+// helper. They are returned unchanged if both are set or there is no helper.
+func LoginCredentials(ctx context.Context, cfg config.GlobalOptions, username, password string) (string, string, error) {
+	if (username != "" && password != "") || cfg.CredentialHelper == "" {
+		return username, password, nil
+	}
+	c, ok, err := HelperGet(ctx, cfg.CredentialHelper, CredentialFor(cfg.Server, username))
+	if err != nil || !ok {
+		return username, password, err
+	}
+	if username != "" && c.Username != username {
+		return username, password, nil
+	}
+	return c.Username, cmp.Or(password, c.Password), nil
+}
+
+// ReportCredentials tells the credential helper whether the server accepted
+// username and password, so it can store them or drop rejected ones like
+// git's helpers do. loginErr is the result of the login.
+func ReportCredentials(ctx context.Context, cfg config.GlobalOptions, username, password string, loginErr error) {
+	if cfg.CredentialHelper == "" {
+		return
+	}
+	c := CredentialFor(cfg.Server, username)
+	c.Password = password
+	var err error
+	switch {
+	case loginErr == nil:
+		err = HelperStore(ctx, cfg.CredentialHelper, c)
+	case api.IsUnauthorized(loginErr):
+		err = HelperErase(ctx, cfg.CredentialHelper, c)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v %v\n", messageUtils.WarningMsg("Warning"), err)
+	}
+}
+
+// KeyForServer returns the keyfile entry used for cfg.Server, the one of
+// cfg.As if it is set.
+func KeyForServer(cfg config.GlobalOptions) (pathUtils.GNS3Key, bool) {
+	keys, err := LoadKeys(cfg.KeyFile)
+	if err != nil {
+		return pathUtils.GNS3Key{}, false
+	}
+	exact, legacy := matchingKeys(keys, cfg.Server, cfg.As)
+	if len(exact) > 0 {
+		return exact[0], true
+	}
+	if len(legacy) > 0 {
+		return legacy[0], true
+	}
+	return pathUtils.GNS3Key{}, false
+}
+
+// SavePassword keeps the password of username on cfg.Server for logging in
+// again, encrypted with the credential store. Without the store nothing is
+// saved.
+func SavePassword(cfg config.GlobalOptions, username, password string) error {
+	if !credstore.Enabled() || cfg.Cassette.Replaying() || cfg.DryRun != nil {
+		return nil
+	}
+	keys, err := LoadKeys(cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+	origin := CanonicalServerURL(cfg.Server)
+	for i, key := range keys {
+		if key.ServerURL != origin || key.User != username {
+			continue
+		}
+		if keys[i].Password, err = credstore.Encrypt(password); err != nil {
+			return err
+		}
+		return WriteKeys(cfg.KeyFile, keys)
+	}
+	return nil
+}
+
+// Identity is a user with a token stored for a server.
+type Identity struct {
+	Server    string `json:"server"`
+	User      string `json:"user"`
+	Default   bool   `json:"default"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// Identities lists the users stored in the keyfile per server. The first
+// user of a server is the one used without --as.
+func Identities(keyFile string) ([]Identity, error) {
+	keys, err := LoadKeys(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	identities := make([]Identity, 0, len(keys))
+	for _, key := range migrateKeys(keys) {
+		identities = append(identities, Identity{
+			Server:    key.ServerURL,
+			User:      key.User,
+			Default:   !seen[key.ServerURL],
+			ExpiresAt: key.ExpiresAt,
+		})
+		seen[key.ServerURL] = true
+	}
+	return identities, nil
+}
+
+// RemoveIdentity deletes the keyfile entry of cfg.Server for cfg.As, or the
+// default one if it is empty, and returns the user it belonged to.
+func RemoveIdentity(cfg config.GlobalOptions) (string, error) {
+	keys, err := LoadKeys(cfg.KeyFile)
+	if err != nil {
+		return "", err
+	}
+	key, ok := KeyForServer(cfg)
+	if !ok {
+		if cfg.As != "" {
+			return "", fmt.Errorf("no token for the user %s is stored for %s", cfg.As, cfg.Server)
+		}
+		return "", fmt.Errorf("no token is stored for %s", cfg.Server)
+	}
+	kept := keys[:0]
+	for _, k := range keys {
+		if k.ServerURL == key.ServerURL && k.User == key.User {
+			continue
+		}
+		kept = append(kept, k)
+	}
+	return key.User, WriteKeys(cfg.KeyFile, kept)
+}
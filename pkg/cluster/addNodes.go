@@ -1,7 +1,10 @@
 package cluster
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -35,16 +38,31 @@ func RunAddNode(server string, opts *AddNodeOptions, cmd *cobra.Command) (db.Nod
 	}
 
 	cfg.Server = server
-	_, status, reqErr := utils.CallClient(cfg, "getMe", nil, nil)
+	_, status, reqErr := utils.CallClient(cmd.Context(), cfg, "getMe", nil, nil)
+	loggedInAs := ""
+	if reqErr != nil {
+		// Without a working token, log in with the credentials from the
+		// flags, the environment or the credential helper.
+		user := opts.Username
+		if user == "" && opts.ServerUsers != nil {
+			user = opts.ServerUsers[server]
+		}
+		var loginErr error
+		if loggedInAs, loginErr = loginNode(cmd.Context(), cfg, user, opts.Password); loginErr == nil {
+			_, status, reqErr = utils.CallClient(cmd.Context(), cfg, "getMe", nil, nil)
+		} else if !errors.Is(loginErr, errNoNodeCredentials) {
+			reqErr = loginErr
+		}
+	}
 	if reqErr != nil || status != 200 {
-		return db.NodeData{}, fmt.Errorf("failed to query node %s: %v", server, reqErr)
+		return db.NodeData{}, fmt.Errorf("failed to query node %s: %w", server, reqErr)
 	}
 	port, toIntErr := strconv.Atoi(u.Port())
 	if toIntErr != nil {
 		return db.NodeData{}, fmt.Errorf("failed to convert port to an int")
 	}
 
-	username := strings.TrimSpace(opts.Username)
+	username := strings.TrimSpace(cmp.Or(opts.Username, loggedInAs))
 	if username == "" && opts.ServerUsers != nil {
 		if mappedUser, ok := opts.ServerUsers[server]; ok {
 			username = strings.TrimSpace(mappedUser)
@@ -61,6 +79,23 @@ func RunAddNode(server string, opts *AddNodeOptions, cmd *cobra.Command) (db.Nod
 	}, nil
 }
 
+var errNoNodeCredentials = errors.New("no credentials for the node")
+
+func loginNode(ctx context.Context, cfg config.GlobalOptions, username, password string) (string, error) {
+	username, password, err := authentication.LoginCredentials(ctx, cfg, username, password)
+	if err != nil {
+		return "", err
+	}
+	if username == "" || password == "" {
+		return "", errNoNodeCredentials
+	}
+	if err := authentication.Login(ctx, cfg, username, password); err != nil {
+		return "", err
+	}
+	fmt.Printf("%s %s\n", colorUtils.Info("Logged in to "+cfg.Server+" as"), colorUtils.Highlight(username))
+	return username, nil
+}
+
 func RunAddNodes(opts *AddNodeOptions, cmd *cobra.Command) ([]db.NodeData, error) {
 	if len(opts.Servers) == 0 {
 		fmt.Printf("%s\n", colorUtils.Info("No servers provided, entering interactive mode..."))
@@ -14,13 +14,60 @@ import (
 
 var ErrNoConfig = errors.New("no config for clusters")
 
+// scratchDir replaces the gns3 directory for the cluster config and database,
+// see UseScratchCopy.
+var scratchDir string
+
+func configPath() (string, error) {
+	if scratchDir != "" {
+		return filepath.Join(scratchDir, "cluster_config.toml"), nil
+	}
+	dir, err := utils.GetGNS3Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cluster_config.toml"), nil
+}
+
+// UseScratchCopy copies the cluster config and database to a temporary
+// directory and uses the copies for the rest of the process, so a dry run
+// can go through the usual bookkeeping without changing the real ones. The
+// returned func switches back and removes the copies.
+func UseScratchCopy() (func(), error) {
+	src, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "gns3util-dry-run-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		scratchDir = ""
+		_ = db.UseScratchCopy("")
+		_ = os.RemoveAll(dir)
+	}
+
+	if data, readErr := os.ReadFile(src); readErr == nil {
+		if err := os.WriteFile(filepath.Join(dir, "cluster_config.toml"), data, 0o600); err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+	if err := db.UseScratchCopy(dir); err != nil {
+		cleanup()
+		return nil, err
+	}
+	scratchDir = dir
+	return cleanup, nil
+}
+
 func LoadClusterConfig() (Config, error) {
 	var c Config
-	dir, getDirErr := utils.GetGNS3Dir()
+	path, getDirErr := configPath()
 	if getDirErr != nil {
 		return c, getDirErr
 	}
-	path := filepath.Join(dir, "cluster_config.toml")
 	f, readErr := os.ReadFile(path)
 	if readErr != nil {
 		return c, ErrNoConfig
@@ -92,11 +139,10 @@ func WriteClusterConfig(c Config) error {
 			c.Clusters[i].Nodes = nil
 		}
 	}
-	dir, getDirErr := utils.GetGNS3Dir()
+	path, getDirErr := configPath()
 	if getDirErr != nil {
 		return getDirErr
 	}
-	path := filepath.Join(dir, "cluster_config.toml")
 	res, marshallErr := toml.Marshal(&c)
 	if marshallErr != nil {
 		return marshallErr
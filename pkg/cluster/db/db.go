@@ -13,6 +13,7 @@ import (
 	_ "modernc.org/sqlite"
 
 	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/credstore"
 	"github.com/stefanistkuhl/gns3util/pkg/utils"
 )
 
@@ -43,12 +44,55 @@ func openDB(dbPath string) (*sql.DB, error) {
 	return db, nil
 }
 
-func InitIfNeeded() (*sql.DB, error) {
+// scratchPath replaces the cluster database for the rest of the process,
+// see UseScratchCopy.
+var scratchPath string
+
+func dbFilePath() (string, error) {
+	if scratchPath != "" {
+		return scratchPath, nil
+	}
 	dir, err := utils.GetGNS3Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clusterData.db"), nil
+}
+
+// UseScratchCopy copies the cluster database into dir and points every later
+// connection at the copy. Passing "" switches back to the real database.
+func UseScratchCopy(dir string) error {
+	if dir == "" {
+		scratchPath = ""
+		return nil
+	}
+	src, err := dbFilePath()
+	if err != nil {
+		return fmt.Errorf("get dir: %w", err)
+	}
+	dst := filepath.Join(dir, "clusterData.db")
+
+	if _, statErr := os.Stat(src); statErr == nil {
+		conn, err := openDB(src)
+		if err != nil {
+			return err
+		}
+		_, err = conn.Exec("VACUUM INTO ?", dst)
+		_ = conn.Close()
+		if err != nil {
+			return fmt.Errorf("copy cluster database: %w", err)
+		}
+	}
+
+	scratchPath = dst
+	return nil
+}
+
+func InitIfNeeded() (*sql.DB, error) {
+	dbPath, err := dbFilePath()
 	if err != nil {
 		return nil, fmt.Errorf("get dir: %w", err)
 	}
-	dbPath := filepath.Join(dir, "clusterData.db")
 
 	_, statErr := os.Stat(dbPath)
 	if os.IsNotExist(statErr) {
@@ -77,12 +121,10 @@ func InitIfNeeded() (*sql.DB, error) {
 }
 
 func CheckIfCluterExists(name string) error {
-	dir, err := utils.GetGNS3Dir()
+	dbPath, err := dbFilePath()
 	if err != nil {
 		return ErrNoDb
 	}
-
-	dbPath := filepath.Join(dir, "clusterData.db")
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		return ErrNoDb
 	}
@@ -387,6 +429,18 @@ func GetClusters(conn *sql.DB) ([]ClusterName, error) {
 }
 
 func InsertClassIntoDB(conn *sql.DB, clusterID int, data schemas.Class) (InsertedClassData, error) {
+	// Encrypt before the transaction, unlocking the store may prompt.
+	passwords := make(map[string]string)
+	for _, g := range data.Groups {
+		for _, u := range g.Students {
+			pw, err := credstore.Cluster().Encrypt(u.Password)
+			if err != nil {
+				return InsertedClassData{}, err
+			}
+			passwords[u.UserName] = pw
+		}
+	}
+
 	tx, conErr := conn.Begin()
 	if conErr != nil {
 		return InsertedClassData{}, fmt.Errorf("begin tx: %w", conErr)
@@ -455,7 +509,7 @@ func InsertClassIntoDB(conn *sql.DB, clusterID int, data schemas.Class) (Inserte
 		result.GroupIDs[g.Name] = int(groupID)
 
 		for _, u := range g.Students {
-			usrRes, err := stmtUsr.Exec(groupID, u.UserName, u.FullName, u.Password)
+			usrRes, err := stmtUsr.Exec(groupID, u.UserName, u.FullName, passwords[u.UserName])
 			if err != nil {
 				_ = tx.Rollback()
 				return InsertedClassData{}, fmt.Errorf("insert user %v failed: %w", u.FullName, err)
@@ -690,6 +744,9 @@ ORDER BY n.node_id, g.group_id, u.user_id;
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
+		if password, err = credstore.Cluster().Decrypt(password); err != nil {
+			return nil, err
+		}
 
 		if currentNodeURL != nodeURL {
 			if currentNodeGroups != nil {
@@ -743,6 +800,52 @@ ORDER BY n.node_id, g.group_id, u.user_id;
 	return results, nil
 }
 
+// RewritePasswords replaces every stored default password with the result
+// of fn, e.g. to encrypt them. It returns how many passwords changed.
+func RewritePasswords(conn *sql.DB, fn func(string) (string, error)) (int, error) {
+	type userPassword struct {
+		id       int
+		password string
+	}
+	users, err := QueryRows(conn, `SELECT user_id, default_password FROM users`, func(rows *sql.Rows) (userPassword, error) {
+		var u userPassword
+		err := rows.Scan(&u.id, &u.password)
+		return u, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query passwords: %w", err)
+	}
+
+	changed := make(map[int]string)
+	for _, u := range users {
+		pw, err := fn(u.password)
+		if err != nil {
+			return 0, err
+		}
+		if pw != u.password {
+			changed[u.id] = pw
+		}
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	for id, pw := range changed {
+		if _, err := tx.Exec(`UPDATE users SET default_password = ? WHERE user_id = ?`, pw, id); err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("failed to update password: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return len(changed), nil
+}
+
 func DeleteExerciseRecord(conn *sql.DB, projectUUID string) error {
 	if conn == nil {
 		return fmt.Errorf("database connection is nil")
@@ -70,10 +70,10 @@ type NodeGroupsForClass struct {
 }
 
 type ExerciseItem struct {
-	Name        string
-	ProjectUUID string
-	GroupName   string
-	State       string
+	Name        string `json:"name"`
+	ProjectUUID string `json:"project_id"`
+	GroupName   string `json:"group"`
+	State       string `json:"state"`
 }
 
 type NodeExercisesForClass struct {
@@ -2,7 +2,14 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
 )
 
 type globalOptionsKey string
@@ -10,11 +17,61 @@ type globalOptionsKey string
 const optsKey globalOptionsKey = "globalOptions"
 
 type GlobalOptions struct {
-	Server   string
-	Insecure bool
-	Raw      bool
-	NoColors bool
-	KeyFile  string
+	Server       string
+	Insecure     bool
+	Raw          bool
+	NoColors     bool
+	KeyFile      string
+	Retries      int
+	RetryMaxWait time.Duration
+	RetryPOST    bool
+	// RetriesSet makes Retries replace the default retry count, so
+	// options built without the flags keep retrying.
+	RetriesSet bool
+	// Output is the -o format of commands that print resources.
+	Output string
+	// List holds the --query, --filter, --sort-by and --limit flags of
+	// list commands.
+	List ListOptions
+	// As selects which of the users stored for Server acts, the first
+	// one if it is empty.
+	As string
+	// Cassette is set by --record/--replay and shared by every client of
+	// the command run.
+	Cassette *api.Cassette
+	// DryRun is set by --dry-run; mutating requests are collected in it
+	// instead of being sent.
+	DryRun *api.DryRun
+	// Trace is set by --trace or GNS3UTIL_TRACE and logs every request to
+	// stderr.
+	Trace *api.Tracer
+	// RootCAs and ClientCert come from --ca-file and --client-cert,
+	// CertPins from the keyfile.
+	RootCAs    *x509.CertPool
+	ClientCert *tls.Certificate
+	CertPins   api.CertPins
+	// Dial and Proxy come from --via and --proxy.
+	Dial  api.DialFunc
+	Proxy func(*http.Request) (*url.URL, error)
+	// CredentialHelper is the command from --credential-helper that
+	// provides passwords for logins.
+	CredentialHelper string
+	// Reauth logs in to server again when its token was rejected.
+	Reauth func(ctx context.Context, server, expired string) (string, error)
+}
+
+// ListOptions are applied client-side to the result of a list command
+// before it is printed.
+type ListOptions struct {
+	Query   string
+	Filters []string
+	SortBy  string
+	Limit   int
+}
+
+// Active reports whether any of the list options is set.
+func (l ListOptions) Active() bool {
+	return l.Query != "" || len(l.Filters) > 0 || l.SortBy != "" || l.Limit > 0
 }
 
 func GetGlobalOptionsFromContext(ctx context.Context) (GlobalOptions, error) {
@@ -28,3 +85,37 @@ func GetGlobalOptionsFromContext(ctx context.Context) (GlobalOptions, error) {
 func WithGlobalOptions(ctx context.Context, opts GlobalOptions) context.Context {
 	return context.WithValue(ctx, optsKey, opts)
 }
+
+// APISettings builds the client settings for opts.Server from the global
+// flags. extra is applied last and can override any of them.
+func APISettings(opts GlobalOptions, token string, extra ...api.SettingOption) api.Settings {
+	retry := api.DefaultRetryPolicy()
+	if opts.RetriesSet {
+		retry.MaxRetries = opts.Retries
+	}
+	if opts.RetryMaxWait > 0 {
+		retry.MaxWait = opts.RetryMaxWait
+	}
+	retry.RetryPOST = opts.RetryPOST
+
+	settingOpts := []api.SettingOption{
+		api.WithBaseURL(opts.Server),
+		api.WithVerify(!opts.Insecure),
+		api.WithToken(token),
+		api.WithRetry(retry),
+		api.WithCassette(opts.Cassette),
+		api.WithDryRun(opts.DryRun),
+		api.WithTrace(opts.Trace),
+		api.WithRootCAs(opts.RootCAs),
+		api.WithClientCert(opts.ClientCert),
+		api.WithPinnedCert(opts.CertPins.For(opts.Server)),
+		api.WithDialer(opts.Dial),
+		api.WithProxy(opts.Proxy),
+	}
+	if opts.Reauth != nil && token != "" {
+		settingOpts = append(settingOpts, api.WithReauth(func(ctx context.Context, expired string) (string, error) {
+			return opts.Reauth(ctx, opts.Server, expired)
+		}))
+	}
+	return api.NewSettings(append(settingOpts, extra...)...)
+}
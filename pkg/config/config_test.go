@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+)
+
+func TestAPISettingsRetries(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GlobalOptions
+		want int
+	}{
+		{"zero value keeps the default", GlobalOptions{}, api.DefaultRetries},
+		{"flag", GlobalOptions{Retries: 5, RetriesSet: true}, 5},
+		{"disabled", GlobalOptions{Retries: 0, RetriesSet: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := APISettings(tt.opts, "").Retry.MaxRetries; got != tt.want {
+				t.Errorf("MaxRetries = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
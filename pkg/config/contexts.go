@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+// ContextEnv selects the context for one shell instead of the current one.
+const ContextEnv = "GNS3UTIL_CONTEXT"
+
+// Context holds the defaults for the global flags that a named context
+// supplies, like a kubectl context.
+type Context struct {
+	Server   string `toml:"server,omitempty" json:"server,omitempty"`
+	Insecure bool   `toml:"insecure,omitempty" json:"insecure,omitempty"`
+	User     string `toml:"user,omitempty" json:"user,omitempty"`
+	KeyFile  string `toml:"key_file,omitempty" json:"key_file,omitempty"`
+	// CredentialHelper provides the passwords for logins to Server.
+	CredentialHelper string `toml:"credential_helper,omitempty" json:"credential_helper,omitempty"`
+	// Cluster is used by commands with a --cluster flag when neither
+	// --server nor --cluster is given.
+	Cluster string `toml:"cluster,omitempty" json:"cluster,omitempty"`
+}
+
+type Contexts struct {
+	Current  string             `toml:"current,omitempty"`
+	Contexts map[string]Context `toml:"contexts"`
+}
+
+func contextsPath() (string, error) {
+	dir, err := pathUtils.GetGNS3Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contexts.toml"), nil
+}
+
+// LoadContexts reads the contexts file. A missing file has no contexts.
+func LoadContexts() (Contexts, error) {
+	c := Contexts{Contexts: make(map[string]Context)}
+	path, err := contextsPath()
+	if err != nil {
+		return c, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := toml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if c.Contexts == nil {
+		c.Contexts = make(map[string]Context)
+	}
+	return c, nil
+}
+
+func WriteContexts(c Contexts) error {
+	path, err := contextsPath()
+	if err != nil {
+		return err
+	}
+	data, err := toml.Marshal(&c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Set adds or replaces the context name. A relative KeyFile is stored as an
+// absolute path, as the context is used from any directory.
+func (c Contexts) Set(name string, ctx Context) error {
+	if ctx.KeyFile != "" {
+		keyFile, err := filepath.Abs(ctx.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the keyfile path: %w", err)
+		}
+		ctx.KeyFile = keyFile
+	}
+	c.Contexts[name] = ctx
+	return nil
+}
+
+// Names returns the context names in order.
+func (c Contexts) Names() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveContext returns the context selected by override (the --context
+// flag), GNS3UTIL_CONTEXT or the current context, in that order. ok is false
+// if none is selected.
+func ActiveContext(override string) (string, Context, bool, error) {
+	contexts, err := LoadContexts()
+	if err != nil {
+		return "", Context{}, false, err
+	}
+	name, source := override, "--context"
+	if name == "" {
+		name, source = os.Getenv(ContextEnv), ContextEnv
+	}
+	if name == "" {
+		name, source = contexts.Current, "the current context"
+	}
+	if name == "" {
+		return "", Context{}, false, nil
+	}
+	c, ok := contexts.Contexts[name]
+	if !ok {
+		return "", Context{}, false, fmt.Errorf("context %q from %s does not exist, see context ls", name, source)
+	}
+	return name, c, true, nil
+}
+
+// ApplyContext sets the global flags of cmd that were not given on the
+// command line from the active context. It is safe to call more than once.
+func ApplyContext(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	override, _ := flags.GetString("context")
+	_, c, ok, err := ActiveContext(override)
+	if err != nil || !ok {
+		return err
+	}
+
+	serverGiven := flags.Changed("server")
+	cluster := flags.Lookup("cluster")
+	clusterGiven := cluster != nil && cluster.Changed
+	if c.Cluster != "" && cluster != nil && !serverGiven && !clusterGiven {
+		if err := flags.Set("cluster", c.Cluster); err != nil {
+			return err
+		}
+		clusterGiven = true
+	}
+	if c.Server != "" && !serverGiven && !clusterGiven {
+		if err := flags.Set("server", c.Server); err != nil {
+			return err
+		}
+	}
+	if c.User != "" && !flags.Changed("as") {
+		if err := flags.Set("as", c.User); err != nil {
+			return err
+		}
+	}
+	if c.Insecure && !flags.Changed("insecure") {
+		if err := flags.Set("insecure", "true"); err != nil {
+			return err
+		}
+	}
+	if c.CredentialHelper != "" && !flags.Changed("credential-helper") {
+		if err := flags.Set("credential-helper", c.CredentialHelper); err != nil {
+			return err
+		}
+	}
+	if c.KeyFile != "" && !flags.Changed("key-file") {
+		keyFile := c.KeyFile
+		// Paths written by hand stay relative to the contexts file instead
+		// of following the working directory.
+		if !filepath.IsAbs(keyFile) {
+			path, err := contextsPath()
+			if err != nil {
+				return err
+			}
+			keyFile = filepath.Join(filepath.Dir(path), keyFile)
+		}
+		if err := flags.Set("key-file", keyFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+func TestContextsSet(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	abs := filepath.Join(t.TempDir(), "gns3key")
+
+	tests := []struct {
+		keyFile, want string
+	}{
+		{"lab/gns3key", filepath.Join(dir, "lab", "gns3key")},
+		{"./gns3key", filepath.Join(dir, "gns3key")},
+		{abs, abs},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		c := Contexts{Contexts: make(map[string]Context)}
+		if err := c.Set("lab", Context{Server: "http://lab:3080", KeyFile: tt.keyFile}); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Contexts["lab"].KeyFile; got != tt.want {
+			t.Errorf("Set() with keyfile %q stored %q, want %q", tt.keyFile, got, tt.want)
+		}
+	}
+}
+
+// newContextCmd returns a command with the global flags ApplyContext sets,
+// parsed from args.
+func newContextCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	flags := cmd.Flags()
+	flags.String("context", "", "")
+	flags.String("server", "", "")
+	flags.String("cluster", "", "")
+	flags.String("as", "", "")
+	flags.Bool("insecure", false, "")
+	flags.String("credential-helper", "", "")
+	flags.String("key-file", "", "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestApplyContext(t *testing.T) {
+	homedir.DisableCache = true
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ContextEnv, "")
+	keyFile := filepath.Join(t.TempDir(), "gns3key")
+	if err := WriteContexts(Contexts{
+		Current: "lab",
+		Contexts: map[string]Context{
+			"lab":  {Server: "http://lab:3080", User: "teacher", Insecure: true, KeyFile: keyFile},
+			"exam": {Cluster: "exam-cluster", KeyFile: "keys/exam"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"current context", nil, "", map[string]string{
+			"server": "http://lab:3080", "as": "teacher", "insecure": "true", "key-file": keyFile,
+		}, false},
+		{"flags win", []string{"--server", "http://other:3080", "--key-file", "mine"}, "", map[string]string{
+			"server": "http://other:3080", "as": "teacher", "key-file": "mine",
+		}, false},
+		{"relative keyfile", []string{"--context", "exam"}, "", map[string]string{
+			"cluster": "exam-cluster", "server": "", "key-file": filepath.Join(home, ".gns3", "keys", "exam"),
+		}, false},
+		{"environment", nil, "exam", map[string]string{"cluster": "exam-cluster"}, false},
+		{"unknown context", []string{"--context", "nope"}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ContextEnv, tt.env)
+			cmd := newContextCmd(t, tt.args...)
+			err := ApplyContext(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyContext() error = %v", err)
+			}
+			for flag, want := range tt.want {
+				if got := cmd.Flags().Lookup(flag).Value.String(); got != want {
+					t.Errorf("--%s = %q, want %q", flag, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package credstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+)
+
+// AgentSocketEnv overrides where auth agent listens.
+const AgentSocketEnv = "GNS3UTIL_AGENT_SOCK"
+
+const agentTimeout = 2 * time.Second
+
+type agentRequest struct {
+	Op   string `json:"op"`
+	Salt []byte `json:"salt,omitempty"`
+	Key  []byte `json:"key,omitempty"`
+}
+
+type agentResponse struct {
+	Key   []byte `json:"key,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type cachedEntry struct {
+	key      []byte
+	lastUsed time.Time
+}
+
+// SocketPath returns the socket of auth agent, AgentSocketEnv or
+// ~/.gns3/agent.sock.
+func SocketPath() (string, error) {
+	if p := os.Getenv(AgentSocketEnv); p != "" {
+		return p, nil
+	}
+	// One agent serves every store, their keys are told apart by salt.
+	dir, err := pathUtils.GetGNS3Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "agent.sock"), nil
+}
+
+// ServeAgent keeps unlocked store keys in memory so other gns3util processes
+// do not ask for the passphrase. A key is dropped ttl after its last use; a
+// ttl of 0 keeps it until the agent stops.
+func ServeAgent(ctx context.Context, ttl time.Duration) error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	if conn, err := net.DialTimeout("unix", path, agentTimeout); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("an agent is already listening on %s", path)
+	}
+	_ = os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// The socket is created in a directory only the current user can enter
+	// and moved into place once it is private, so nobody can connect in
+	// between.
+	tmp, err := os.MkdirTemp(filepath.Dir(path), ".agent-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	tmpPath := filepath.Join(tmp, "agent.sock")
+	ln, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0o600); err != nil {
+		_ = ln.Close()
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(path) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	var mu sync.Mutex
+	keys := make(map[string]*cachedEntry)
+	if ttl > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					mu.Lock()
+					for salt, e := range keys {
+						if now.Sub(e.lastUsed) > ttl {
+							delete(keys, salt)
+						}
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	handle := func(req agentRequest) agentResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		salt := base64.StdEncoding.EncodeToString(req.Salt)
+		switch req.Op {
+		case "get":
+			e, ok := keys[salt]
+			if !ok {
+				return agentResponse{Error: "not cached"}
+			}
+			e.lastUsed = time.Now()
+			return agentResponse{Key: e.key}
+		case "put":
+			keys[salt] = &cachedEntry{key: req.Key, lastUsed: time.Now()}
+		case "forget":
+			delete(keys, salt)
+		case "stop":
+			cancel()
+		default:
+			return agentResponse{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+		}
+		return agentResponse{}
+	}
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			_ = conn.SetDeadline(time.Now().Add(agentTimeout))
+			var req agentRequest
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+				return
+			}
+			_ = json.NewEncoder(conn).Encode(handle(req))
+		}()
+	}
+}
+
+// StopAgent asks a running agent to forget its keys and exit.
+func StopAgent() error {
+	_, err := agentCall(agentRequest{Op: "stop"})
+	return err
+}
+
+func agentCall(req agentRequest) (agentResponse, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return agentResponse{}, err
+	}
+	conn, err := net.DialTimeout("unix", path, agentTimeout)
+	if err != nil {
+		return agentResponse{}, fmt.Errorf("no agent is running on %s", path)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(agentTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return agentResponse{}, err
+	}
+	var resp agentResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return agentResponse{}, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+func agentGet(salt []byte) ([]byte, bool) {
+	resp, err := agentCall(agentRequest{Op: "get", Salt: salt})
+	if err != nil || len(resp.Key) != keyLen {
+		return nil, false
+	}
+	return resp.Key, true
+}
+
+// agentPut and agentForget are best effort, the agent is optional.
+func agentPut(salt, key []byte) {
+	_, _ = agentCall(agentRequest{Op: "put", Salt: salt, Key: key})
+}
+
+func agentForget(salt []byte) {
+	_, _ = agentCall(agentRequest{Op: "forget", Salt: salt})
+}
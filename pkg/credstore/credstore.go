@@ -0,0 +1,387 @@
+package credstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The credential store encrypts single values, the tokens in the keyfile and
+// the default passwords in the cluster database, with a key derived from a
+// passphrase. Encrypted values carry valuePrefix so plain values written
+// before the store was set up stay readable.
+const valuePrefix = "enc:v1:"
+
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+
+	checkValue = "gns3util"
+)
+
+// PassphraseEnv unlocks the store without a prompt, e.g. in scripts.
+const PassphraseEnv = "GNS3UTIL_PASSPHRASE"
+
+var ErrWrongPassphrase = errors.New("wrong passphrase for the credential store")
+
+type params struct {
+	KDF  string `json:"kdf"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+	// Check is a known value sealed with the key to detect a wrong passphrase.
+	Check string `json:"check"`
+}
+
+// Store is the credential store in one directory. Tokens in a keyfile are
+// encrypted with the store next to it, see UseKeyFile, and the passwords in
+// the cluster database always with the one in ~/.gns3 because the database
+// does not move with --key-file.
+type Store struct {
+	dir string
+}
+
+var (
+	mu sync.Mutex
+	// cachedKeys are the unlocked keys by store directory.
+	cachedKeys = make(map[string][]byte)
+)
+
+// keyFileDir is the directory of the keyfile given with --key-file.
+var keyFileDir string
+
+// UseKeyFile keeps the store for the keyfile tokens next to keyFile, a file
+// or a directory as accepted by --key-file, instead of in ~/.gns3. An empty
+// keyFile restores the default. Call it before the store is used.
+func UseKeyFile(keyFile string) error {
+	dir := ""
+	if keyFile != "" {
+		path, err := pathUtils.ExpandPath(keyFile)
+		if err != nil {
+			return err
+		}
+		dir = filepath.Dir(path)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dir = path
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	keyFileDir = dir
+	return nil
+}
+
+// Keys returns the store for the tokens and passwords in the keyfile.
+func Keys() Store {
+	mu.Lock()
+	defer mu.Unlock()
+	return Store{dir: keyFileDir}
+}
+
+// Cluster returns the store for the student passwords in the cluster
+// database, ~/.gns3 whatever keyfile is used.
+func Cluster() Store {
+	return Store{}
+}
+
+func (s Store) location() (string, error) {
+	if s.dir != "" {
+		return s.dir, nil
+	}
+	return pathUtils.GetGNS3Dir()
+}
+
+func (s Store) paramsPath() (string, error) {
+	dir, err := s.location()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credstore.json"), nil
+}
+
+func (s Store) loadParams() (params, error) {
+	var p params
+	path, err := s.paramsPath()
+	if err != nil {
+		return p, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return p, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if p.KDF != "scrypt" {
+		return p, fmt.Errorf("unsupported key derivation %q in %s", p.KDF, path)
+	}
+	return p, nil
+}
+
+// Enabled reports whether the store has been set up with auth migrate-store,
+// so new secrets are written encrypted.
+func (s Store) Enabled() bool {
+	path, err := s.paramsPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Same reports whether s and other are kept in the same directory.
+func (s Store) Same(other Store) bool {
+	a, errA := s.location()
+	b, errB := other.location()
+	return errA == nil && errB == nil && filepath.Clean(a) == filepath.Clean(b)
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, valuePrefix)
+}
+
+// Encrypt returns value encrypted if the store is enabled and unchanged
+// otherwise. It asks for the passphrase if the store is locked.
+func (s Store) Encrypt(value string) (string, error) {
+	if !s.Enabled() || IsEncrypted(value) {
+		return value, nil
+	}
+	key, err := s.unlock()
+	if err != nil {
+		return "", err
+	}
+	return seal(key, value)
+}
+
+// Decrypt returns the plain text of an encrypted value. Plain values are
+// returned as they are.
+func (s Store) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	key, err := s.unlock()
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(key, value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt a stored credential: %w", err)
+	}
+	return plain, nil
+}
+
+// Init sets the store up with a new passphrase.
+func (s Store) Init(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("the passphrase must not be empty")
+	}
+	p := params{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 16)}
+	if _, err := rand.Read(p.Salt); err != nil {
+		return err
+	}
+	key, err := deriveKey(p, passphrase)
+	if err != nil {
+		return err
+	}
+	if p.Check, err = seal(key, checkValue); err != nil {
+		return err
+	}
+
+	dir, err := s.location()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path, _ := s.paramsPath()
+	b, _ := json.MarshalIndent(p, "", "  ")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	mu.Lock()
+	cachedKeys[dir] = key
+	mu.Unlock()
+	agentPut(p.Salt, key)
+	return nil
+}
+
+// Remove turns the store off again. Values still encrypted with it cannot be
+// read afterwards.
+func (s Store) Remove() error {
+	p, err := s.loadParams()
+	if err != nil {
+		return err
+	}
+	path, _ := s.paramsPath()
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	dir, _ := s.location()
+	mu.Lock()
+	delete(cachedKeys, dir)
+	mu.Unlock()
+	agentForget(p.Salt)
+	return nil
+}
+
+// Enabled reports whether the keyfile store is set up.
+func Enabled() bool {
+	return Keys().Enabled()
+}
+
+// Encrypt encrypts value with the keyfile store.
+func Encrypt(value string) (string, error) {
+	return Keys().Encrypt(value)
+}
+
+// Decrypt decrypts value with the keyfile store.
+func Decrypt(value string) (string, error) {
+	return Keys().Decrypt(value)
+}
+
+// Init sets the keyfile store up.
+func Init(passphrase string) error {
+	return Keys().Init(passphrase)
+}
+
+// Remove turns the keyfile store off.
+func Remove() error {
+	return Keys().Remove()
+}
+
+// ReadPassphrase prompts on the terminal without echoing the input.
+func ReadPassphrase(prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("the credential store is locked and there is no terminal to ask for the passphrase: set %s or run auth agent", PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the passphrase: %w", err)
+	}
+	return string(b), nil
+}
+
+// unlock returns the key from this process, the agent, PassphraseEnv or a
+// prompt, in that order.
+func (s Store) unlock() ([]byte, error) {
+	dir, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if key := cachedKeys[dir]; key != nil {
+		return key, nil
+	}
+
+	p, err := s.loadParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the credential store: %w", err)
+	}
+	if key, ok := agentGet(p.Salt); ok && checkKey(p, key) {
+		cachedKeys[dir] = key
+		return key, nil
+	}
+
+	if pass := os.Getenv(PassphraseEnv); pass != "" {
+		key, err := deriveKey(p, pass)
+		if err != nil {
+			return nil, err
+		}
+		if !checkKey(p, key) {
+			return nil, fmt.Errorf("%s: %w", PassphraseEnv, ErrWrongPassphrase)
+		}
+		cachedKeys[dir] = key
+		agentPut(p.Salt, key)
+		return key, nil
+	}
+
+	for range 3 {
+		pass, err := ReadPassphrase(fmt.Sprintf("Passphrase for the gns3util credential store in %s: ", dir))
+		if err != nil {
+			return nil, err
+		}
+		key, err := deriveKey(p, pass)
+		if err != nil {
+			return nil, err
+		}
+		if checkKey(p, key) {
+			cachedKeys[dir] = key
+			agentPut(p.Salt, key)
+			return key, nil
+		}
+		fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+	}
+	return nil, ErrWrongPassphrase
+}
+
+func deriveKey(p params, passphrase string) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), p.Salt, p.N, p.R, p.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the store key: %w", err)
+	}
+	return key, nil
+}
+
+func checkKey(p params, key []byte) bool {
+	v, err := open(key, p.Check)
+	return err == nil && subtle.ConstantTimeCompare([]byte(v), []byte(checkValue)) == 1
+}
+
+func seal(key []byte, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return valuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func open(key []byte, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, valuePrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ct := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,171 @@
+package credstore
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// useStore points the store at a scratch keyfile directory and keeps the
+// agent of the user out of the test.
+func useStore(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv(AgentSocketEnv, filepath.Join(dir, "agent.sock"))
+	t.Setenv(PassphraseEnv, "")
+	if err := UseKeyFile(filepath.Join(dir, "gns3key")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = UseKeyFile("") })
+	return dir
+}
+
+func lock() {
+	mu.Lock()
+	clear(cachedKeys)
+	mu.Unlock()
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	dir := useStore(t)
+
+	if Enabled() {
+		t.Fatal("Enabled() before Init")
+	}
+	if got, err := Encrypt("s3cret"); err != nil || got != "s3cret" {
+		t.Errorf("Encrypt() without a store = %q, %v, want it unchanged", got, err)
+	}
+
+	if err := Init("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "credstore.json")); err != nil {
+		t.Errorf("store not kept next to the keyfile: %v", err)
+	}
+
+	tests := []string{"s3cret", "", "pässwörd with spaces"}
+	for _, plain := range tests {
+		enc, err := Encrypt(plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(enc) || enc == plain {
+			t.Errorf("Encrypt(%q) = %q, not encrypted", plain, enc)
+		}
+		if again, _ := Encrypt(enc); again != enc {
+			t.Errorf("Encrypt() encrypted an encrypted value again")
+		}
+		if got, err := Decrypt(enc); err != nil || got != plain {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q, %v", plain, got, err)
+		}
+	}
+	if got, err := Decrypt("plain"); err != nil || got != "plain" {
+		t.Errorf("Decrypt(plain) = %q, %v", got, err)
+	}
+
+	enc, _ := Encrypt("s3cret")
+	if _, err := Decrypt(enc[:len(enc)-4] + "AAAA"); err == nil {
+		t.Error("Decrypt() accepted a tampered value")
+	}
+
+	lock()
+	t.Setenv(PassphraseEnv, "passphrase")
+	if got, err := Decrypt(enc); err != nil || got != "s3cret" {
+		t.Errorf("Decrypt() with %s = %q, %v", PassphraseEnv, got, err)
+	}
+	lock()
+	t.Setenv(PassphraseEnv, "wrong")
+	if _, err := Decrypt(enc); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Decrypt() with a wrong passphrase = %v, want %v", err, ErrWrongPassphrase)
+	}
+
+	if err := Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if Enabled() {
+		t.Error("Enabled() after Remove")
+	}
+}
+
+func TestUseKeyFile(t *testing.T) {
+	homedir.DisableCache = true
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	t.Cleanup(func() { _ = UseKeyFile("") })
+	tests := []struct {
+		keyFile string
+		want    string
+	}{
+		{filepath.Join(dir, "gns3key"), dir},
+		{dir, dir},
+		{"", filepath.Join(home, ".gns3")},
+	}
+	for _, tt := range tests {
+		if err := UseKeyFile(tt.keyFile); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := Keys().location(); got != tt.want {
+			t.Errorf("keyfile store with %q = %s, want %s", tt.keyFile, got, tt.want)
+		}
+		// The cluster database stays in ~/.gns3, so does its store.
+		if got, _ := Cluster().location(); got != filepath.Join(home, ".gns3") {
+			t.Errorf("cluster store with %q = %s, want ~/.gns3", tt.keyFile, got)
+		}
+	}
+}
+
+func TestAgent(t *testing.T) {
+	dir := useStore(t)
+	path := filepath.Join(dir, "agent.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- ServeAgent(ctx, 0) }()
+	for range 100 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+
+	salt, key := []byte("salt"), make([]byte, keyLen)
+	key[0] = 1
+	if _, ok := agentGet(salt); ok {
+		t.Error("agent returned a key before it got one")
+	}
+	agentPut(salt, key)
+	if got, ok := agentGet(salt); !ok || got[0] != 1 {
+		t.Errorf("agentGet() = %v, %v", got, ok)
+	}
+	agentForget(salt)
+	if _, ok := agentGet(salt); ok {
+		t.Error("agent returned a forgotten key")
+	}
+
+	if err := StopAgent(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeAgent() = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left after stop: %v", err)
+	}
+}
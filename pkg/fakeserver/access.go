@@ -0,0 +1,684 @@
+package fakeserver
+
+import (
+	"net/http"
+	"slices"
+)
+
+func (s *Server) accessRoutes() {
+	s.handle("GET /access/users", s.listUsers)
+	s.handle("POST /access/users", s.createUser)
+	s.handle("GET /access/users/me", s.getMe)
+	s.handle("PUT /access/users/me", s.updateMe)
+	s.handle("GET /access/users/{user_id}", s.getUser)
+	s.handle("PUT /access/users/{user_id}", s.updateUser)
+	s.handle("DELETE /access/users/{user_id}", s.deleteUser)
+	s.handle("GET /access/users/{user_id}/groups", s.userGroups)
+
+	s.handle("GET /access/groups", s.listGroups)
+	s.handle("POST /access/groups", s.createGroup)
+	s.handle("GET /access/groups/{group_id}", s.getGroup)
+	s.handle("PUT /access/groups/{group_id}", s.updateGroup)
+	s.handle("DELETE /access/groups/{group_id}", s.deleteGroup)
+	s.handle("GET /access/groups/{group_id}/members", s.groupMembers)
+	s.handle("PUT /access/groups/{group_id}/members/{user_id}", s.addGroupMember)
+	s.handle("DELETE /access/groups/{group_id}/members/{user_id}", s.removeGroupMember)
+
+	s.handle("GET /access/roles", s.listRoles)
+	s.handle("POST /access/roles", s.createRole)
+	s.handle("GET /access/roles/{role_id}", s.getRole)
+	s.handle("PUT /access/roles/{role_id}", s.updateRole)
+	s.handle("DELETE /access/roles/{role_id}", s.deleteRole)
+	s.handle("GET /access/roles/{role_id}/privileges", s.rolePrivileges)
+	s.handle("PUT /access/roles/{role_id}/privileges/{privilege_id}", s.addRolePrivilege)
+	s.handle("DELETE /access/roles/{role_id}/privileges/{privilege_id}", s.removeRolePrivilege)
+	s.handle("GET /access/privileges", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.privileges.list())
+	})
+
+	s.handle("GET /access/acl", s.listACEs)
+	s.handle("POST /access/acl", s.createACE)
+	s.handle("GET /access/acl/endpoints", s.aclEndpoints)
+	s.handle("GET /access/acl/{ace_id}", s.getACE)
+	s.handle("PUT /access/acl/{ace_id}", s.updateACE)
+	s.handle("DELETE /access/acl/{ace_id}", s.deleteACE)
+
+	s.handle("GET /pools", s.listPools)
+	s.handle("POST /pools", s.createPool)
+	s.handle("GET /pools/{pool_id}", s.getPool)
+	s.handle("PUT /pools/{pool_id}", s.updatePool)
+	s.handle("DELETE /pools/{pool_id}", s.deletePool)
+	s.handle("GET /pools/{pool_id}/resources", s.poolResourceList)
+	s.handle("PUT /pools/{pool_id}/resources/{resource_id}", s.addPoolResource)
+	s.handle("DELETE /pools/{pool_id}/resources/{resource_id}", s.removePoolResource)
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	username, _ := body["username"].(string)
+	password, _ := body["password"].(string)
+	s.issueToken(w, username, password)
+}
+
+// login is the OAuth2 form variant of authenticate.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		validationError(w, []string{"body"}, err.Error(), "value_error")
+		return
+	}
+	s.issueToken(w, r.PostForm.Get("username"), r.PostForm.Get("password"))
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, username, password string) {
+	user, ok := s.users.find("username", username)
+	if !ok || s.passwords[user["user_id"].(string)] != password || user["is_active"] != true {
+		writeError(w, http.StatusUnauthorized, "Authentication was unsuccessful")
+		return
+	}
+	user["last_login"] = timestamp()
+	writeJSON(w, http.StatusOK, object{
+		"access_token": s.newToken(user["user_id"].(string)),
+		"token_type":   "bearer",
+	})
+}
+
+var userFields = []string{"username", "email", "full_name", "is_active"}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.users.list())
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	username, ok := requireString(w, body, "username")
+	if !ok {
+		return
+	}
+	password, ok := requireString(w, body, "password")
+	if !ok {
+		return
+	}
+	if _, exists := s.users.find("username", username); exists {
+		writeError(w, http.StatusBadRequest, "Username '%s' is already registered", username)
+		return
+	}
+	now := timestamp()
+	user := object{"is_active": true, "is_superadmin": false, "email": nil, "full_name": nil, "created_at": now, "updated_at": now}
+	merge(user, body, userFields...)
+	created := s.users.add(user)
+	s.passwords[created["user_id"].(string)] = password
+	s.publish("", "user.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) lookupUser(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "user_id")
+	if !ok {
+		return nil, false
+	}
+	user, ok := s.users.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "User '%s' not found", id)
+	}
+	return user, ok
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	if user, ok := s.lookupUser(w, r); ok {
+		writeJSON(w, http.StatusOK, user)
+	}
+}
+
+func (s *Server) applyUserUpdate(w http.ResponseWriter, r *http.Request, user object) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["username"].(string); name != "" && name != user["username"] {
+		if _, exists := s.users.find("username", name); exists {
+			writeError(w, http.StatusBadRequest, "Username '%s' is already registered", name)
+			return
+		}
+	}
+	merge(user, body, userFields...)
+	if pw, _ := body["password"].(string); pw != "" {
+		s.passwords[user["user_id"].(string)] = pw
+	}
+	user["updated_at"] = timestamp()
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	if user, ok := s.lookupUser(w, r); ok {
+		s.applyUserUpdate(w, r, user)
+	}
+}
+
+func (s *Server) getMe(w http.ResponseWriter, r *http.Request) {
+	user, _ := s.currentUser(r)
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) updateMe(w http.ResponseWriter, r *http.Request) {
+	user, _ := s.currentUser(r)
+	s.applyUserUpdate(w, r, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+	if user["is_superadmin"] == true {
+		writeError(w, http.StatusForbidden, "The super admin cannot be deleted")
+		return
+	}
+	id := user["user_id"].(string)
+	s.users.remove(id)
+	delete(s.passwords, id)
+	for gid, members := range s.members {
+		s.members[gid] = slices.DeleteFunc(members, func(m string) bool { return m == id })
+	}
+	for token, uid := range s.tokens {
+		if uid == id {
+			delete(s.tokens, token)
+		}
+	}
+	s.acl.removeWhere(func(ace object) bool { return ace["user_id"] == id })
+	writeNoContent(w)
+}
+
+func (s *Server) userGroups(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+	id := user["user_id"].(string)
+	writeJSON(w, http.StatusOK, s.groups.filter(func(g object) bool {
+		return slices.Contains(s.members[g["user_group_id"].(string)], id)
+	}))
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.groups.list())
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	if _, exists := s.groups.find("name", name); exists {
+		writeError(w, http.StatusBadRequest, "User group '%s' already exists", name)
+		return
+	}
+	now := timestamp()
+	created := s.groups.add(object{"name": name, "is_builtin": false, "created_at": now, "updated_at": now})
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) lookupGroup(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "group_id")
+	if !ok {
+		return nil, false
+	}
+	group, ok := s.groups.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "User group '%s' not found", id)
+	}
+	return group, ok
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	if group, ok := s.lookupGroup(w, r); ok {
+		writeJSON(w, http.StatusOK, group)
+	}
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookupGroup(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if group["is_builtin"] == true {
+		writeError(w, http.StatusForbidden, "Built-in user group '%s' cannot be updated", group["name"])
+		return
+	}
+	merge(group, body, "name")
+	group["updated_at"] = timestamp()
+	writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookupGroup(w, r)
+	if !ok {
+		return
+	}
+	if group["is_builtin"] == true {
+		writeError(w, http.StatusForbidden, "Built-in user group '%s' cannot be deleted", group["name"])
+		return
+	}
+	id := group["user_group_id"].(string)
+	s.groups.remove(id)
+	delete(s.members, id)
+	s.acl.removeWhere(func(ace object) bool { return ace["group_id"] == id })
+	writeNoContent(w)
+}
+
+func (s *Server) groupMembers(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookupGroup(w, r)
+	if !ok {
+		return
+	}
+	members := s.members[group["user_group_id"].(string)]
+	writeJSON(w, http.StatusOK, s.users.filter(func(u object) bool {
+		return slices.Contains(members, u["user_id"].(string))
+	}))
+}
+
+func (s *Server) addGroupMember(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookupGroup(w, r)
+	if !ok {
+		return
+	}
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+	gid, uid := group["user_group_id"].(string), user["user_id"].(string)
+	if !slices.Contains(s.members[gid], uid) {
+		s.members[gid] = append(s.members[gid], uid)
+	}
+	writeNoContent(w)
+}
+
+func (s *Server) removeGroupMember(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookupGroup(w, r)
+	if !ok {
+		return
+	}
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+	gid, uid := group["user_group_id"].(string), user["user_id"].(string)
+	s.members[gid] = slices.DeleteFunc(s.members[gid], func(m string) bool { return m == uid })
+	writeNoContent(w)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.roles.list())
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	if _, exists := s.roles.find("name", name); exists {
+		writeError(w, http.StatusBadRequest, "Role '%s' already exists", name)
+		return
+	}
+	now := timestamp()
+	role := object{"description": nil, "is_builtin": false, "created_at": now, "updated_at": now}
+	merge(role, body, "name", "description")
+	writeJSON(w, http.StatusCreated, s.roles.add(role))
+}
+
+func (s *Server) lookupRole(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "role_id")
+	if !ok {
+		return nil, false
+	}
+	role, ok := s.roles.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Role '%s' not found", id)
+	}
+	return role, ok
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	if role, ok := s.lookupRole(w, r); ok {
+		writeJSON(w, http.StatusOK, role)
+	}
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRole(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if role["is_builtin"] == true {
+		writeError(w, http.StatusForbidden, "Built-in role '%s' cannot be updated", role["name"])
+		return
+	}
+	merge(role, body, "name", "description")
+	role["updated_at"] = timestamp()
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRole(w, r)
+	if !ok {
+		return
+	}
+	if role["is_builtin"] == true {
+		writeError(w, http.StatusForbidden, "Built-in role '%s' cannot be deleted", role["name"])
+		return
+	}
+	id := role["role_id"].(string)
+	s.roles.remove(id)
+	delete(s.rolePrivs, id)
+	s.acl.removeWhere(func(ace object) bool { return ace["role_id"] == id })
+	writeNoContent(w)
+}
+
+func (s *Server) rolePrivileges(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRole(w, r)
+	if !ok {
+		return
+	}
+	privs := s.rolePrivs[role["role_id"].(string)]
+	writeJSON(w, http.StatusOK, s.privileges.filter(func(p object) bool {
+		return slices.Contains(privs, p["privilege_id"].(string))
+	}))
+}
+
+func (s *Server) lookupPrivilege(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, ok := pathID(w, r, "privilege_id")
+	if !ok {
+		return "", false
+	}
+	if _, ok := s.privileges.get(id); !ok {
+		writeError(w, http.StatusNotFound, "Privilege '%s' not found", id)
+		return "", false
+	}
+	return id, true
+}
+
+func (s *Server) addRolePrivilege(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRole(w, r)
+	if !ok {
+		return
+	}
+	pid, ok := s.lookupPrivilege(w, r)
+	if !ok {
+		return
+	}
+	rid := role["role_id"].(string)
+	if !slices.Contains(s.rolePrivs[rid], pid) {
+		s.rolePrivs[rid] = append(s.rolePrivs[rid], pid)
+	}
+	writeNoContent(w)
+}
+
+func (s *Server) removeRolePrivilege(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRole(w, r)
+	if !ok {
+		return
+	}
+	pid, ok := s.lookupPrivilege(w, r)
+	if !ok {
+		return
+	}
+	rid := role["role_id"].(string)
+	s.rolePrivs[rid] = slices.DeleteFunc(s.rolePrivs[rid], func(p string) bool { return p == pid })
+	writeNoContent(w)
+}
+
+var aceFields = []string{"ace_type", "path", "propagate", "allowed", "user_id", "group_id", "role_id"}
+
+func (s *Server) listACEs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.acl.list())
+}
+
+// checkACE validates the references of an ACE the way the controller does:
+// the role must exist and exactly one of user_id/group_id must match ace_type.
+func (s *Server) checkACE(w http.ResponseWriter, ace object) bool {
+	if _, ok := s.roles.get(stringField(ace, "role_id")); !ok {
+		writeError(w, http.StatusNotFound, "Role '%v' not found", ace["role_id"])
+		return false
+	}
+	switch ace["ace_type"] {
+	case "user":
+		if _, ok := s.users.get(stringField(ace, "user_id")); !ok {
+			writeError(w, http.StatusNotFound, "User '%v' not found", ace["user_id"])
+			return false
+		}
+	case "group":
+		if _, ok := s.groups.get(stringField(ace, "group_id")); !ok {
+			writeError(w, http.StatusNotFound, "User group '%v' not found", ace["group_id"])
+			return false
+		}
+	default:
+		validationError(w, []string{"body", "ace_type"}, "Input should be 'user' or 'group'", "enum")
+		return false
+	}
+	return true
+}
+
+func (s *Server) createACE(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := requireString(w, body, "path"); !ok {
+		return
+	}
+	now := timestamp()
+	ace := object{"propagate": true, "allowed": true, "user_id": nil, "group_id": nil, "created_at": now, "updated_at": now}
+	merge(ace, body, aceFields...)
+	if !s.checkACE(w, ace) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.acl.add(ace))
+}
+
+func (s *Server) lookupACE(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "ace_id")
+	if !ok {
+		return nil, false
+	}
+	ace, ok := s.acl.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "ACE '%s' not found", id)
+	}
+	return ace, ok
+}
+
+func (s *Server) getACE(w http.ResponseWriter, r *http.Request) {
+	if ace, ok := s.lookupACE(w, r); ok {
+		writeJSON(w, http.StatusOK, ace)
+	}
+}
+
+func (s *Server) updateACE(w http.ResponseWriter, r *http.Request) {
+	ace, ok := s.lookupACE(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	updated := object{}
+	merge(updated, ace, aceFields...)
+	merge(updated, body, aceFields...)
+	if !s.checkACE(w, updated) {
+		return
+	}
+	merge(ace, updated, aceFields...)
+	ace["updated_at"] = timestamp()
+	writeJSON(w, http.StatusOK, ace)
+}
+
+func (s *Server) deleteACE(w http.ResponseWriter, r *http.Request) {
+	ace, ok := s.lookupACE(w, r)
+	if !ok {
+		return
+	}
+	s.acl.remove(ace["ace_id"].(string))
+	writeNoContent(w)
+}
+
+func (s *Server) aclEndpoints(w http.ResponseWriter, r *http.Request) {
+	out := []object{
+		{"endpoint": "/", "name": "All resources", "endpoint_type": "root"},
+		{"endpoint": "/projects", "name": "All projects", "endpoint_type": "project"},
+		{"endpoint": "/pools", "name": "All pools", "endpoint_type": "pool"},
+		{"endpoint": "/templates", "name": "All templates", "endpoint_type": "template"},
+	}
+	for _, p := range s.projects.list() {
+		out = append(out, object{"endpoint": "/projects/" + p["project_id"].(string), "name": p["name"], "endpoint_type": "project"})
+	}
+	for _, p := range s.pools.list() {
+		out = append(out, object{"endpoint": "/pools/" + p["resource_pool_id"].(string), "name": p["name"], "endpoint_type": "pool"})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) listPools(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.pools.list())
+}
+
+func (s *Server) createPool(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	if _, exists := s.pools.find("name", name); exists {
+		writeError(w, http.StatusBadRequest, "Resource pool '%s' already exists", name)
+		return
+	}
+	now := timestamp()
+	writeJSON(w, http.StatusCreated, s.pools.add(object{"name": name, "created_at": now, "updated_at": now}))
+}
+
+func (s *Server) lookupPool(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "pool_id")
+	if !ok {
+		return nil, false
+	}
+	pool, ok := s.pools.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource pool '%s' not found", id)
+	}
+	return pool, ok
+}
+
+func (s *Server) getPool(w http.ResponseWriter, r *http.Request) {
+	if pool, ok := s.lookupPool(w, r); ok {
+		writeJSON(w, http.StatusOK, pool)
+	}
+}
+
+func (s *Server) updatePool(w http.ResponseWriter, r *http.Request) {
+	pool, ok := s.lookupPool(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	merge(pool, body, "name")
+	pool["updated_at"] = timestamp()
+	writeJSON(w, http.StatusOK, pool)
+}
+
+func (s *Server) deletePool(w http.ResponseWriter, r *http.Request) {
+	pool, ok := s.lookupPool(w, r)
+	if !ok {
+		return
+	}
+	id := pool["resource_pool_id"].(string)
+	s.pools.remove(id)
+	delete(s.poolResources, id)
+	s.acl.removeWhere(func(ace object) bool { return ace["path"] == "/pools/"+id })
+	writeNoContent(w)
+}
+
+func (s *Server) poolResourceList(w http.ResponseWriter, r *http.Request) {
+	pool, ok := s.lookupPool(w, r)
+	if !ok {
+		return
+	}
+	out := []object{}
+	for _, id := range s.poolResources[pool["resource_pool_id"].(string)] {
+		if p, ok := s.projects.get(id); ok {
+			out = append(out, object{"resource_id": id, "resource_type": "project", "name": p["name"]})
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) addPoolResource(w http.ResponseWriter, r *http.Request) {
+	pool, ok := s.lookupPool(w, r)
+	if !ok {
+		return
+	}
+	rid, ok := pathID(w, r, "resource_id")
+	if !ok {
+		return
+	}
+	if _, ok := s.projects.get(rid); !ok {
+		writeError(w, http.StatusNotFound, "Resource '%s' not found", rid)
+		return
+	}
+	pid := pool["resource_pool_id"].(string)
+	if slices.Contains(s.poolResources[pid], rid) {
+		writeError(w, http.StatusBadRequest, "Resource '%s' is already in resource pool '%s'", rid, pool["name"])
+		return
+	}
+	s.poolResources[pid] = append(s.poolResources[pid], rid)
+	writeNoContent(w)
+}
+
+func (s *Server) removePoolResource(w http.ResponseWriter, r *http.Request) {
+	pool, ok := s.lookupPool(w, r)
+	if !ok {
+		return
+	}
+	rid, ok := pathID(w, r, "resource_id")
+	if !ok {
+		return
+	}
+	pid := pool["resource_pool_id"].(string)
+	if !slices.Contains(s.poolResources[pid], rid) {
+		writeError(w, http.StatusNotFound, "Resource '%s' not found in resource pool '%s'", rid, pool["name"])
+		return
+	}
+	s.poolResources[pid] = slices.DeleteFunc(s.poolResources[pid], func(id string) bool { return id == rid })
+	writeNoContent(w)
+}
+
+func stringField(obj object, field string) string {
+	v, _ := obj[field].(string)
+	return v
+}
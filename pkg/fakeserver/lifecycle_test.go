@@ -0,0 +1,214 @@
+package fakeserver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/class"
+)
+
+// TestMain points the home directory, and with it the keyfile default and
+// the cluster database, at a scratch directory.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "gns3util-fakeserver")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	_ = os.Setenv("HOME", home)
+	code := m.Run()
+	_ = os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// start serves a fresh fake controller and logs in to it as admin.
+func start(t *testing.T) (config.GlobalOptions, *sdk.Client) {
+	t.Helper()
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	t.Cleanup(ts.Close)
+
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(context.Background(), cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	c, err := sdk.NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, c
+}
+
+func groupNames(t *testing.T, c *sdk.Client) []string {
+	t.Helper()
+	groups, err := c.Groups().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func usernames(t *testing.T, c *sdk.Client) []string {
+	t.Helper()
+	users, err := c.Users().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	return names
+}
+
+func TestClassLifecycle(t *testing.T) {
+	ctx := context.Background()
+	cfg, c := start(t)
+
+	u, _ := url.Parse(cfg.Server)
+	port, _ := strconv.Atoi(u.Port())
+	conn, err := db.InitIfNeeded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	clusters, err := db.CreateClusters(conn, []db.ClusterName{{Name: u.Hostname() + "_single_node_cluster"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := db.InsertNodes(clusters[0].Id, []db.NodeData{
+		{User: "admin", Protocol: u.Scheme, Host: u.Hostname(), Port: port, Weight: 10, MaxGroups: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	student := func(name string) schemas.Student {
+		fullName := "Student " + name
+		return schemas.Student{UserName: name, FullName: &fullName, Password: "password-" + name}
+	}
+	classData := schemas.Class{
+		Name: "net101",
+		Groups: []schemas.Group{
+			{Name: "net101-g1", Students: []schemas.Student{student("alice"), student("bob")}},
+			{Name: "net101-g2", Students: []schemas.Student{student("carol")}},
+		},
+	}
+	ok, err := class.CreateClass(ctx, cfg, clusters[0].Id, classData, nodes)
+	if err != nil || !ok {
+		t.Fatalf("CreateClass = %v, %v", ok, err)
+	}
+
+	groups := groupNames(t, c)
+	for _, want := range []string{"net101", "net101-g1", "net101-g2"} {
+		if !slices.Contains(groups, want) {
+			t.Errorf("group %s missing after create, have %v", want, groups)
+		}
+	}
+	users := usernames(t, c)
+	for _, want := range []string{"alice", "bob", "carol"} {
+		if !slices.Contains(users, want) {
+			t.Errorf("user %s missing after create, have %v", want, users)
+		}
+	}
+
+	if _, err := class.CreateClass(ctx, cfg, clusters[0].Id, classData, nodes); err == nil {
+		t.Error("creating the class twice succeeded")
+	}
+
+	if err := class.DeleteClass(ctx, cfg, "net101"); err != nil {
+		t.Fatalf("DeleteClass: %v", err)
+	}
+	for _, g := range groupNames(t, c) {
+		if g == "net101" || g == "net101-g1" || g == "net101-g2" {
+			t.Errorf("group %s left after delete", g)
+		}
+	}
+	for _, u := range usernames(t, c) {
+		if u == "alice" || u == "bob" || u == "carol" {
+			t.Errorf("user %s left after delete", u)
+		}
+	}
+	if !slices.Contains(usernames(t, c), "admin") {
+		t.Error("delete removed the admin user")
+	}
+}
+
+func TestExerciseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	cfg, c := start(t)
+
+	var projectIDs []string
+	for _, name := range []string{"lab-ospf-g1", "lab-ospf-g2", "lab-bgp-g1"} {
+		p, err := c.Projects().Create(ctx, schemas.ProjectCreate{Name: &name})
+		if err != nil {
+			t.Fatalf("create project %s: %v", name, err)
+		}
+		projectIDs = append(projectIDs, p.ProjectID)
+
+		nodes := c.Nodes(p.ProjectID)
+		var nodeIDs []string
+		for _, n := range []string{"PC1", "PC2"} {
+			nodeType := "vpcs"
+			node, err := nodes.Create(ctx, schemas.NodeCreate{Name: &n, NodeType: &nodeType})
+			if err != nil {
+				t.Fatalf("create node %s: %v", n, err)
+			}
+			nodeIDs = append(nodeIDs, node.NodeID)
+		}
+		if err := nodes.StartAll(ctx); err != nil {
+			t.Fatalf("start nodes: %v", err)
+		}
+		node, err := nodes.Get(ctx, nodeIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Status != "started" {
+			t.Errorf("node status = %q after start, want started", node.Status)
+		}
+		if err := nodes.Delete(ctx, nodeIDs[1]); err != nil {
+			t.Fatalf("delete node: %v", err)
+		}
+		if list, err := nodes.List(ctx); err != nil || len(list) != 1 {
+			t.Errorf("nodes after delete = %d, %v, want 1", len(list), err)
+		}
+	}
+
+	if err := class.DeleteExercise(ctx, cfg, "ospf", "lab", ""); err != nil {
+		t.Fatalf("DeleteExercise: %v", err)
+	}
+	projects, err := c.Projects().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, p := range projects {
+		left = append(left, p.Name)
+	}
+	if !slices.Equal(left, []string{"lab-bgp-g1"}) {
+		t.Errorf("projects after deleting ospf = %v, want [lab-bgp-g1]", left)
+	}
+	if _, err := c.Projects().Get(ctx, projectIDs[0]); err == nil {
+		t.Error("deleted project can still be fetched")
+	}
+
+	if err := class.DeleteExercise(ctx, cfg, "ospf", "lab", ""); err == nil {
+		t.Error("deleting a missing exercise succeeded")
+	}
+}
@@ -0,0 +1,131 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// pingInterval matches the controller, which sends a ping so idle
+// notification streams are not closed by proxies.
+const pingInterval = 10 * time.Second
+
+type event struct {
+	projectID string
+	data      []byte
+}
+
+// hub fans out notification events to the connected streams. Slow readers
+// drop events rather than blocking the handler that published them.
+type hub struct {
+	mu     sync.Mutex
+	subs   map[chan event]struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[chan event]struct{}), closed: make(chan struct{})}
+}
+
+func (h *hub) subscribe() chan event {
+	ch := make(chan event, 64)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *hub) unsubscribe(ch chan event) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
+
+func (h *hub) send(ev event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (h *hub) close() {
+	h.once.Do(func() { close(h.closed) })
+}
+
+// publish must be called with s.mu held; obj is copied before it is encoded.
+func (s *Server) publish(projectID, action string, obj object) {
+	data, err := json.Marshal(object{"action": action, "event": maps.Clone(obj)})
+	if err != nil {
+		return
+	}
+	s.hub.send(event{projectID: projectID, data: data})
+}
+
+func (s *Server) notifications(w http.ResponseWriter, r *http.Request) {
+	s.stream(w, r, "")
+}
+
+func (s *Server) projectNotifications(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	project, ok := s.lookupProject(w, r)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	s.stream(w, r, project["project_id"].(string))
+}
+
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, projectID string) {
+	ch := s.hub.subscribe()
+	defer s.hub.unsubscribe(ch)
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(data []byte) bool {
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	ping := func() bool {
+		data, _ := json.Marshal(object{"action": "ping", "event": object{"cpu_usage_percent": 0, "memory_usage_percent": 0}})
+		return write(data)
+	}
+
+	if !ping() {
+		return
+	}
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.hub.closed:
+			return
+		case <-ticker.C:
+			if !ping() {
+				return
+			}
+		case ev := <-ch:
+			if projectID != "" && ev.projectID != projectID {
+				continue
+			}
+			if !write(ev.data) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,1057 @@
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func (s *Server) projectRoutes() {
+	s.handle("GET /projects", s.listProjects)
+	s.handle("POST /projects", s.createProject)
+	s.handle("POST /projects/load", s.loadProject)
+	s.handle("GET /projects/{project_id}", s.getProject)
+	s.handle("PUT /projects/{project_id}", s.updateProject)
+	s.handle("DELETE /projects/{project_id}", s.deleteProject)
+	s.handle("POST /projects/{project_id}/open", s.setProjectStatus("opened"))
+	s.handle("POST /projects/{project_id}/close", s.setProjectStatus("closed"))
+	s.handle("POST /projects/{project_id}/duplicate", s.duplicateProject)
+	s.handle("GET /projects/{project_id}/export", s.exportProject)
+	s.handle("POST /projects/{project_id}/import", s.importProject)
+	s.handle("GET /projects/{project_id}/stats", s.projectStats)
+	s.handle("POST /projects/{project_id}/lock", s.setProjectLock(true))
+	s.handle("POST /projects/{project_id}/unlock", s.setProjectLock(false))
+	s.handle("GET /projects/{project_id}/locked", s.projectLocked)
+
+	s.handle("GET /projects/{project_id}/nodes", s.listNodes)
+	s.handle("POST /projects/{project_id}/nodes", s.createNode)
+	s.handle("POST /projects/{project_id}/nodes/start", s.setAllNodesStatus("started"))
+	s.handle("POST /projects/{project_id}/nodes/stop", s.setAllNodesStatus("stopped"))
+	s.handle("POST /projects/{project_id}/nodes/suspend", s.setAllNodesStatus("suspended"))
+	s.handle("POST /projects/{project_id}/nodes/reload", s.setAllNodesStatus("started"))
+	s.handle("POST /projects/{project_id}/nodes/console/reset", s.projectNoContent)
+	s.handle("GET /projects/{project_id}/nodes/{node_id}", s.getNode)
+	s.handle("PUT /projects/{project_id}/nodes/{node_id}", s.updateNode)
+	s.handle("DELETE /projects/{project_id}/nodes/{node_id}", s.deleteNode)
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/start", s.setNodeStatus("started"))
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/stop", s.setNodeStatus("stopped"))
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/suspend", s.setNodeStatus("suspended"))
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/reload", s.setNodeStatus("started"))
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/isolate", s.nodeNoContent)
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/unisolate", s.nodeNoContent)
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/console/reset", s.nodeNoContent)
+	s.handle("POST /projects/{project_id}/nodes/{node_id}/duplicate", s.duplicateNode)
+	s.handle("GET /projects/{project_id}/nodes/{node_id}/links", s.nodeLinks)
+	s.handle("POST /projects/{project_id}/templates/{template_id}", s.createNodeFromTemplate)
+
+	s.handle("GET /projects/{project_id}/links", s.listLinks)
+	s.handle("POST /projects/{project_id}/links", s.createLink)
+	s.handle("GET /projects/{project_id}/links/{link_id}", s.getLink)
+	s.handle("PUT /projects/{project_id}/links/{link_id}", s.updateLink)
+	s.handle("DELETE /projects/{project_id}/links/{link_id}", s.deleteLink)
+	s.handle("POST /projects/{project_id}/links/{link_id}/reset", s.resetLink)
+	s.handle("POST /projects/{project_id}/links/{link_id}/capture/start", s.setLinkCapture(true))
+	s.handle("POST /projects/{project_id}/links/{link_id}/capture/stop", s.setLinkCapture(false))
+	s.handle("GET /projects/{project_id}/links/{link_id}/available_filters", s.linkFilters)
+
+	s.handle("GET /projects/{project_id}/drawings", s.listDrawings)
+	s.handle("POST /projects/{project_id}/drawings", s.createDrawing)
+	s.handle("GET /projects/{project_id}/drawings/{drawing_id}", s.getDrawing)
+	s.handle("PUT /projects/{project_id}/drawings/{drawing_id}", s.updateDrawing)
+	s.handle("DELETE /projects/{project_id}/drawings/{drawing_id}", s.deleteDrawing)
+
+	s.handle("GET /projects/{project_id}/snapshots", s.listSnapshots)
+	s.handle("POST /projects/{project_id}/snapshots", s.createSnapshot)
+	s.handle("DELETE /projects/{project_id}/snapshots/{snapshot_id}", s.deleteSnapshot)
+	s.handle("POST /projects/{project_id}/snapshots/{snapshot_id}/restore", s.restoreSnapshot)
+
+	s.handle("GET /templates", s.listTemplates)
+	s.handle("POST /templates", s.createTemplate)
+	s.handle("GET /templates/{template_id}", s.getTemplate)
+	s.handle("DELETE /templates/{template_id}", s.deleteTemplate)
+	s.handle("POST /templates/{template_id}/duplicate", s.duplicateTemplate)
+
+	s.handle("GET /computes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.computes.list())
+	})
+	s.handle("GET /computes/{compute_id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("compute_id")
+		compute, ok := s.computes.get(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "Compute ID '%s' doesn't exist", id)
+			return
+		}
+		writeJSON(w, http.StatusOK, compute)
+	})
+}
+
+var projectFields = []string{
+	"name", "path", "auto_close", "auto_open", "auto_start", "scene_height", "scene_width",
+	"zoom", "show_layers", "snap_to_grid", "show_grid", "grid_size", "drawing_grid_size",
+	"show_interface_labels", "supplier", "variables",
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.projects.list())
+}
+
+func (s *Server) newProject(body object) object {
+	project := object{
+		"project_id":            nil,
+		"status":                "opened",
+		"auto_close":            true,
+		"auto_open":             false,
+		"auto_start":            false,
+		"scene_height":          1000,
+		"scene_width":           2000,
+		"zoom":                  100,
+		"show_layers":           false,
+		"snap_to_grid":          false,
+		"show_grid":             false,
+		"grid_size":             75,
+		"drawing_grid_size":     25,
+		"show_interface_labels": false,
+		"supplier":              nil,
+		"variables":             nil,
+		"locked":                false,
+	}
+	merge(project, body, projectFields...)
+	if id, _ := body["project_id"].(string); id != "" {
+		project["project_id"] = id
+	} else {
+		project["project_id"] = uuid.NewString()
+	}
+	project["filename"] = fmt.Sprintf("%v.gns3", project["name"])
+	if project["path"] == nil {
+		project["path"] = "/opt/gns3/projects/" + project["project_id"].(string)
+	}
+	return s.projects.add(project)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	if _, exists := s.projects.find("name", name); exists {
+		writeError(w, http.StatusConflict, "Project '%s' already exists", name)
+		return
+	}
+	if id, _ := body["project_id"].(string); id != "" {
+		if _, exists := s.projects.get(id); exists {
+			writeError(w, http.StatusConflict, "Project ID %s already exists", id)
+			return
+		}
+	}
+	created := s.newProject(body)
+	s.publish(created["project_id"].(string), "project.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) loadProject(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	path, ok := requireString(w, body, "path")
+	if !ok {
+		return
+	}
+	writeError(w, http.StatusNotFound, "Project file '%s' does not exist on the fake controller", path)
+}
+
+func (s *Server) lookupProject(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "project_id")
+	if !ok {
+		return nil, false
+	}
+	project, ok := s.projects.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Project ID %s doesn't exist", id)
+	}
+	return project, ok
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	if project, ok := s.lookupProject(w, r); ok {
+		writeJSON(w, http.StatusOK, project)
+	}
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name != "" && name != project["name"] {
+		if _, exists := s.projects.find("name", name); exists {
+			writeError(w, http.StatusConflict, "Project '%s' already exists", name)
+			return
+		}
+	}
+	merge(project, body, projectFields...)
+	s.publish(project["project_id"].(string), "project.updated", project)
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"].(string)
+	inProject := func(obj object) bool { return obj["project_id"] == id }
+	s.nodes.removeWhere(inProject)
+	s.links.removeWhere(inProject)
+	s.drawings.removeWhere(inProject)
+	s.snapshots.removeWhere(inProject)
+	for pid, resources := range s.poolResources {
+		s.poolResources[pid] = slices.DeleteFunc(resources, func(rid string) bool { return rid == id })
+	}
+	s.projects.remove(id)
+	s.publish(id, "project.deleted", project)
+	writeNoContent(w)
+}
+
+func (s *Server) setProjectStatus(status string) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.lookupProject(w, r)
+		if !ok {
+			return
+		}
+		project["status"] = status
+		if status == "closed" {
+			s.publish(project["project_id"].(string), "project.closed", project)
+			writeNoContent(w)
+			return
+		}
+		s.publish(project["project_id"].(string), "project.updated", project)
+		writeJSON(w, http.StatusCreated, project)
+	}
+}
+
+func (s *Server) setProjectLock(locked bool) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.lookupProject(w, r)
+		if !ok {
+			return
+		}
+		project["locked"] = locked
+		id := project["project_id"].(string)
+		for _, node := range s.nodes.items {
+			if node["project_id"] == id {
+				node["locked"] = locked
+			}
+		}
+		writeNoContent(w)
+	}
+}
+
+func (s *Server) projectLocked(w http.ResponseWriter, r *http.Request) {
+	if project, ok := s.lookupProject(w, r); ok {
+		writeJSON(w, http.StatusOK, project["locked"])
+	}
+}
+
+func (s *Server) projectStats(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"].(string)
+	inProject := func(obj object) bool { return obj["project_id"] == id }
+	writeJSON(w, http.StatusOK, object{
+		"nodes":     len(s.nodes.filter(inProject)),
+		"links":     len(s.links.filter(inProject)),
+		"drawings":  len(s.drawings.filter(inProject)),
+		"snapshots": len(s.snapshots.filter(inProject)),
+	})
+}
+
+// projectArchive is the fake export format: plain JSON instead of a zip, but
+// it round-trips through export/import and duplicate the same way.
+type projectArchive struct {
+	Project  object   `json:"project"`
+	Nodes    []object `json:"nodes"`
+	Links    []object `json:"links"`
+	Drawings []object `json:"drawings"`
+}
+
+func (s *Server) archive(projectID string) projectArchive {
+	inProject := func(obj object) bool { return obj["project_id"] == projectID }
+	project, _ := s.projects.get(projectID)
+	return projectArchive{
+		Project:  maps.Clone(project),
+		Nodes:    s.nodes.filter(inProject),
+		Links:    s.links.filter(inProject),
+		Drawings: s.drawings.filter(inProject),
+	}
+}
+
+// restore creates a new project from a by giving every object a fresh id and
+// rewriting the references between them.
+func (s *Server) restore(a projectArchive, projectID, name string) object {
+	body := maps.Clone(a.Project)
+	body["project_id"] = projectID
+	body["name"] = name
+	delete(body, "path")
+	project := s.newProject(body)
+
+	nodeIDs := make(map[string]string)
+	for _, n := range a.Nodes {
+		n = maps.Clone(n)
+		old := n["node_id"].(string)
+		n["node_id"] = uuid.NewString()
+		n["project_id"] = projectID
+		n["status"] = "stopped"
+		nodeIDs[old] = n["node_id"].(string)
+		s.nodes.add(n)
+	}
+	for _, l := range a.Links {
+		l = maps.Clone(l)
+		l["link_id"] = uuid.NewString()
+		l["project_id"] = projectID
+		l["nodes"] = remapLinkNodes(l["nodes"], nodeIDs)
+		s.links.add(l)
+	}
+	for _, d := range a.Drawings {
+		d = maps.Clone(d)
+		d["drawing_id"] = uuid.NewString()
+		d["project_id"] = projectID
+		s.drawings.add(d)
+	}
+	return project
+}
+
+func remapLinkNodes(v any, ids map[string]string) []any {
+	var out []any
+	list, _ := v.([]any)
+	for _, item := range list {
+		end, _ := item.(map[string]any)
+		end = maps.Clone(end)
+		if id, ok := ids[stringField(end, "node_id")]; ok {
+			end["node_id"] = id
+		}
+		out = append(out, end)
+	}
+	return out
+}
+
+func (s *Server) duplicateProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	if _, exists := s.projects.find("name", name); exists {
+		writeError(w, http.StatusConflict, "Project '%s' already exists", name)
+		return
+	}
+	// Round-trip through JSON so the copy shares no nested values.
+	var a projectArchive
+	data, _ := json.Marshal(s.archive(project["project_id"].(string)))
+	_ = json.Unmarshal(data, &a)
+	created := s.restore(a, uuid.NewString(), name)
+	s.publish(created["project_id"].(string), "project.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) exportProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%v.gns3project", project["name"])))
+	writeJSON(w, http.StatusOK, s.archive(project["project_id"].(string)))
+}
+
+func (s *Server) importProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "project_id")
+	if !ok {
+		return
+	}
+	if _, exists := s.projects.get(id); exists {
+		writeError(w, http.StatusConflict, "Project ID %s already exists", id)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read project archive: %v", err)
+		return
+	}
+	// The archive may be sent raw or wrapped in a multipart form, so take the
+	// outermost JSON object from the body.
+	start, end := bytes.IndexByte(data, '{'), bytes.LastIndexByte(data, '}')
+	var a projectArchive
+	if start < 0 || end < start || json.Unmarshal(data[start:end+1], &a) != nil || a.Project == nil {
+		writeError(w, http.StatusBadRequest, "Can't import project: not a project archive exported by this controller")
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name, _ = a.Project["name"].(string)
+	}
+	if _, exists := s.projects.find("name", name); exists {
+		writeError(w, http.StatusConflict, "Project '%s' already exists", name)
+		return
+	}
+	created := s.restore(a, id, name)
+	s.publish(id, "project.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) projectNoContent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookupProject(w, r); ok {
+		writeNoContent(w)
+	}
+}
+
+var nodeFields = []string{
+	"name", "node_type", "template_id", "compute_id", "console", "console_type", "console_auto_start",
+	"properties", "label", "symbol", "x", "y", "z", "locked", "port_name_format",
+	"port_segment_size", "first_port_name", "custom_adapters", "aux", "aux_type", "tags",
+}
+
+func (s *Server) listNodes(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"]
+	writeJSON(w, http.StatusOK, s.nodes.filter(func(n object) bool { return n["project_id"] == id }))
+}
+
+func (s *Server) addNode(projectID string, body object) object {
+	node := object{
+		"project_id":   projectID,
+		"compute_id":   "local",
+		"console":      5000 + len(s.nodes.order),
+		"console_type": "telnet",
+		"console_host": "127.0.0.1",
+		"status":       "stopped",
+		"properties":   object{},
+		"symbol":       ":/symbols/computer.svg",
+		"x":            0,
+		"y":            0,
+		"z":            1,
+		"locked":       false,
+	}
+	merge(node, body, nodeFields...)
+	node["ports"] = nodePorts(stringField(node, "node_type"))
+	created := s.nodes.add(node)
+	s.publish(projectID, "node.created", created)
+	return created
+}
+
+func nodePorts(nodeType string) []object {
+	var ports []object
+	switch nodeType {
+	case "ethernet_switch", "ethernet_hub":
+		for i := range 8 {
+			ports = append(ports, object{"name": fmt.Sprintf("Ethernet%d", i), "short_name": fmt.Sprintf("e%d", i), "adapter_number": 0, "port_number": i, "link_type": "ethernet"})
+		}
+	case "vpcs":
+		ports = append(ports, object{"name": "Ethernet0", "short_name": "e0", "adapter_number": 0, "port_number": 0, "link_type": "ethernet"})
+	default:
+		for i := range 4 {
+			ports = append(ports, object{"name": fmt.Sprintf("eth%d", i), "short_name": fmt.Sprintf("eth%d", i), "adapter_number": i, "port_number": 0, "link_type": "ethernet"})
+		}
+	}
+	return ports
+}
+
+func (s *Server) createNode(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := requireString(w, body, "name"); !ok {
+		return
+	}
+	if _, ok := requireString(w, body, "node_type"); !ok {
+		return
+	}
+	if cid := stringField(body, "compute_id"); cid != "" {
+		if _, ok := s.computes.get(cid); !ok {
+			writeError(w, http.StatusNotFound, "Compute ID '%s' doesn't exist", cid)
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.addNode(project["project_id"].(string), body))
+}
+
+func (s *Server) createNodeFromTemplate(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	tid, ok := pathID(w, r, "template_id")
+	if !ok {
+		return
+	}
+	template, ok := s.templates.get(tid)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template ID '%s' doesn't exist", tid)
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	pid := project["project_id"].(string)
+	node := object{
+		"name":        s.nextNodeName(pid, stringField(template, "name")),
+		"node_type":   template["template_type"],
+		"compute_id":  template["compute_id"],
+		"symbol":      template["symbol"],
+		"template_id": tid,
+	}
+	merge(node, body, "name", "x", "y", "compute_id")
+	writeJSON(w, http.StatusCreated, s.addNode(pid, node))
+}
+
+func (s *Server) nextNodeName(projectID, base string) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s-%d", base, i)
+		taken := false
+		for _, n := range s.nodes.items {
+			if n["project_id"] == projectID && n["name"] == name {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return name
+		}
+	}
+}
+
+func (s *Server) lookupNode(w http.ResponseWriter, r *http.Request) (object, bool) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "node_id")
+	if !ok {
+		return nil, false
+	}
+	node, ok := s.nodes.get(id)
+	if !ok || node["project_id"] != project["project_id"] {
+		writeError(w, http.StatusNotFound, "Node ID %s doesn't exist", id)
+		return nil, false
+	}
+	return node, true
+}
+
+func (s *Server) getNode(w http.ResponseWriter, r *http.Request) {
+	if node, ok := s.lookupNode(w, r); ok {
+		writeJSON(w, http.StatusOK, node)
+	}
+}
+
+func (s *Server) updateNode(w http.ResponseWriter, r *http.Request) {
+	node, ok := s.lookupNode(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	merge(node, body, nodeFields...)
+	s.publish(node["project_id"].(string), "node.updated", node)
+	writeJSON(w, http.StatusOK, node)
+}
+
+func (s *Server) deleteNode(w http.ResponseWriter, r *http.Request) {
+	node, ok := s.lookupNode(w, r)
+	if !ok {
+		return
+	}
+	id := node["node_id"].(string)
+	pid := node["project_id"].(string)
+	for _, lid := range s.links.removeWhere(func(l object) bool { return linkTouches(l, id) }) {
+		s.publish(pid, "link.deleted", object{"link_id": lid, "project_id": pid})
+	}
+	s.nodes.remove(id)
+	s.publish(pid, "node.deleted", node)
+	writeNoContent(w)
+}
+
+func (s *Server) setNodeStatus(status string) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		node, ok := s.lookupNode(w, r)
+		if !ok {
+			return
+		}
+		node["status"] = status
+		s.publish(node["project_id"].(string), "node.updated", node)
+		writeJSON(w, http.StatusOK, node)
+	}
+}
+
+func (s *Server) setAllNodesStatus(status string) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.lookupProject(w, r)
+		if !ok {
+			return
+		}
+		pid := project["project_id"].(string)
+		for _, id := range s.nodes.order {
+			if node := s.nodes.items[id]; node["project_id"] == pid {
+				node["status"] = status
+				s.publish(pid, "node.updated", node)
+			}
+		}
+		writeNoContent(w)
+	}
+}
+
+func (s *Server) nodeNoContent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookupNode(w, r); ok {
+		writeNoContent(w)
+	}
+}
+
+func (s *Server) duplicateNode(w http.ResponseWriter, r *http.Request) {
+	node, ok := s.lookupNode(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	dup := maps.Clone(node)
+	delete(dup, "node_id")
+	pid := node["project_id"].(string)
+	dup["name"] = s.nextNodeName(pid, stringField(node, "name"))
+	merge(dup, body, "x", "y", "z")
+	writeJSON(w, http.StatusCreated, s.addNode(pid, dup))
+}
+
+func (s *Server) nodeLinks(w http.ResponseWriter, r *http.Request) {
+	node, ok := s.lookupNode(w, r)
+	if !ok {
+		return
+	}
+	id := node["node_id"].(string)
+	writeJSON(w, http.StatusOK, s.links.filter(func(l object) bool { return linkTouches(l, id) }))
+}
+
+func linkEnds(l object) []map[string]any {
+	var ends []map[string]any
+	switch v := l["nodes"].(type) {
+	case []any:
+		for _, item := range v {
+			if end, ok := item.(map[string]any); ok {
+				ends = append(ends, end)
+			}
+		}
+	}
+	return ends
+}
+
+func linkTouches(l object, nodeID string) bool {
+	for _, end := range linkEnds(l) {
+		if end["node_id"] == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listLinks(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"]
+	writeJSON(w, http.StatusOK, s.links.filter(func(l object) bool { return l["project_id"] == id }))
+}
+
+func (s *Server) createLink(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	pid := project["project_id"].(string)
+	ends := linkEnds(body)
+	if len(ends) != 2 {
+		validationError(w, []string{"body", "nodes"}, "A link must connect exactly two nodes", "value_error")
+		return
+	}
+	for _, end := range ends {
+		nid := stringField(end, "node_id")
+		node, ok := s.nodes.get(nid)
+		if !ok || node["project_id"] != pid {
+			writeError(w, http.StatusNotFound, "Node ID %s doesn't exist", nid)
+			return
+		}
+		for _, l := range s.links.items {
+			for _, other := range linkEnds(l) {
+				if other["node_id"] == nid &&
+					fmt.Sprint(other["adapter_number"]) == fmt.Sprint(end["adapter_number"]) &&
+					fmt.Sprint(other["port_number"]) == fmt.Sprint(end["port_number"]) {
+					writeError(w, http.StatusConflict, "Port %v/%v is already used on node %v", end["adapter_number"], end["port_number"], node["name"])
+					return
+				}
+			}
+		}
+	}
+	link := object{
+		"project_id":        pid,
+		"nodes":             body["nodes"],
+		"suspend":           false,
+		"filters":           object{},
+		"capturing":         false,
+		"capture_file_name": nil,
+		"link_type":         "ethernet",
+		"link_style":        object{},
+	}
+	merge(link, body, "suspend", "filters", "link_style")
+	created := s.links.add(link)
+	s.publish(pid, "link.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) lookupLink(w http.ResponseWriter, r *http.Request) (object, bool) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "link_id")
+	if !ok {
+		return nil, false
+	}
+	link, ok := s.links.get(id)
+	if !ok || link["project_id"] != project["project_id"] {
+		writeError(w, http.StatusNotFound, "Link ID %s doesn't exist", id)
+		return nil, false
+	}
+	return link, true
+}
+
+func (s *Server) getLink(w http.ResponseWriter, r *http.Request) {
+	if link, ok := s.lookupLink(w, r); ok {
+		writeJSON(w, http.StatusOK, link)
+	}
+}
+
+func (s *Server) updateLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := s.lookupLink(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	merge(link, body, "suspend", "filters", "link_style")
+	s.publish(link["project_id"].(string), "link.updated", link)
+	writeJSON(w, http.StatusCreated, link)
+}
+
+func (s *Server) deleteLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := s.lookupLink(w, r)
+	if !ok {
+		return
+	}
+	s.links.remove(link["link_id"].(string))
+	s.publish(link["project_id"].(string), "link.deleted", link)
+	writeNoContent(w)
+}
+
+func (s *Server) resetLink(w http.ResponseWriter, r *http.Request) {
+	if link, ok := s.lookupLink(w, r); ok {
+		writeJSON(w, http.StatusCreated, link)
+	}
+}
+
+func (s *Server) setLinkCapture(capturing bool) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		link, ok := s.lookupLink(w, r)
+		if !ok {
+			return
+		}
+		link["capturing"] = capturing
+		if capturing {
+			link["capture_file_name"] = link["link_id"].(string) + ".pcap"
+			s.publish(link["project_id"].(string), "link.updated", link)
+			writeJSON(w, http.StatusCreated, link)
+			return
+		}
+		link["capture_file_name"] = nil
+		s.publish(link["project_id"].(string), "link.updated", link)
+		writeNoContent(w)
+	}
+}
+
+func (s *Server) linkFilters(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookupLink(w, r); ok {
+		writeJSON(w, http.StatusOK, []object{})
+	}
+}
+
+var drawingFields = []string{"svg", "x", "y", "z", "locked", "rotation"}
+
+func (s *Server) listDrawings(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"]
+	writeJSON(w, http.StatusOK, s.drawings.filter(func(d object) bool { return d["project_id"] == id }))
+}
+
+func (s *Server) createDrawing(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	pid := project["project_id"].(string)
+	drawing := object{"project_id": pid, "svg": "", "x": 0, "y": 0, "z": 2, "locked": false, "rotation": 0}
+	merge(drawing, body, drawingFields...)
+	created := s.drawings.add(drawing)
+	s.publish(pid, "drawing.created", created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) lookupDrawing(w http.ResponseWriter, r *http.Request) (object, bool) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "drawing_id")
+	if !ok {
+		return nil, false
+	}
+	drawing, ok := s.drawings.get(id)
+	if !ok || drawing["project_id"] != project["project_id"] {
+		writeError(w, http.StatusNotFound, "Drawing ID %s doesn't exist", id)
+		return nil, false
+	}
+	return drawing, true
+}
+
+func (s *Server) getDrawing(w http.ResponseWriter, r *http.Request) {
+	if drawing, ok := s.lookupDrawing(w, r); ok {
+		writeJSON(w, http.StatusOK, drawing)
+	}
+}
+
+func (s *Server) updateDrawing(w http.ResponseWriter, r *http.Request) {
+	drawing, ok := s.lookupDrawing(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	merge(drawing, body, drawingFields...)
+	s.publish(drawing["project_id"].(string), "drawing.updated", drawing)
+	writeJSON(w, http.StatusCreated, drawing)
+}
+
+func (s *Server) deleteDrawing(w http.ResponseWriter, r *http.Request) {
+	drawing, ok := s.lookupDrawing(w, r)
+	if !ok {
+		return
+	}
+	s.drawings.remove(drawing["drawing_id"].(string))
+	s.publish(drawing["project_id"].(string), "drawing.deleted", drawing)
+	writeNoContent(w)
+}
+
+func (s *Server) listSnapshots(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	id := project["project_id"]
+	snapshots := s.snapshots.filter(func(sn object) bool { return sn["project_id"] == id })
+	for _, sn := range snapshots {
+		delete(sn, "state")
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+}
+
+func (s *Server) createSnapshot(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return
+	}
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	name, ok := requireString(w, body, "name")
+	if !ok {
+		return
+	}
+	pid := project["project_id"].(string)
+	for _, sn := range s.snapshots.items {
+		if sn["project_id"] == pid && sn["name"] == name {
+			writeError(w, http.StatusConflict, "The snapshot name %s already exists", name)
+			return
+		}
+	}
+	state, _ := json.Marshal(s.archive(pid))
+	created := s.snapshots.add(object{"project_id": pid, "name": name, "created_at": timestamp(), "state": string(state)})
+	delete(created, "state")
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) lookupSnapshot(w http.ResponseWriter, r *http.Request) (object, bool) {
+	project, ok := s.lookupProject(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "snapshot_id")
+	if !ok {
+		return nil, false
+	}
+	snapshot, ok := s.snapshots.get(id)
+	if !ok || snapshot["project_id"] != project["project_id"] {
+		writeError(w, http.StatusNotFound, "Snapshot ID %s doesn't exist", id)
+		return nil, false
+	}
+	return snapshot, true
+}
+
+func (s *Server) deleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	if snapshot, ok := s.lookupSnapshot(w, r); ok {
+		s.snapshots.remove(snapshot["snapshot_id"].(string))
+		writeNoContent(w)
+	}
+}
+
+func (s *Server) restoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.lookupSnapshot(w, r)
+	if !ok {
+		return
+	}
+	pid := snapshot["project_id"].(string)
+	var a projectArchive
+	if err := json.Unmarshal([]byte(snapshot["state"].(string)), &a); err != nil {
+		writeError(w, http.StatusInternalServerError, "corrupted snapshot: %v", err)
+		return
+	}
+	inProject := func(obj object) bool { return obj["project_id"] == pid }
+	s.nodes.removeWhere(inProject)
+	s.links.removeWhere(inProject)
+	s.drawings.removeWhere(inProject)
+	for _, n := range a.Nodes {
+		s.nodes.add(n)
+	}
+	for _, l := range a.Links {
+		s.links.add(l)
+	}
+	for _, d := range a.Drawings {
+		s.drawings.add(d)
+	}
+	project, _ := s.projects.get(pid)
+	s.publish(pid, "project.updated", project)
+	writeJSON(w, http.StatusCreated, project)
+}
+
+var templateFields = []string{"name", "template_type", "category", "symbol", "compute_id", "default_name_format", "properties"}
+
+func (s *Server) listTemplates(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.templates.list())
+}
+
+func (s *Server) createTemplate(w http.ResponseWriter, r *http.Request) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := requireString(w, body, "name"); !ok {
+		return
+	}
+	if _, ok := requireString(w, body, "template_type"); !ok {
+		return
+	}
+	template := object{"category": "guest", "symbol": ":/symbols/computer.svg", "compute_id": "local", "builtin": false}
+	merge(template, body, templateFields...)
+	writeJSON(w, http.StatusCreated, s.templates.add(template))
+}
+
+func (s *Server) lookupTemplate(w http.ResponseWriter, r *http.Request) (object, bool) {
+	id, ok := pathID(w, r, "template_id")
+	if !ok {
+		return nil, false
+	}
+	template, ok := s.templates.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template ID '%s' doesn't exist", id)
+	}
+	return template, ok
+}
+
+func (s *Server) getTemplate(w http.ResponseWriter, r *http.Request) {
+	if template, ok := s.lookupTemplate(w, r); ok {
+		writeJSON(w, http.StatusOK, template)
+	}
+}
+
+func (s *Server) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	if template["builtin"] == true {
+		writeError(w, http.StatusForbidden, "Template ID '%s' cannot be deleted because it is a builtin", template["template_id"])
+		return
+	}
+	s.templates.remove(template["template_id"].(string))
+	writeNoContent(w)
+}
+
+func (s *Server) duplicateTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	if template["builtin"] == true {
+		writeError(w, http.StatusForbidden, "Template ID '%s' cannot be duplicated because it is a builtin", template["template_id"])
+		return
+	}
+	dup := maps.Clone(template)
+	delete(dup, "template_id")
+	writeJSON(w, http.StatusCreated, s.templates.add(dup))
+}
@@ -0,0 +1,357 @@
+// Package fakeserver is an in-memory stand-in for a GNS3v3 controller. It
+// implements the /v3 endpoints gns3util talks to closely enough to run whole
+// class and exercise lifecycles without a real server:
+//
+//	srv := fakeserver.New(fakeserver.Options{})
+//	ts := httptest.NewServer(srv)
+//	defer ts.Close()
+//
+// State lives only in memory and is lost when the server stops.
+package fakeserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultAdminUser     = "admin"
+	DefaultAdminPassword = "admin"
+	DefaultVersion       = "3.0.0"
+)
+
+type Options struct {
+	AdminUser     string
+	AdminPassword string
+	Version       string
+}
+
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+	hub  *hub
+
+	mu            sync.Mutex
+	tokens        map[string]string
+	passwords     map[string]string
+	users         *collection
+	groups        *collection
+	members       map[string][]string
+	roles         *collection
+	privileges    *collection
+	rolePrivs     map[string][]string
+	acl           *collection
+	pools         *collection
+	poolResources map[string][]string
+	projects      *collection
+	nodes         *collection
+	links         *collection
+	drawings      *collection
+	snapshots     *collection
+	templates     *collection
+	computes      *collection
+}
+
+func New(opts Options) *Server {
+	if opts.AdminUser == "" {
+		opts.AdminUser = DefaultAdminUser
+	}
+	if opts.AdminPassword == "" {
+		opts.AdminPassword = DefaultAdminPassword
+	}
+	if opts.Version == "" {
+		opts.Version = DefaultVersion
+	}
+
+	s := &Server{
+		opts:          opts,
+		mux:           http.NewServeMux(),
+		hub:           newHub(),
+		tokens:        make(map[string]string),
+		passwords:     make(map[string]string),
+		users:         newCollection("user_id"),
+		groups:        newCollection("user_group_id"),
+		members:       make(map[string][]string),
+		roles:         newCollection("role_id"),
+		privileges:    newCollection("privilege_id"),
+		rolePrivs:     make(map[string][]string),
+		acl:           newCollection("ace_id"),
+		pools:         newCollection("resource_pool_id"),
+		poolResources: make(map[string][]string),
+		projects:      newCollection("project_id"),
+		nodes:         newCollection("node_id"),
+		links:         newCollection("link_id"),
+		drawings:      newCollection("drawing_id"),
+		snapshots:     newCollection("snapshot_id"),
+		templates:     newCollection("template_id"),
+		computes:      newCollection("compute_id"),
+	}
+	s.seed()
+	s.routes()
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// AdminToken returns a fresh access token for the admin user so Go callers
+// can skip the authenticate round trip.
+func (s *Server) AdminToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	admin, _ := s.users.find("username", s.opts.AdminUser)
+	return s.newToken(admin["user_id"].(string))
+}
+
+// ListenAndServe serves srv on addr until ctx is cancelled.
+func ListenAndServe(ctx context.Context, addr string, srv *Server) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	httpSrv := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.hub.close()
+		_ = httpSrv.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) seed() {
+	now := timestamp()
+	admin := s.users.add(object{
+		"username":      s.opts.AdminUser,
+		"email":         s.opts.AdminUser + "@localhost",
+		"full_name":     "Administrator",
+		"is_active":     true,
+		"is_superadmin": true,
+		"created_at":    now,
+		"updated_at":    now,
+	})
+	s.passwords[admin["user_id"].(string)] = s.opts.AdminPassword
+
+	admins := s.groups.add(object{"name": "Administrators", "is_builtin": true, "created_at": now, "updated_at": now})
+	s.groups.add(object{"name": "Users", "is_builtin": true, "created_at": now, "updated_at": now})
+	s.members[admins["user_group_id"].(string)] = []string{admin["user_id"].(string)}
+
+	for _, name := range []string{
+		"Project.Allocate", "Project.Audit", "Project.Modify",
+		"Node.Allocate", "Node.Audit", "Node.Modify", "Node.Console", "Node.PowerMgmt",
+		"Link.Allocate", "Link.Audit", "Link.Modify", "Link.Capture",
+		"Snapshot.Allocate", "Snapshot.Audit", "Snapshot.Restore",
+		"Template.Allocate", "Template.Audit", "Template.Modify",
+		"User.Allocate", "User.Audit", "User.Modify",
+		"Group.Allocate", "Group.Audit", "Group.Modify",
+		"Role.Allocate", "Role.Audit", "Role.Modify",
+		"Pool.Allocate", "Pool.Audit", "Pool.Modify",
+		"ACE.Allocate", "ACE.Audit", "ACE.Modify",
+	} {
+		s.privileges.add(object{"name": name, "description": name})
+	}
+
+	builtinRoles := map[string]string{
+		"Administrator":    "",
+		"User":             "Project.,Node.,Link.,Snapshot.,Template.Audit",
+		"Auditor":          ".Audit",
+		"Template manager": "Template.",
+	}
+	for _, name := range []string{"Administrator", "User", "Auditor", "Template manager"} {
+		role := s.roles.add(object{"name": name, "description": name + " role", "is_builtin": true, "created_at": now, "updated_at": now})
+		roleID := role["role_id"].(string)
+		for _, priv := range s.privileges.list() {
+			privName := priv["name"].(string)
+			if name == "Administrator" || matchesAny(privName, builtinRoles[name]) {
+				s.rolePrivs[roleID] = append(s.rolePrivs[roleID], priv["privilege_id"].(string))
+			}
+		}
+	}
+
+	s.computes.add(object{
+		"compute_id":           "local",
+		"name":                 "fake-controller",
+		"protocol":             "http",
+		"host":                 "127.0.0.1",
+		"port":                 3080,
+		"connected":            true,
+		"cpu_usage_percent":    0.0,
+		"memory_usage_percent": 0.0,
+	})
+
+	for _, t := range []struct{ name, typ, category, symbol string }{
+		{"VPCS", "vpcs", "guest", ":/symbols/vpcs_guest.svg"},
+		{"Ethernet switch", "ethernet_switch", "switch", ":/symbols/ethernet_switch.svg"},
+		{"Ethernet hub", "ethernet_hub", "switch", ":/symbols/hub.svg"},
+		{"Cloud", "cloud", "guest", ":/symbols/cloud.svg"},
+	} {
+		s.templates.add(object{
+			"name":          t.name,
+			"template_type": t.typ,
+			"category":      t.category,
+			"symbol":        t.symbol,
+			"compute_id":    "local",
+			"builtin":       true,
+		})
+	}
+}
+
+func matchesAny(name, prefixes string) bool {
+	for _, p := range strings.Split(prefixes, ",") {
+		if p == "" {
+			continue
+		}
+		if strings.HasPrefix(name, p) || (strings.HasPrefix(p, ".") && strings.HasSuffix(name, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) newToken(userID string) string {
+	token := strings.ReplaceAll(uuid.NewString()+uuid.NewString(), "-", "")
+	s.tokens[token] = userID
+	return token
+}
+
+type handler func(w http.ResponseWriter, r *http.Request)
+
+// handle registers pattern under /v3 and wraps h with authentication and the
+// server lock. Streaming handlers pass locked=false and lock on their own.
+func (s *Server) handle(pattern string, h handler) {
+	s.register(pattern, h, true, true)
+}
+
+func (s *Server) register(pattern string, h handler, auth, locked bool) {
+	method, path, _ := strings.Cut(pattern, " ")
+	s.mux.HandleFunc(method+" /v3"+path, func(w http.ResponseWriter, r *http.Request) {
+		if auth && !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "Could not validate credentials")
+			return
+		}
+		if locked {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok = s.tokens[token]
+	return ok
+}
+
+func (s *Server) currentUser(r *http.Request) (object, bool) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.users.get(s.tokens[token])
+}
+
+func (s *Server) routes() {
+	s.register("POST /access/users/authenticate", s.authenticate, false, true)
+	s.register("POST /access/users/login", s.login, false, true)
+	s.register("GET /version", s.version, false, false)
+	s.register("GET /notifications", s.notifications, true, false)
+	s.register("GET /projects/{project_id}/notifications", s.projectNotifications, true, false)
+
+	s.accessRoutes()
+	s.projectRoutes()
+
+	for _, p := range []string{"/appliances", "/images", "/symbols"} {
+		s.handle("GET "+p, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, []object{})
+		})
+	}
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Not Found")
+	})
+}
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, object{
+		"version":         s.opts.Version,
+		"controller_host": r.Host,
+		"local":           false,
+	})
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, object{"message": fmt.Sprintf(format, args...)})
+}
+
+func writeNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validationError mimics the FastAPI 422 body the real controller returns.
+func validationError(w http.ResponseWriter, loc []string, msg, typ string) {
+	writeJSON(w, http.StatusUnprocessableEntity, object{
+		"detail": []object{{"loc": loc, "msg": msg, "type": typ}},
+	})
+}
+
+// pathID returns the {name} path value after checking that it is a UUID.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	v := r.PathValue(name)
+	if _, err := uuid.Parse(v); err != nil {
+		validationError(w, []string{"path", name},
+			"Input should be a valid UUID, invalid character: expected an optional prefix of `urn:uuid:` followed by [0-9a-fA-F-]",
+			"uuid_parsing")
+		return "", false
+	}
+	return v, true
+}
+
+func readObject(w http.ResponseWriter, r *http.Request) (object, bool) {
+	obj := object{}
+	if r.Body == nil || r.ContentLength == 0 {
+		return obj, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		validationError(w, []string{"body"}, "JSON decode error: "+err.Error(), "json_invalid")
+		return nil, false
+	}
+	return obj, true
+}
+
+func requireString(w http.ResponseWriter, obj object, field string) (string, bool) {
+	v, _ := obj[field].(string)
+	if v == "" {
+		validationError(w, []string{"body", field}, "Field required", "missing")
+		return "", false
+	}
+	return v, true
+}
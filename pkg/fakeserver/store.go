@@ -0,0 +1,96 @@
+package fakeserver
+
+import (
+	"maps"
+
+	"github.com/google/uuid"
+)
+
+type object = map[string]any
+
+// collection is an insertion-ordered set of JSON objects keyed by idField.
+type collection struct {
+	idField string
+	order   []string
+	items   map[string]object
+}
+
+func newCollection(idField string) *collection {
+	return &collection{idField: idField, items: make(map[string]object)}
+}
+
+func (c *collection) add(obj object) object {
+	id, _ := obj[c.idField].(string)
+	if id == "" {
+		id = uuid.NewString()
+		obj[c.idField] = id
+	}
+	if _, exists := c.items[id]; !exists {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = obj
+	return maps.Clone(obj)
+}
+
+func (c *collection) get(id string) (object, bool) {
+	obj, ok := c.items[id]
+	return obj, ok
+}
+
+func (c *collection) remove(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (c *collection) filter(keep func(object) bool) []object {
+	out := []object{}
+	for _, id := range c.order {
+		obj := c.items[id]
+		if keep == nil || keep(obj) {
+			out = append(out, maps.Clone(obj))
+		}
+	}
+	return out
+}
+
+func (c *collection) list() []object {
+	return c.filter(nil)
+}
+
+func (c *collection) find(field string, value any) (object, bool) {
+	for _, id := range c.order {
+		if obj := c.items[id]; obj[field] == value {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+func (c *collection) removeWhere(match func(object) bool) []string {
+	var removed []string
+	for _, id := range append([]string(nil), c.order...) {
+		if match(c.items[id]) {
+			c.remove(id)
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// merge copies the allowed keys present in patch onto obj.
+func merge(obj, patch object, allowed ...string) {
+	for _, k := range allowed {
+		if v, ok := patch[k]; ok {
+			obj[k] = v
+		}
+	}
+}
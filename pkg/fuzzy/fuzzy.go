@@ -2,6 +2,7 @@ package fuzzy
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/stefanistkuhl/gns3util/pkg/config"
@@ -19,9 +20,9 @@ type FuzzyInfoParams struct {
 	ContextLabel string
 }
 
-func FuzzyInfo(params FuzzyInfoParams) error {
+func FuzzyInfo(ctx context.Context, params FuzzyInfoParams) error {
 	var selected []gjson.Result
-	apiData, vals, err := getValuesForFuzzy(params)
+	apiData, vals, err := getValuesForFuzzy(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -48,6 +49,10 @@ func FuzzyInfo(params FuzzyInfoParams) error {
 
 	toPrint := buf.Bytes()
 
+	if params.Cfg.Output != "" {
+		utils.PrintOutput(params.Cfg, getResourceTypeFromMethod(params.Method), toPrint)
+		return nil
+	}
 	if params.Cfg.Raw {
 		utils.PrintJson(toPrint)
 		return nil
@@ -61,9 +66,9 @@ func FuzzyInfo(params FuzzyInfoParams) error {
 	}
 }
 
-func FuzzyInfoIDs(params FuzzyInfoParams) ([]string, error) {
+func FuzzyInfoIDs(ctx context.Context, params FuzzyInfoParams) ([]string, error) {
 	var selectedIDs []string
-	apiData, vals, err := getValuesForFuzzy(params)
+	apiData, vals, err := getValuesForFuzzy(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -91,10 +96,10 @@ func FuzzyInfoIDs(params FuzzyInfoParams) ([]string, error) {
 	return selectedIDs, nil
 }
 
-func FuzzyInfoIDsWithData(params FuzzyInfoParams) ([]string, []gjson.Result, error) {
+func FuzzyInfoIDsWithData(ctx context.Context, params FuzzyInfoParams) ([]string, []gjson.Result, error) {
 	var selectedIDs []string
 	var selectedData []gjson.Result
-	apiData, vals, err := getValuesForFuzzy(params)
+	apiData, vals, err := getValuesForFuzzy(ctx, params)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -186,8 +191,8 @@ func getResourceTypeFromMethod(method string) string {
 	return "unknown"
 }
 
-func getValuesForFuzzy(params FuzzyInfoParams) ([]gjson.Result, []string, error) {
-	rawData, _, err := utils.CallClient(params.Cfg, params.Method, nil, nil)
+func getValuesForFuzzy(ctx context.Context, params FuzzyInfoParams) ([]gjson.Result, []string, error) {
+	rawData, _, err := utils.CallClient(ctx, params.Cfg, params.Method, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,196 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+)
+
+type UsersService struct {
+	c *Client
+}
+
+func (c *Client) Users() *UsersService {
+	return &UsersService{c: c}
+}
+
+func (s *UsersService) List(ctx context.Context) ([]schemas.UserResponse, error) {
+	return get[[]schemas.UserResponse](ctx, s.c, "users.list", s.c.ep.Get.Users())
+}
+
+func (s *UsersService) Get(ctx context.Context, userID string) (schemas.UserResponse, error) {
+	return get[schemas.UserResponse](ctx, s.c, "users.get", s.c.ep.Get.User(userID))
+}
+
+func (s *UsersService) Me(ctx context.Context) (schemas.UserResponse, error) {
+	return get[schemas.UserResponse](ctx, s.c, "users.me", s.c.ep.Get.Me())
+}
+
+func (s *UsersService) Create(ctx context.Context, data schemas.UserCreate) (schemas.UserResponse, error) {
+	return send[schemas.UserResponse](ctx, s.c, "users.create", api.POST, s.c.ep.Post.CreateUser(), data)
+}
+
+func (s *UsersService) Update(ctx context.Context, userID string, data schemas.UserUpdate) (schemas.UserResponse, error) {
+	return send[schemas.UserResponse](ctx, s.c, "users.update", api.PUT, s.c.ep.Put.UpdateUser(userID), data)
+}
+
+func (s *UsersService) Delete(ctx context.Context, userID string) error {
+	return s.c.do(ctx, "users.delete", api.DELETE, s.c.ep.Delete.DeleteUser(userID), nil, nil)
+}
+
+func (s *UsersService) Groups(ctx context.Context, userID string) ([]schemas.UserGroupResponse, error) {
+	return get[[]schemas.UserGroupResponse](ctx, s.c, "users.groups", s.c.ep.Get.GroupMemberships(userID))
+}
+
+type GroupsService struct {
+	c *Client
+}
+
+func (c *Client) Groups() *GroupsService {
+	return &GroupsService{c: c}
+}
+
+func (s *GroupsService) List(ctx context.Context) ([]schemas.UserGroupResponse, error) {
+	return get[[]schemas.UserGroupResponse](ctx, s.c, "groups.list", s.c.ep.Get.Groups())
+}
+
+func (s *GroupsService) Get(ctx context.Context, groupID string) (schemas.UserGroupResponse, error) {
+	return get[schemas.UserGroupResponse](ctx, s.c, "groups.get", s.c.ep.Get.Group(groupID))
+}
+
+func (s *GroupsService) Create(ctx context.Context, name string) (schemas.UserGroupResponse, error) {
+	return send[schemas.UserGroupResponse](ctx, s.c, "groups.create", api.POST, s.c.ep.Post.CreateGroup(), schemas.UserGroupCreate{Name: &name})
+}
+
+func (s *GroupsService) Update(ctx context.Context, groupID string, data schemas.UserGroupUpdate) (schemas.UserGroupResponse, error) {
+	return send[schemas.UserGroupResponse](ctx, s.c, "groups.update", api.PUT, s.c.ep.Put.UpdateGroup(groupID), data)
+}
+
+func (s *GroupsService) Delete(ctx context.Context, groupID string) error {
+	return s.c.do(ctx, "groups.delete", api.DELETE, s.c.ep.Delete.DeleteGroup(groupID), nil, nil)
+}
+
+func (s *GroupsService) Members(ctx context.Context, groupID string) ([]schemas.UserResponse, error) {
+	return get[[]schemas.UserResponse](ctx, s.c, "groups.members", s.c.ep.Get.GroupMembers(groupID))
+}
+
+func (s *GroupsService) AddMember(ctx context.Context, groupID, userID string) error {
+	return s.c.do(ctx, "groups.add_member", api.PUT, s.c.ep.Put.AddGroupMember(groupID, userID), nil, nil)
+}
+
+func (s *GroupsService) RemoveMember(ctx context.Context, groupID, userID string) error {
+	return s.c.do(ctx, "groups.remove_member", api.DELETE, s.c.ep.Delete.DeleteUserFromGroup(groupID, userID), nil, nil)
+}
+
+type RolesService struct {
+	c *Client
+}
+
+func (c *Client) Roles() *RolesService {
+	return &RolesService{c: c}
+}
+
+func (s *RolesService) List(ctx context.Context) ([]schemas.RoleResponse, error) {
+	return get[[]schemas.RoleResponse](ctx, s.c, "roles.list", s.c.ep.Get.Roles())
+}
+
+func (s *RolesService) Get(ctx context.Context, roleID string) (schemas.RoleResponse, error) {
+	return get[schemas.RoleResponse](ctx, s.c, "roles.get", s.c.ep.Get.Role(roleID))
+}
+
+func (s *RolesService) Create(ctx context.Context, data schemas.RoleCreate) (schemas.RoleResponse, error) {
+	return send[schemas.RoleResponse](ctx, s.c, "roles.create", api.POST, s.c.ep.Post.CreateRole(), data)
+}
+
+func (s *RolesService) Update(ctx context.Context, roleID string, data schemas.RoleUpdate) (schemas.RoleResponse, error) {
+	return send[schemas.RoleResponse](ctx, s.c, "roles.update", api.PUT, s.c.ep.Put.UpdateRole(roleID), data)
+}
+
+func (s *RolesService) Delete(ctx context.Context, roleID string) error {
+	return s.c.do(ctx, "roles.delete", api.DELETE, s.c.ep.Delete.DeleteRole(roleID), nil, nil)
+}
+
+func (s *RolesService) Privileges(ctx context.Context, roleID string) ([]schemas.PrivilegeResponse, error) {
+	return get[[]schemas.PrivilegeResponse](ctx, s.c, "roles.privileges", s.c.ep.Get.RolePrivs(roleID))
+}
+
+func (s *RolesService) AddPrivilege(ctx context.Context, roleID, privilegeID string) error {
+	return s.c.do(ctx, "roles.add_privilege", api.PUT, s.c.ep.Put.AddPrivilege(roleID, privilegeID), nil, nil)
+}
+
+func (s *RolesService) RemovePrivilege(ctx context.Context, roleID, privilegeID string) error {
+	return s.c.do(ctx, "roles.remove_privilege", api.DELETE, s.c.ep.Delete.DeleteRolePrivilege(roleID, privilegeID), nil, nil)
+}
+
+func (c *Client) Privileges(ctx context.Context) ([]schemas.PrivilegeResponse, error) {
+	return get[[]schemas.PrivilegeResponse](ctx, c, "privileges.list", c.ep.Get.GetPrivileges())
+}
+
+type ACLService struct {
+	c *Client
+}
+
+func (c *Client) ACL() *ACLService {
+	return &ACLService{c: c}
+}
+
+func (s *ACLService) List(ctx context.Context) ([]schemas.ACLResponse, error) {
+	return get[[]schemas.ACLResponse](ctx, s.c, "acl.list", s.c.ep.Get.ACL())
+}
+
+func (s *ACLService) Get(ctx context.Context, aceID string) (schemas.ACLResponse, error) {
+	return get[schemas.ACLResponse](ctx, s.c, "acl.get", s.c.ep.Get.ACE(aceID))
+}
+
+func (s *ACLService) Create(ctx context.Context, data schemas.ACECreate) (schemas.ACLResponse, error) {
+	return send[schemas.ACLResponse](ctx, s.c, "acl.create", api.POST, s.c.ep.Post.CreateACL(), data)
+}
+
+func (s *ACLService) Update(ctx context.Context, aceID string, data schemas.ACEUpdate) (schemas.ACLResponse, error) {
+	return send[schemas.ACLResponse](ctx, s.c, "acl.update", api.PUT, s.c.ep.Put.UpdateACE(aceID), data)
+}
+
+func (s *ACLService) Delete(ctx context.Context, aceID string) error {
+	return s.c.do(ctx, "acl.delete", api.DELETE, s.c.ep.Delete.DeleteACE(aceID), nil, nil)
+}
+
+type PoolsService struct {
+	c *Client
+}
+
+func (c *Client) Pools() *PoolsService {
+	return &PoolsService{c: c}
+}
+
+func (s *PoolsService) List(ctx context.Context) ([]schemas.ResourcePoolResponse, error) {
+	return get[[]schemas.ResourcePoolResponse](ctx, s.c, "pools.list", s.c.ep.Get.Pools())
+}
+
+func (s *PoolsService) Get(ctx context.Context, poolID string) (schemas.ResourcePoolResponse, error) {
+	return get[schemas.ResourcePoolResponse](ctx, s.c, "pools.get", s.c.ep.Get.Pool(poolID))
+}
+
+func (s *PoolsService) Create(ctx context.Context, name string) (schemas.ResourcePoolResponse, error) {
+	return send[schemas.ResourcePoolResponse](ctx, s.c, "pools.create", api.POST, s.c.ep.Post.CreatePool(), schemas.ResourcePoolCreate{Name: &name})
+}
+
+func (s *PoolsService) Update(ctx context.Context, poolID string, data schemas.ResourcePoolUpdate) (schemas.ResourcePoolResponse, error) {
+	return send[schemas.ResourcePoolResponse](ctx, s.c, "pools.update", api.PUT, s.c.ep.Put.UpdatePool(poolID), data)
+}
+
+func (s *PoolsService) Delete(ctx context.Context, poolID string) error {
+	return s.c.do(ctx, "pools.delete", api.DELETE, s.c.ep.Delete.DeletePool(poolID), nil, nil)
+}
+
+func (s *PoolsService) Resources(ctx context.Context, poolID string) ([]schemas.ResourceResponse, error) {
+	return get[[]schemas.ResourceResponse](ctx, s.c, "pools.resources", s.c.ep.Get.PoolResources(poolID))
+}
+
+func (s *PoolsService) AddResource(ctx context.Context, poolID, resourceID string) error {
+	return s.c.do(ctx, "pools.add_resource", api.PUT, s.c.ep.Put.AddToPool(poolID, resourceID), nil, nil)
+}
+
+func (s *PoolsService) RemoveResource(ctx context.Context, poolID, resourceID string) error {
+	return s.c.do(ctx, "pools.remove_resource", api.DELETE, s.c.ep.Delete.DeletePoolResource(poolID, resourceID), nil, nil)
+}
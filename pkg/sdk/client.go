@@ -0,0 +1,125 @@
+// Package sdk is a typed client for the GNS3v3 controller API built on top of
+// pkg/api and pkg/api/endpoints. Every method decodes the response into the
+// matching schemas type so callers never have to deal with raw JSON.
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+)
+
+type Client struct {
+	settings api.Settings
+	api      *api.GNS3ApiClient
+	ep       endpoints.Endpoints
+}
+
+// Error is returned by every SDK method. Op names the operation that failed
+// (e.g. "projects.list") and Err holds the underlying transport or decode error.
+type Error struct {
+	Op         string
+	StatusCode int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: status %d: %v", e.Op, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New builds a client from settings. A token renewed by settings.Reauth is
+// used for the following requests.
+func New(settings api.Settings) *Client {
+	c := &Client{settings: settings}
+	if reauth := c.settings.Reauth; reauth != nil {
+		c.settings.Reauth = func(ctx context.Context, expired string) (string, error) {
+			token, err := reauth(ctx, expired)
+			if err == nil {
+				c.settings.Token = token
+			}
+			return token, err
+		}
+	}
+	c.api = api.NewGNS3Client(c.settings)
+	return c
+}
+
+// NewFromConfig builds a client for cfg.Server using the access token stored
+// in the keyfile by "gns3util auth login".
+func NewFromConfig(cfg config.GlobalOptions) (*Client, error) {
+	token, err := authentication.GetKeyForServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.APISettings(cfg, token)
+	return New(settings), nil
+}
+
+func (c *Client) Settings() api.Settings {
+	return c.settings
+}
+
+func (c *Client) do(ctx context.Context, op string, method api.HTTPMethod, path string, in, out any) error {
+	reqOpts := api.NewRequestOptions(c.settings).
+		WithContext(ctx).
+		WithURL(path).
+		WithMethod(method)
+
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return &Error{Op: op, Err: fmt.Errorf("failed to encode request body: %w", err)}
+		}
+		reqOpts = reqOpts.WithData(string(b))
+	}
+
+	body, resp, err := c.api.Do(reqOpts)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		return &Error{Op: op, StatusCode: status, Err: err}
+	}
+
+	if out != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, out); err != nil {
+			return &Error{Op: op, StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to parse response: %w", err)}
+		}
+	}
+	return nil
+}
+
+func get[T any](ctx context.Context, c *Client, op, path string) (T, error) {
+	var out T
+	err := c.do(ctx, op, api.GET, path, nil, &out)
+	return out, err
+}
+
+func send[T any](ctx context.Context, c *Client, op string, method api.HTTPMethod, path string, in any) (T, error) {
+	var out T
+	err := c.do(ctx, op, method, path, in, &out)
+	return out, err
+}
+
+// ServerInfo returns the API detected for the server on first contact.
+func (c *Client) ServerInfo(ctx context.Context) (api.ServerInfo, error) {
+	return c.api.ServerInfo(ctx)
+}
+
+func (c *Client) Version(ctx context.Context) (schemas.Version, error) {
+	return get[schemas.Version](ctx, c, "version", c.ep.Get.Version())
+}
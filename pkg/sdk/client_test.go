@@ -0,0 +1,152 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+)
+
+// login returns the config and a valid token for a fake server.
+func login(t *testing.T) (config.GlobalOptions, string) {
+	t.Helper()
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	t.Cleanup(ts.Close)
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(context.Background(), cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	token, err := authentication.GetKeyForServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, token
+}
+
+func TestClientKeepsRenewedToken(t *testing.T) {
+	cfg, token := login(t)
+	var reauths []string
+	cfg.Reauth = func(ctx context.Context, server, expired string) (string, error) {
+		reauths = append(reauths, expired)
+		return token, nil
+	}
+	c := New(config.APISettings(cfg, "expired"))
+
+	ctx := context.Background()
+	for range 3 {
+		if _, err := c.Projects().List(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(reauths) != 1 || reauths[0] != "expired" {
+		t.Errorf("logged in again for %q, want once for the expired token", reauths)
+	}
+	if c.Settings().Token != token {
+		t.Errorf("Settings().Token = %q, want the renewed token", c.Settings().Token)
+	}
+}
+
+func TestClientReauthFails(t *testing.T) {
+	cfg, _ := login(t)
+	cfg.Reauth = func(ctx context.Context, server, expired string) (string, error) {
+		return "", api.ErrNoCredentials
+	}
+	c := New(config.APISettings(cfg, "expired"))
+	if _, err := c.Projects().List(context.Background()); !api.IsUnauthorized(err) {
+		t.Errorf("List() = %v, want unauthorized", err)
+	}
+	if c.Settings().Token != "expired" {
+		t.Errorf("Settings().Token = %q, want the old token", c.Settings().Token)
+	}
+}
+
+// newTestClient returns a client for srv that sends token, without the
+// version detection of WithBaseURL.
+func newTestClient(srv *httptest.Server, token string) *Client {
+	settings := api.NewSettings(api.WithToken(token))
+	settings.BaseURL = srv.URL
+	settings.Retry = api.RetryPolicy{}
+	return New(settings)
+}
+
+func TestClientSendsToken(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"project_id": "p1", "name": "lab"}]`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv, "t0ken")
+	projects, err := c.Projects().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Name != "lab" {
+		t.Errorf("List() = %+v", projects)
+	}
+	if len(auth) != 1 || auth[0] != "Bearer t0ken" {
+		t.Errorf("Authorization = %q, want Bearer t0ken", auth)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Project ID missing doesn't exist"}`))
+		case "/projects/garbled":
+			_, _ = w.Write([]byte(`not json`))
+		case "/projects":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"detail": [{"loc": ["body", "name"], "msg": "Field required", "type": "missing"}]}`))
+		case "/projects/gone":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	c := newTestClient(srv, "t0ken")
+	ctx := context.Background()
+
+	_, err := c.Projects().Get(ctx, "missing")
+	var sdkErr *Error
+	if !errors.As(err, &sdkErr) || sdkErr.Op != "projects.get" || sdkErr.StatusCode != http.StatusNotFound || !api.IsNotFound(err) {
+		t.Errorf("Get(missing) = %#v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "doesn't exist") {
+		t.Errorf("Get(missing) = %q lacks the server message", err)
+	}
+
+	_, err = c.Projects().Get(ctx, "garbled")
+	if !errors.As(err, &sdkErr) || sdkErr.StatusCode != http.StatusOK || !strings.Contains(err.Error(), "failed to parse response") {
+		t.Errorf("Get(garbled) = %v", err)
+	}
+
+	_, err = c.Projects().Create(ctx, schemas.ProjectCreate{})
+	if !errors.As(err, &sdkErr) || sdkErr.Op != "projects.create" || !api.IsInvalidField(err, "body", "name") {
+		t.Errorf("Create() = %v", err)
+	}
+
+	if err := c.Projects().Delete(ctx, "gone"); err != nil {
+		t.Errorf("Delete() of a 204 = %v", err)
+	}
+
+	srv.Close()
+	_, err = c.Projects().List(ctx)
+	if !errors.As(err, &sdkErr) || sdkErr.Op != "projects.list" || sdkErr.StatusCode != 0 {
+		t.Errorf("List() of a stopped server = %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+)
+
+type LinksService struct {
+	c         *Client
+	projectID string
+}
+
+func (c *Client) Links(projectID string) *LinksService {
+	return &LinksService{c: c, projectID: projectID}
+}
+
+func (s *LinksService) List(ctx context.Context) ([]schemas.LinkResponse, error) {
+	return get[[]schemas.LinkResponse](ctx, s.c, "links.list", s.c.ep.Get.Links(s.projectID))
+}
+
+func (s *LinksService) Get(ctx context.Context, linkID string) (schemas.LinkResponse, error) {
+	return get[schemas.LinkResponse](ctx, s.c, "links.get", s.c.ep.Get.Link(s.projectID, linkID))
+}
+
+func (s *LinksService) Create(ctx context.Context, data schemas.LinkCreate) (schemas.LinkResponse, error) {
+	return send[schemas.LinkResponse](ctx, s.c, "links.create", api.POST, s.c.ep.Post.CreateLink(s.projectID), data)
+}
+
+func (s *LinksService) Update(ctx context.Context, linkID string, data schemas.LinkUpdate) (schemas.LinkResponse, error) {
+	return send[schemas.LinkResponse](ctx, s.c, "links.update", api.PUT, s.c.ep.Put.UpdateLink(s.projectID, linkID), data)
+}
+
+func (s *LinksService) Delete(ctx context.Context, linkID string) error {
+	return s.c.do(ctx, "links.delete", api.DELETE, s.c.ep.Delete.DeleteLink(s.projectID, linkID), nil, nil)
+}
+
+func (s *LinksService) Reset(ctx context.Context, linkID string) error {
+	return s.c.do(ctx, "links.reset", api.POST, s.c.ep.Post.ResetLink(s.projectID, linkID), nil, nil)
+}
+
+type DrawingsService struct {
+	c         *Client
+	projectID string
+}
+
+func (c *Client) Drawings(projectID string) *DrawingsService {
+	return &DrawingsService{c: c, projectID: projectID}
+}
+
+func (s *DrawingsService) List(ctx context.Context) ([]schemas.DrawingResponse, error) {
+	return get[[]schemas.DrawingResponse](ctx, s.c, "drawings.list", s.c.ep.Get.Drawings(s.projectID))
+}
+
+func (s *DrawingsService) Get(ctx context.Context, drawingID string) (schemas.DrawingResponse, error) {
+	return get[schemas.DrawingResponse](ctx, s.c, "drawings.get", s.c.ep.Get.Drawing(s.projectID, drawingID))
+}
+
+func (s *DrawingsService) Create(ctx context.Context, data schemas.DrawingCreate) (schemas.DrawingResponse, error) {
+	return send[schemas.DrawingResponse](ctx, s.c, "drawings.create", api.POST, s.c.ep.Post.CreateDrawing(s.projectID), data)
+}
+
+func (s *DrawingsService) Update(ctx context.Context, drawingID string, data schemas.DrawingUpdate) (schemas.DrawingResponse, error) {
+	return send[schemas.DrawingResponse](ctx, s.c, "drawings.update", api.PUT, s.c.ep.Put.UpdateDrawing(s.projectID, drawingID), data)
+}
+
+func (s *DrawingsService) Delete(ctx context.Context, drawingID string) error {
+	return s.c.do(ctx, "drawings.delete", api.DELETE, s.c.ep.Delete.DeleteDrawing(s.projectID, drawingID), nil, nil)
+}
+
+type SnapshotsService struct {
+	c         *Client
+	projectID string
+}
+
+func (c *Client) Snapshots(projectID string) *SnapshotsService {
+	return &SnapshotsService{c: c, projectID: projectID}
+}
+
+func (s *SnapshotsService) List(ctx context.Context) ([]schemas.SnapshotResponse, error) {
+	return get[[]schemas.SnapshotResponse](ctx, s.c, "snapshots.list", s.c.ep.Get.Snapshots(s.projectID))
+}
+
+func (s *SnapshotsService) Create(ctx context.Context, name string) (schemas.SnapshotResponse, error) {
+	return send[schemas.SnapshotResponse](ctx, s.c, "snapshots.create", api.POST, s.c.ep.Post.CreateSnapshot(s.projectID), schemas.SnapshotCreate{Name: &name})
+}
+
+func (s *SnapshotsService) Delete(ctx context.Context, snapshotID string) error {
+	return s.c.do(ctx, "snapshots.delete", api.DELETE, s.c.ep.Delete.DeleteSnapshot(s.projectID, snapshotID), nil, nil)
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+)
+
+type NodesService struct {
+	c         *Client
+	projectID string
+}
+
+func (c *Client) Nodes(projectID string) *NodesService {
+	return &NodesService{c: c, projectID: projectID}
+}
+
+func (s *NodesService) List(ctx context.Context) ([]schemas.NodeResponse, error) {
+	return get[[]schemas.NodeResponse](ctx, s.c, "nodes.list", s.c.ep.Get.Nodes(s.projectID))
+}
+
+func (s *NodesService) Get(ctx context.Context, nodeID string) (schemas.NodeResponse, error) {
+	return get[schemas.NodeResponse](ctx, s.c, "nodes.get", s.c.ep.Get.Node(s.projectID, nodeID))
+}
+
+func (s *NodesService) Create(ctx context.Context, data schemas.NodeCreate) (schemas.NodeResponse, error) {
+	return send[schemas.NodeResponse](ctx, s.c, "nodes.create", api.POST, s.c.ep.Post.CreateNode(s.projectID), data)
+}
+
+func (s *NodesService) CreateFromTemplate(ctx context.Context, templateID string, data schemas.TemplateUsage) (schemas.NodeResponse, error) {
+	return send[schemas.NodeResponse](ctx, s.c, "nodes.create_from_template", api.POST, s.c.ep.Post.CreateProjectNodeFromTemplate(s.projectID, templateID), data)
+}
+
+func (s *NodesService) Update(ctx context.Context, nodeID string, data schemas.NodeUpdate) (schemas.NodeResponse, error) {
+	return send[schemas.NodeResponse](ctx, s.c, "nodes.update", api.PUT, s.c.ep.Put.UpdateNode(s.projectID, nodeID), data)
+}
+
+func (s *NodesService) Delete(ctx context.Context, nodeID string) error {
+	return s.c.do(ctx, "nodes.delete", api.DELETE, s.c.ep.Delete.DeleteNode(s.projectID, nodeID), nil, nil)
+}
+
+func (s *NodesService) Links(ctx context.Context, nodeID string) ([]schemas.LinkResponse, error) {
+	return get[[]schemas.LinkResponse](ctx, s.c, "nodes.links", s.c.ep.Get.NodeLinks(s.projectID, nodeID))
+}
+
+func (s *NodesService) Start(ctx context.Context, nodeID string) error {
+	return s.c.do(ctx, "nodes.start", api.POST, s.c.ep.Post.StartNode(s.projectID, nodeID), nil, nil)
+}
+
+func (s *NodesService) Stop(ctx context.Context, nodeID string) error {
+	return s.c.do(ctx, "nodes.stop", api.POST, s.c.ep.Post.StopNode(s.projectID, nodeID), nil, nil)
+}
+
+func (s *NodesService) Suspend(ctx context.Context, nodeID string) error {
+	return s.c.do(ctx, "nodes.suspend", api.POST, s.c.ep.Post.SuspendNode(s.projectID, nodeID), nil, nil)
+}
+
+func (s *NodesService) Reload(ctx context.Context, nodeID string) error {
+	return s.c.do(ctx, "nodes.reload", api.POST, s.c.ep.Post.ReloadNode(s.projectID, nodeID), nil, nil)
+}
+
+func (s *NodesService) StartAll(ctx context.Context) error {
+	return s.c.do(ctx, "nodes.start_all", api.POST, s.c.ep.Post.StartNodes(s.projectID), nil, nil)
+}
+
+func (s *NodesService) StopAll(ctx context.Context) error {
+	return s.c.do(ctx, "nodes.stop_all", api.POST, s.c.ep.Post.StopNodes(s.projectID), nil, nil)
+}
@@ -0,0 +1,56 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+)
+
+type ProjectsService struct {
+	c *Client
+}
+
+func (c *Client) Projects() *ProjectsService {
+	return &ProjectsService{c: c}
+}
+
+func (s *ProjectsService) List(ctx context.Context) ([]schemas.ProjectResponse, error) {
+	return get[[]schemas.ProjectResponse](ctx, s.c, "projects.list", s.c.ep.Get.Projects())
+}
+
+func (s *ProjectsService) Get(ctx context.Context, projectID string) (schemas.ProjectResponse, error) {
+	return get[schemas.ProjectResponse](ctx, s.c, "projects.get", s.c.ep.Get.Project(projectID))
+}
+
+func (s *ProjectsService) Create(ctx context.Context, data schemas.ProjectCreate) (schemas.ProjectResponse, error) {
+	return send[schemas.ProjectResponse](ctx, s.c, "projects.create", api.POST, s.c.ep.Post.CreateProject(), data)
+}
+
+func (s *ProjectsService) Update(ctx context.Context, projectID string, data schemas.ProjectUpdate) (schemas.ProjectResponse, error) {
+	return send[schemas.ProjectResponse](ctx, s.c, "projects.update", api.PUT, s.c.ep.Put.UpdateProject(projectID), data)
+}
+
+func (s *ProjectsService) Delete(ctx context.Context, projectID string) error {
+	return s.c.do(ctx, "projects.delete", api.DELETE, s.c.ep.Delete.DeleteProject(projectID), nil, nil)
+}
+
+func (s *ProjectsService) Duplicate(ctx context.Context, projectID string, data schemas.ProjectDuplicate) (schemas.ProjectResponse, error) {
+	return send[schemas.ProjectResponse](ctx, s.c, "projects.duplicate", api.POST, s.c.ep.Post.DuplicateProject(projectID), data)
+}
+
+func (s *ProjectsService) Open(ctx context.Context, projectID string) (schemas.ProjectResponse, error) {
+	return send[schemas.ProjectResponse](ctx, s.c, "projects.open", api.POST, s.c.ep.Post.OpenProject(projectID), nil)
+}
+
+func (s *ProjectsService) Close(ctx context.Context, projectID string) error {
+	return s.c.do(ctx, "projects.close", api.POST, s.c.ep.Post.CloseProject(projectID), nil, nil)
+}
+
+func (s *ProjectsService) Lock(ctx context.Context, projectID string) error {
+	return s.c.do(ctx, "projects.lock", api.POST, s.c.ep.Post.LockProject(projectID), nil, nil)
+}
+
+func (s *ProjectsService) Unlock(ctx context.Context, projectID string) error {
+	return s.c.do(ctx, "projects.unlock", api.POST, s.c.ep.Post.UnlockProject(projectID), nil, nil)
+}
@@ -0,0 +1,52 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+)
+
+type TemplatesService struct {
+	c *Client
+}
+
+func (c *Client) Templates() *TemplatesService {
+	return &TemplatesService{c: c}
+}
+
+func (s *TemplatesService) List(ctx context.Context) ([]schemas.TemplateResponse, error) {
+	return get[[]schemas.TemplateResponse](ctx, s.c, "templates.list", s.c.ep.Get.Templates())
+}
+
+func (s *TemplatesService) Get(ctx context.Context, templateID string) (schemas.TemplateResponse, error) {
+	return get[schemas.TemplateResponse](ctx, s.c, "templates.get", s.c.ep.Get.Template(templateID))
+}
+
+func (s *TemplatesService) Create(ctx context.Context, data schemas.TemplateCreate) (schemas.TemplateResponse, error) {
+	return send[schemas.TemplateResponse](ctx, s.c, "templates.create", api.POST, s.c.ep.Post.CreateTemplate(), data)
+}
+
+func (s *TemplatesService) Delete(ctx context.Context, templateID string) error {
+	return s.c.do(ctx, "templates.delete", api.DELETE, s.c.ep.Delete.DeleteTemplate(templateID), nil, nil)
+}
+
+type ComputesService struct {
+	c *Client
+}
+
+func (c *Client) Computes() *ComputesService {
+	return &ComputesService{c: c}
+}
+
+func (s *ComputesService) List(ctx context.Context) ([]schemas.ComputeResponse, error) {
+	return get[[]schemas.ComputeResponse](ctx, s.c, "computes.list", s.c.ep.Get.Computes())
+}
+
+func (s *ComputesService) Get(ctx context.Context, computeID string) (schemas.ComputeResponse, error) {
+	return get[schemas.ComputeResponse](ctx, s.c, "computes.get", s.c.ep.Get.Compute(computeID))
+}
+
+func (s *ComputesService) Delete(ctx context.Context, computeID string) error {
+	return s.c.do(ctx, "computes.delete", api.DELETE, s.c.ep.Delete.DeleteCompute(computeID), nil, nil)
+}
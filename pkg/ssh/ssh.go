@@ -88,35 +88,11 @@ func (c *SSHClient) ExecuteCommand(command string) (*CommandResult, error) {
 func ConnectWithKeyOrPassword(hostname, username string, port int, customPrivateKeyPath string, verbose bool) (*SSHClient, error) {
 	config := &ssh.ClientConfig{
 		User:            username,
-		Auth:            []ssh.AuthMethod{},
+		Auth:            authMethods(hostname, username, customPrivateKeyPath, verbose),
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 		Timeout:         30 * time.Second,
 	}
 
-	if authMethods := getSSHAgentAuth(); len(authMethods) > 0 {
-		config.Auth = append(config.Auth, authMethods...)
-		if verbose {
-			fmt.Println("Using SSH agent authentication")
-		}
-	}
-
-	keyPaths := getPrivateKeyPaths(customPrivateKeyPath)
-	for _, keyPath := range keyPaths {
-		if authMethod, err := getPrivateKeyAuth(keyPath); err == nil {
-			config.Auth = append(config.Auth, authMethod)
-			if verbose {
-				fmt.Printf("Added private key: %s\n", keyPath)
-			}
-		}
-	}
-
-	config.Auth = append(config.Auth, ssh.PasswordCallback(func() (string, error) {
-		fmt.Printf("Enter password for %s@%s: ", username, hostname)
-		var password string
-		_, _ = fmt.Scanln(&password)
-		return password, nil
-	}))
-
 	address := fmt.Sprintf("%s:%d", hostname, port)
 	client, err := ssh.Dial("tcp", address, config)
 	if err != nil {
@@ -137,6 +113,36 @@ func ConnectWithKeyOrPassword(hostname, username string, port int, customPrivate
 	}, nil
 }
 
+// authMethods tries the SSH agent, then the private keys and finally asks
+// for a password.
+func authMethods(hostname, username, customPrivateKeyPath string, verbose bool) []ssh.AuthMethod {
+	var methods []ssh.AuthMethod
+	if agentAuth := getSSHAgentAuth(); len(agentAuth) > 0 {
+		methods = append(methods, agentAuth...)
+		if verbose {
+			fmt.Println("Using SSH agent authentication")
+		}
+	}
+
+	keyPaths := getPrivateKeyPaths(customPrivateKeyPath)
+	for _, keyPath := range keyPaths {
+		if authMethod, err := getPrivateKeyAuth(keyPath); err == nil {
+			methods = append(methods, authMethod)
+			if verbose {
+				fmt.Printf("Added private key: %s\n", keyPath)
+			}
+		}
+	}
+
+	// The prompt goes to stderr, stdout may be piped into another program.
+	return append(methods, ssh.PasswordCallback(func() (string, error) {
+		fmt.Fprintf(os.Stderr, "Enter password for %s@%s: ", username, hostname)
+		var password string
+		_, _ = fmt.Scanln(&password)
+		return password, nil
+	}))
+}
+
 func (c *SSHClient) ExecuteScript(scriptContent, remotePath string) (bool, error) {
 	createScriptCmd := fmt.Sprintf(`cat > %s << 'SCRIPT_EOF'
 %s
@@ -249,19 +255,12 @@ func getSSHAgentAuth() []ssh.AuthMethod {
 	if err != nil {
 		return nil
 	}
-	defer func() {
-		if err := sshAgent.Close(); err != nil {
-			fmt.Printf("failed to close SSH agent connection: %v", err)
-		}
-	}()
-
+	// The agent signs during the handshake, so the connection has to stay
+	// open past this function.
 	agentClient := agent.NewClient(sshAgent)
 	signers, err := agentClient.Signers()
-	if err != nil {
-		return nil
-	}
-
-	if len(signers) == 0 {
+	if err != nil || len(signers) == 0 {
+		_ = sshAgent.Close()
 		return nil
 	}
 
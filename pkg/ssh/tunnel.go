@@ -0,0 +1,195 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Tunnel opens TCP connections from an SSH server, like ssh -W. It connects
+// on first use and can be shared by concurrent API clients; all connections
+// are multiplexed over one SSH session, which is opened again once it dies.
+// The host key must be in ~/.ssh/known_hosts.
+type Tunnel struct {
+	Host    string
+	User    string
+	Port    int
+	KeyPath string
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// ParseTunnelURL parses ssh://user@host:port, user@host or host. The user
+// defaults to the local one and the port to 22.
+func ParseTunnelURL(raw string) (*Tunnel, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "ssh://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh address %q: %v", raw, err)
+	}
+	if u.Scheme != "ssh" {
+		return nil, fmt.Errorf("invalid ssh address %q: scheme must be ssh", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh address %q: missing host", raw)
+	}
+
+	t := &Tunnel{Host: u.Hostname(), Port: 22, KeyPath: u.Query().Get("key")}
+	if p := u.Port(); p != "" {
+		if t.Port, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("invalid ssh port %q", p)
+		}
+	}
+	t.User = u.User.Username()
+	if t.User == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("no user in ssh address %q: %v", raw, err)
+		}
+		t.User = current.Username
+	}
+	return t, nil
+}
+
+func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	for attempt := 0; ; attempt++ {
+		client, err := t.connect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ssh tunnel: %w", err)
+		}
+		conn, err := client.DialContext(ctx, network, addr)
+		if err == nil {
+			return conn, nil
+		}
+		// The server refusing the forward is final, anything else means the
+		// SSH connection is gone and is opened again once.
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) || ctx.Err() != nil || attempt > 0 {
+			return nil, fmt.Errorf("ssh tunnel via %s: %w", t.Host, err)
+		}
+		t.drop(client)
+	}
+}
+
+// connect returns the SSH connection, opening it if there is none. The lock
+// is not held while dialing so a canceled context returns right away.
+func (t *Tunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		// Another request connected meanwhile.
+		_ = client.Close()
+		return t.client, nil
+	}
+	t.client = client
+	go func() {
+		_ = client.Wait()
+		t.drop(client)
+	}()
+	return client, nil
+}
+
+func (t *Tunnel) dial(ctx context.Context) (*ssh.Client, error) {
+	hostKeys, err := knownHosts()
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            authMethods(t.Host, t.User, t.KeyPath, false),
+		HostKeyCallback: hostKeys,
+	}
+
+	address := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	d := net.Dialer{Timeout: 30 * time.Second}
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	// Closing the connection aborts the handshake when ctx is done.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if !stop() {
+		if err == nil {
+			_ = c.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// knownHosts checks host keys against ~/.ssh/known_hosts. Unknown hosts are
+// rejected, connect once with ssh to add them.
+func knownHosts() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(home, ".ssh", "known_hosts")
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s, connect once with ssh to add the host key: %w", path, err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key of %s is not in %s, connect once with ssh to add it", hostname, path)
+			}
+			return fmt.Errorf("host key of %s does not match %s:%d, the connection may be intercepted", hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+		}
+		return err
+	}, nil
+}
+
+// drop forgets client if it is still the current connection and closes it.
+func (t *Tunnel) drop(client *ssh.Client) {
+	t.mu.Lock()
+	if t.client == client {
+		t.client = nil
+	}
+	t.mu.Unlock()
+	_ = client.Close()
+}
+
+func (t *Tunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	return err
+}
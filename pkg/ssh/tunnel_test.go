@@ -0,0 +1,299 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestParseTunnelURL(t *testing.T) {
+	tests := []struct {
+		raw        string
+		host, user string
+		port       int
+		key        string
+		wantErr    bool
+	}{
+		{raw: "ssh://admin@bastion:2222", host: "bastion", user: "admin", port: 2222},
+		{raw: "admin@bastion", host: "bastion", user: "admin", port: 22},
+		{raw: "ssh://admin@bastion?key=/keys/id", host: "bastion", user: "admin", port: 22, key: "/keys/id"},
+		{raw: "http://admin@bastion", wantErr: true},
+		{raw: "ssh://admin@bastion:ssh", wantErr: true},
+		{raw: "ssh://admin@", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTunnelURL(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTunnelURL(%q) error = %v", tt.raw, err)
+			continue
+		}
+		if err == nil && (got.Host != tt.host || got.User != tt.user || got.Port != tt.port || got.KeyPath != tt.key) {
+			t.Errorf("ParseTunnelURL(%q) = %s@%s:%d key %q, want %s@%s:%d key %q",
+				tt.raw, got.User, got.Host, got.Port, got.KeyPath, tt.user, tt.host, tt.port, tt.key)
+		}
+	}
+}
+
+// sshServer forwards direct-tcpip channels like sshd, clients need no
+// credentials.
+type sshServer struct {
+	addr    string
+	hostKey ssh.PublicKey
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func startSSHServer(t *testing.T) *sshServer {
+	t.Helper()
+	signer := newSigner(t)
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	s := &sshServer{addr: l.Addr().String(), hostKey: signer.PublicKey()}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn, config)
+		}
+	}()
+	t.Cleanup(s.dropConnections)
+	return s
+}
+
+func (s *sshServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChan := range chans {
+		var target struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if newChan.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChan.ExtraData(), &target) != nil {
+			_ = newChan.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		ch, chReqs, err := newChan.Accept()
+		if err != nil {
+			_ = upstream.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chReqs)
+		go func() {
+			_, _ = io.Copy(ch, upstream)
+			_ = ch.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(upstream, ch)
+			_ = upstream.Close()
+		}()
+	}
+}
+
+// dropConnections cuts every SSH connection as a dying server would.
+func (s *sshServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+// writeKnownHosts sets up a home directory whose known_hosts has key for
+// addr, or no known_hosts at all for a nil key.
+func writeKnownHosts(t *testing.T, addr string, key ssh.PublicKey) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if key == nil {
+		return
+	}
+	dir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, key) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "known_hosts"), []byte(line), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tunnelTo(t *testing.T, addr string) *Tunnel {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(addr)
+	p, _ := strconv.Atoi(port)
+	tun := &Tunnel{Host: host, User: "admin", Port: p}
+	t.Cleanup(func() { _ = tun.Close() })
+	return tun
+}
+
+func get(tun *Tunnel, url string) (string, error) {
+	client := &http.Client{Transport: &http.Transport{DialContext: tun.DialContext, DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestTunnelHostKey(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer api.Close()
+	srv := startSSHServer(t)
+
+	tests := []struct {
+		name    string
+		key     ssh.PublicKey
+		wantErr string
+	}{
+		{"known host", srv.hostKey, ""},
+		{"unknown host", newSigner(t).PublicKey(), "does not match"},
+		{"no known_hosts", nil, "connect once with ssh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeKnownHosts(t, srv.addr, tt.key)
+			body, err := get(tunnelTo(t, srv.addr), api.URL)
+			if tt.wantErr == "" {
+				if err != nil || body != "ok" {
+					t.Fatalf("GET through the tunnel = %q, %v", body, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GET through the tunnel error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("host missing from known_hosts", func(t *testing.T) {
+		writeKnownHosts(t, "[other]:22", srv.hostKey)
+		if _, err := get(tunnelTo(t, srv.addr), api.URL); err == nil || !strings.Contains(err.Error(), "is not in") {
+			t.Errorf("GET through the tunnel error = %v, want an unknown host", err)
+		}
+	})
+}
+
+func TestTunnelReconnect(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer api.Close()
+	srv := startSSHServer(t)
+	writeKnownHosts(t, srv.addr, srv.hostKey)
+	tun := tunnelTo(t, srv.addr)
+
+	if _, err := get(tun, api.URL); err != nil {
+		t.Fatal(err)
+	}
+	srv.dropConnections()
+	if body, err := get(tun, api.URL); err != nil || body != "ok" {
+		t.Errorf("GET after the SSH connection died = %q, %v", body, err)
+	}
+}
+
+func TestTunnelCanceled(t *testing.T) {
+	// A server that accepts but never starts the handshake.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+	writeKnownHosts(t, l.Addr().String(), newSigner(t).PublicKey())
+	tun := tunnelTo(t, l.Addr().String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := tun.DialContext(ctx, "tcp", "127.0.0.1:80"); err == nil {
+		t.Fatal("DialContext() succeeded without a handshake")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("DialContext() returned %v after the context ended", d)
+	}
+
+	// The lock is not held while dialing, Close does not wait for it.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_, _ = tun.DialContext(ctx, "tcp", "127.0.0.1:80")
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		_ = tun.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Error("Close() blocked on a dial in progress")
+	}
+	cancel()
+	<-done
+}
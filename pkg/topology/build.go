@@ -0,0 +1,382 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+// Step is a change Build made, like creating node R1. Details are the
+// attributes it changed.
+type Step struct {
+	Action  string
+	Kind    string
+	Name    string
+	Details []string
+}
+
+type builder struct {
+	c         *sdk.Client
+	t         *Topology
+	projectID string
+	templates map[string]schemas.TemplateResponse
+	nodes     map[string]schemas.NodeResponse
+	done      func(Step)
+}
+
+// Build creates or updates the project so it has the nodes, links and
+// drawings of t. Anything that already matches is left alone, so building
+// twice changes nothing the second time. Nodes, links and drawings of the
+// project that are not in t are kept. project overrides t.Name, done is
+// called after each change.
+func Build(ctx context.Context, c *sdk.Client, t *Topology, project string, done func(Step)) error {
+	if project == "" {
+		project = t.Name
+	}
+	if project == "" {
+		return fmt.Errorf("no project name, set name in the topology or use --project")
+	}
+	if done == nil {
+		done = func(Step) {}
+	}
+	b := &builder{
+		c:         c,
+		t:         t,
+		templates: make(map[string]schemas.TemplateResponse),
+		nodes:     make(map[string]schemas.NodeResponse),
+		done:      done,
+	}
+
+	templates, err := c.Templates().List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tmpl := range templates {
+		b.templates[tmpl.Name] = tmpl
+		b.templates[tmpl.TemplateID] = tmpl
+	}
+	for _, n := range t.Nodes {
+		if _, ok := b.templates[n.Template]; n.Template != "" && !ok {
+			return fmt.Errorf("node %s: unknown template %s", n.Name, n.Template)
+		}
+	}
+
+	if err := b.project(ctx, project); err != nil {
+		return err
+	}
+	nodes, err := c.Nodes(b.projectID).List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		b.nodes[n.Name] = n
+	}
+	for _, l := range t.Links {
+		for _, e := range []Endpoint{l.A, l.B} {
+			if _, ok := b.nodes[e.Node]; !ok && !t.hasNode(e.Node) {
+				return fmt.Errorf("link %s: unknown node %s", l, e.Node)
+			}
+		}
+	}
+
+	for _, step := range []func(context.Context) error{b.buildNodes, b.buildLinks, b.buildDrawings} {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Topology) hasNode(name string) bool {
+	for _, n := range t.Nodes {
+		if n.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// project finds the project by name, creating or opening it if needed.
+func (b *builder) project(ctx context.Context, name string) error {
+	projects, err := b.c.Projects().List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p.Name != name {
+			continue
+		}
+		b.projectID = p.ProjectID
+		if p.Status != nil && *p.Status == "closed" {
+			if _, err := b.c.Projects().Open(ctx, p.ProjectID); err != nil {
+				return err
+			}
+			b.done(Step{Action: "open", Kind: "project", Name: name})
+		}
+		return nil
+	}
+	created, err := b.c.Projects().Create(ctx, schemas.ProjectCreate{Name: &name})
+	if err != nil {
+		return err
+	}
+	b.projectID = created.ProjectID
+	b.done(Step{Action: "create", Kind: "project", Name: name})
+	return nil
+}
+
+func (b *builder) buildNodes(ctx context.Context) error {
+	svc := b.c.Nodes(b.projectID)
+	for _, n := range b.t.Nodes {
+		cur, ok := b.nodes[n.Name]
+		if !ok {
+			created, err := b.createNode(ctx, n)
+			if err != nil {
+				return fmt.Errorf("failed to create node %s: %w", n.Name, err)
+			}
+			b.nodes[n.Name] = created
+			var details []string
+			if n.Template != "" {
+				details = append(details, "template: "+n.Template)
+			} else {
+				details = append(details, "node_type: "+n.NodeType)
+			}
+			details = append(details, "position: "+n.Position.String())
+			b.done(Step{Action: "create", Kind: "node", Name: n.Name, Details: details})
+			continue
+		}
+
+		if tmpl, ok := b.templates[n.Template]; ok && cur.TemplateID != nil && *cur.TemplateID != tmpl.TemplateID {
+			return fmt.Errorf("node %s already exists with another template", n.Name)
+		}
+		var data schemas.NodeUpdate
+		var details []string
+		if pos := (Position{X: cur.X, Y: cur.Y}); pos != n.Position {
+			x, y := n.Position.X, n.Position.Y
+			data.X, data.Y = &x, &y
+			details = append(details, fmt.Sprintf("position: %s -> %s", pos, n.Position))
+		}
+		if props := changedProperties(n.Properties, cur.Properties); len(props) > 0 {
+			data.Properties = props
+			for _, k := range sortedKeys(props) {
+				details = append(details, fmt.Sprintf("properties.%s: %s -> %s", k, jsonValue(cur.Properties[k]), jsonValue(props[k])))
+			}
+		}
+		if len(details) == 0 {
+			continue
+		}
+		updated, err := svc.Update(ctx, cur.NodeID, data)
+		if err != nil {
+			return fmt.Errorf("failed to update node %s: %w", n.Name, err)
+		}
+		if updated.NodeID != "" {
+			b.nodes[n.Name] = updated
+		}
+		b.done(Step{Action: "update", Kind: "node", Name: n.Name, Details: details})
+	}
+	return nil
+}
+
+func (b *builder) createNode(ctx context.Context, n Node) (schemas.NodeResponse, error) {
+	svc := b.c.Nodes(b.projectID)
+	x, y, name := n.Position.X, n.Position.Y, n.Name
+	var compute *string
+	if n.Compute != "" {
+		compute = &n.Compute
+	}
+
+	switch {
+	case n.Template != "":
+		created, err := svc.CreateFromTemplate(ctx, b.templates[n.Template].TemplateID, schemas.TemplateUsage{X: &x, Y: &y, Name: &name, ComputeID: compute})
+		if err != nil {
+			return created, err
+		}
+		// The controller may number the name after the template, and
+		// properties can only be set once the node exists.
+		var data schemas.NodeUpdate
+		if created.Name != n.Name {
+			data.Name = &name
+		}
+		if len(n.Properties) > 0 {
+			data.Properties = n.Properties
+		}
+		if data.Name == nil && data.Properties == nil {
+			return created, nil
+		}
+		updated, err := svc.Update(ctx, created.NodeID, data)
+		if err != nil || updated.NodeID == "" {
+			return created, err
+		}
+		return updated, nil
+	case n.NodeType != "":
+		if compute == nil {
+			local := "local"
+			compute = &local
+		}
+		return svc.Create(ctx, schemas.NodeCreate{Name: &name, NodeType: &n.NodeType, ComputeID: compute, X: &x, Y: &y, Properties: n.Properties})
+	}
+	return schemas.NodeResponse{}, fmt.Errorf("set template or node_type")
+}
+
+// changedProperties returns the properties of want that differ from have.
+func changedProperties(want, have map[string]any) map[string]any {
+	changed := make(map[string]any)
+	for k, v := range want {
+		if jsonValue(v) != jsonValue(have[k]) {
+			changed[k] = v
+		}
+	}
+	return changed
+}
+
+// jsonValue returns v as JSON so values decoded from YAML and JSON compare
+// equal.
+func jsonValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// port returns the link end for e. Ports match by name or short name,
+// ignoring case, or by adapter/port numbers.
+func (b *builder) port(e Endpoint) (schemas.LinkNode, error) {
+	node, ok := b.nodes[e.Node]
+	if !ok {
+		return schemas.LinkNode{}, fmt.Errorf("unknown node %s", e.Node)
+	}
+	for _, p := range node.Ports {
+		if strings.EqualFold(p.Name, e.Port) || strings.EqualFold(p.ShortName, e.Port) {
+			return schemas.LinkNode{NodeID: node.NodeID, AdapterNumber: p.AdapterNumber, PortNumber: p.PortNumber}, nil
+		}
+	}
+	if a, p, ok := strings.Cut(e.Port, "/"); ok {
+		adapter, err1 := strconv.Atoi(a)
+		port, err2 := strconv.Atoi(p)
+		if err1 == nil && err2 == nil {
+			return schemas.LinkNode{NodeID: node.NodeID, AdapterNumber: adapter, PortNumber: port}, nil
+		}
+	}
+	return schemas.LinkNode{}, fmt.Errorf("node %s has no port %s", e.Node, e.Port)
+}
+
+func portKey(n schemas.LinkNode) string {
+	return fmt.Sprintf("%s/%d/%d", n.NodeID, n.AdapterNumber, n.PortNumber)
+}
+
+func (b *builder) buildLinks(ctx context.Context) error {
+	svc := b.c.Links(b.projectID)
+	links, err := svc.List(ctx)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]string)
+	for _, l := range links {
+		for _, n := range l.Nodes {
+			used[portKey(n)] = l.LinkID
+		}
+	}
+
+	dryRun := b.c.Settings().DryRun != nil
+	for _, l := range b.t.Links {
+		if dryRun && (len(b.nodes[l.A.Node].Ports) == 0 || len(b.nodes[l.B.Node].Ports) == 0) {
+			// Nodes planned by a dry run have no ports to resolve the link with.
+			b.done(Step{Action: "create", Kind: "link", Name: l.String(), Details: []string{"ports are resolved once the nodes exist"}})
+			continue
+		}
+		a, err := b.port(l.A)
+		if err != nil {
+			return fmt.Errorf("link %s: %w", l, err)
+		}
+		z, err := b.port(l.B)
+		if err != nil {
+			return fmt.Errorf("link %s: %w", l, err)
+		}
+		linkA, okA := used[portKey(a)]
+		linkZ, okZ := used[portKey(z)]
+		if okA && okZ && linkA == linkZ {
+			continue
+		}
+		if okA {
+			return fmt.Errorf("link %s: port %s is already connected", l, l.A)
+		}
+		if okZ {
+			return fmt.Errorf("link %s: port %s is already connected", l, l.B)
+		}
+		created, err := svc.Create(ctx, schemas.LinkCreate{Nodes: []schemas.LinkNode{a, z}})
+		if err != nil {
+			return fmt.Errorf("failed to create link %s: %w", l, err)
+		}
+		used[portKey(a)], used[portKey(z)] = created.LinkID, created.LinkID
+		b.done(Step{Action: "create", Kind: "link", Name: l.String()})
+	}
+	return nil
+}
+
+func (d Drawing) name() string {
+	if d.Text != "" {
+		return strconv.Quote(d.Text)
+	}
+	return "at " + d.Position.String()
+}
+
+// buildDrawings matches drawings by their SVG, only the position and
+// rotation of a matched drawing are updated.
+func (b *builder) buildDrawings(ctx context.Context) error {
+	svc := b.c.Drawings(b.projectID)
+	drawings, err := svc.List(ctx)
+	if err != nil {
+		return err
+	}
+	matched := make(map[string]bool)
+
+	for _, d := range b.t.Drawings {
+		svg := d.svg()
+		x, y, rotation := d.Position.X, d.Position.Y, d.Rotation
+		var cur *schemas.DrawingResponse
+		for i := range drawings {
+			if !matched[drawings[i].DrawingID] && drawings[i].SVG == svg {
+				cur = &drawings[i]
+				break
+			}
+		}
+		if cur == nil {
+			if _, err := svc.Create(ctx, schemas.DrawingCreate{SVG: &svg, X: &x, Y: &y, Rotation: &rotation}); err != nil {
+				return fmt.Errorf("failed to create drawing %s: %w", d.name(), err)
+			}
+			b.done(Step{Action: "create", Kind: "drawing", Name: d.name()})
+			continue
+		}
+		matched[cur.DrawingID] = true
+		var details []string
+		if pos := (Position{X: cur.X, Y: cur.Y}); pos != d.Position {
+			details = append(details, fmt.Sprintf("position: %s -> %s", pos, d.Position))
+		}
+		if cur.Rotation != rotation {
+			details = append(details, fmt.Sprintf("rotation: %d -> %d", cur.Rotation, rotation))
+		}
+		if len(details) == 0 {
+			continue
+		}
+		if _, err := svc.Update(ctx, cur.DrawingID, schemas.DrawingUpdate{X: &x, Y: &y, Rotation: &rotation}); err != nil {
+			return fmt.Errorf("failed to update drawing %s: %w", d.name(), err)
+		}
+		b.done(Step{Action: "update", Kind: "drawing", Name: d.name(), Details: details})
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+// Export returns the nodes, links and drawings of a project as a topology.
+// Nodes refer to their template by name, links use the short port names.
+func Export(ctx context.Context, c *sdk.Client, projectID string) (*Topology, error) {
+	project, err := c.Projects().Get(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := c.Templates().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	templateNames := make(map[string]string)
+	for _, tmpl := range templates {
+		templateNames[tmpl.TemplateID] = tmpl.Name
+	}
+
+	t := &Topology{Name: project.Name, Nodes: []Node{}}
+	nodes, err := c.Nodes(projectID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]schemas.NodeResponse)
+	for _, n := range nodes {
+		byID[n.NodeID] = n
+		node := Node{Name: n.Name, Position: Position{X: n.X, Y: n.Y}, Properties: n.Properties}
+		if n.TemplateID != nil && templateNames[*n.TemplateID] != "" {
+			node.Template = templateNames[*n.TemplateID]
+		} else {
+			node.NodeType = n.NodeType
+		}
+		if n.ComputeID != "local" {
+			node.Compute = n.ComputeID
+		}
+		t.Nodes = append(t.Nodes, node)
+	}
+
+	links, err := c.Links(projectID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		if len(l.Nodes) != 2 {
+			continue
+		}
+		t.Links = append(t.Links, Link{A: endpoint(byID, l.Nodes[0]), B: endpoint(byID, l.Nodes[1])})
+	}
+
+	drawings, err := c.Drawings(projectID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drawings {
+		t.Drawings = append(t.Drawings, Drawing{SVG: d.SVG, Position: Position{X: d.X, Y: d.Y}, Rotation: d.Rotation})
+	}
+	return t, nil
+}
+
+func endpoint(nodes map[string]schemas.NodeResponse, ln schemas.LinkNode) Endpoint {
+	node, ok := nodes[ln.NodeID]
+	if !ok {
+		return Endpoint{Node: ln.NodeID, Port: fmt.Sprintf("%d/%d", ln.AdapterNumber, ln.PortNumber)}
+	}
+	e := Endpoint{Node: node.Name, Port: fmt.Sprintf("%d/%d", ln.AdapterNumber, ln.PortNumber)}
+	for _, p := range node.Ports {
+		if p.AdapterNumber == ln.AdapterNumber && p.PortNumber == ln.PortNumber {
+			if p.ShortName != "" {
+				e.Port = p.ShortName
+			} else if p.Name != "" {
+				e.Port = p.Name
+			}
+			break
+		}
+	}
+	return e
+}
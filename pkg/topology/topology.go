@@ -0,0 +1,212 @@
+// Package topology reads and writes the YAML lab files of project build
+// and project export-topology: nodes by template and position, links
+// between node ports and drawings.
+package topology
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Topology struct {
+	// Name is the project to build, --project overrides it.
+	Name     string    `yaml:"name,omitempty"`
+	Nodes    []Node    `yaml:"nodes"`
+	Links    []Link    `yaml:"links,omitempty"`
+	Drawings []Drawing `yaml:"drawings,omitempty"`
+}
+
+// Node is created from Template, or with NodeType for nodes that have no
+// template. Properties only set the keys they list.
+type Node struct {
+	Name       string         `yaml:"name"`
+	Template   string         `yaml:"template,omitempty"`
+	NodeType   string         `yaml:"node_type,omitempty"`
+	Compute    string         `yaml:"compute,omitempty"`
+	Position   Position       `yaml:"position"`
+	Properties map[string]any `yaml:"properties,omitempty"`
+}
+
+// Position is written as [x, y], {x: 0, y: 0} is read as well.
+type Position struct {
+	X, Y int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d, %d", p.X, p.Y)
+}
+
+func (p *Position) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		var xy struct {
+			X int `yaml:"x"`
+			Y int `yaml:"y"`
+		}
+		if err := value.Decode(&xy); err != nil {
+			return err
+		}
+		*p = Position{X: xy.X, Y: xy.Y}
+		return nil
+	}
+	var xy []int
+	if err := value.Decode(&xy); err != nil || len(xy) != 2 {
+		return fmt.Errorf("line %d: position must be [x, y]", value.Line)
+	}
+	*p = Position{X: xy[0], Y: xy[1]}
+	return nil
+}
+
+func (p Position) MarshalYAML() (any, error) {
+	return &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprint(p.X)},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprint(p.Y)},
+	}}, nil
+}
+
+// Endpoint is a port of a node. Port is the port name or short name
+// (GigabitEthernet0/0 or Gi0/0) or adapter/port numbers like 0/1.
+type Endpoint struct {
+	Node string
+	Port string
+}
+
+func (e Endpoint) String() string {
+	return e.Node + ":" + e.Port
+}
+
+// Link is written as "R1:Gi0/0 -- SW1:e1".
+type Link struct {
+	A, B Endpoint
+}
+
+func (l Link) String() string {
+	return l.A.String() + " -- " + l.B.String()
+}
+
+// ParseLink parses a link of the form "R1:Gi0/0 -- SW1:e1".
+func ParseLink(s string) (Link, error) {
+	a, b, ok := strings.Cut(s, "--")
+	if !ok {
+		return Link{}, fmt.Errorf("invalid link %q, use NODE:PORT -- NODE:PORT", s)
+	}
+	var l Link
+	for i, side := range []string{a, b} {
+		node, port, ok := strings.Cut(strings.TrimSpace(side), ":")
+		if !ok || node == "" || port == "" {
+			return Link{}, fmt.Errorf("invalid link %q, use NODE:PORT -- NODE:PORT", s)
+		}
+		e := Endpoint{Node: node, Port: port}
+		if i == 0 {
+			l.A = e
+		} else {
+			l.B = e
+		}
+	}
+	return l, nil
+}
+
+func (l *Link) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	parsed, err := ParseLink(s)
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+func (l Link) MarshalYAML() (any, error) {
+	return l.String(), nil
+}
+
+// Drawing is an SVG at a position. Text is a shortcut for an SVG with a
+// single line of text.
+type Drawing struct {
+	SVG      string   `yaml:"svg,omitempty"`
+	Text     string   `yaml:"text,omitempty"`
+	Position Position `yaml:"position"`
+	Rotation int      `yaml:"rotation,omitempty"`
+}
+
+// svg returns the SVG of the drawing, built from Text when it has none.
+func (d Drawing) svg() string {
+	if d.SVG != "" || d.Text == "" {
+		return d.SVG
+	}
+	width := 10 * len([]rune(d.Text))
+	return fmt.Sprintf(`<svg width="%d" height="24"><text font-family="TypeWriter" font-size="10.0" font-weight="bold" fill="#000000" fill-opacity="1.0">%s</text></svg>`,
+		width, html.EscapeString(d.Text))
+}
+
+// Load reads and validates the topology file at path.
+func Load(path string) (*Topology, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a topology.
+func Parse(data []byte) (*Topology, error) {
+	var t Topology
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+	return &t, nil
+}
+
+// Marshal returns the topology as YAML.
+func (t *Topology) Marshal() ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(t); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (t *Topology) validate() error {
+	seen := make(map[string]bool)
+	for i, n := range t.Nodes {
+		switch {
+		case n.Name == "":
+			return fmt.Errorf("node %d has no name", i+1)
+		case seen[n.Name]:
+			return fmt.Errorf("node %s is listed twice", n.Name)
+		case n.Template != "" && n.NodeType != "":
+			return fmt.Errorf("node %s: set only one of template and node_type", n.Name)
+		}
+		seen[n.Name] = true
+	}
+	for _, l := range t.Links {
+		if l.A.Node == l.B.Node && l.A.Port == l.B.Port {
+			return fmt.Errorf("link %s connects a port to itself", l)
+		}
+	}
+	for i, d := range t.Drawings {
+		if (d.SVG == "") == (d.Text == "") {
+			return fmt.Errorf("drawing %d: set exactly one of svg and text", i+1)
+		}
+	}
+	return nil
+}
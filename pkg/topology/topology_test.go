@@ -0,0 +1,229 @@
+package topology
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Link
+		wantErr bool
+	}{
+		{"R1:Gi0/0 -- SW1:e1", Link{A: Endpoint{"R1", "Gi0/0"}, B: Endpoint{"SW1", "e1"}}, false},
+		{"  R1:0/1--R2:eth0 ", Link{A: Endpoint{"R1", "0/1"}, B: Endpoint{"R2", "eth0"}}, false},
+		{"R1:Gi0/0 SW1:e1", Link{}, true},
+		{"R1 -- SW1:e1", Link{}, true},
+		{":e0 -- SW1:e1", Link{}, true},
+		{"R1:e0 -- SW1:", Link{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLink(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLink(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	if s := (Link{A: Endpoint{"R1", "Gi0/0"}, B: Endpoint{"SW1", "e1"}}).String(); s != "R1:Gi0/0 -- SW1:e1" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"empty", "", ""},
+		{"valid", "name: lab\nnodes:\n  - name: R1\n    template: VPCS\n    position: [10, -20]\n  - name: SW1\n    node_type: ethernet_switch\n    position: {x: 5, y: 6}\nlinks:\n  - R1:e0 -- SW1:e1\ndrawings:\n  - text: Lab\n    position: [0, 0]\n", ""},
+		{"unknown field", "nodes:\n  - name: R1\n    image: c7200\n", "image"},
+		{"no name", "nodes:\n  - template: VPCS\n", "has no name"},
+		{"duplicate", "nodes:\n  - name: R1\n  - name: R1\n", "listed twice"},
+		{"template and node_type", "nodes:\n  - name: R1\n    template: VPCS\n    node_type: vpcs\n", "only one of template and node_type"},
+		{"self link", "nodes:\n  - name: R1\nlinks:\n  - R1:e0 -- R1:e0\n", "to itself"},
+		{"bad link", "nodes:\n  - name: R1\nlinks:\n  - R1:e0\n", "invalid link"},
+		{"drawing without svg or text", "drawings:\n  - position: [0, 0]\n", "exactly one of svg and text"},
+		{"drawing with svg and text", "drawings:\n  - svg: <svg/>\n    text: Lab\n", "exactly one of svg and text"},
+		{"bad position", "nodes:\n  - name: R1\n    position: [1, 2, 3]\n", "position must be [x, y]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	top, err := Parse([]byte("nodes:\n  - name: SW1\n    position: {x: 5, y: 6}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top.Nodes[0].Position != (Position{X: 5, Y: 6}) {
+		t.Errorf("position = %+v, want {5 6}", top.Nodes[0].Position)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	want := &Topology{
+		Name: "lab",
+		Nodes: []Node{
+			{Name: "R1", Template: "VPCS", Position: Position{X: 10, Y: -20}},
+			{Name: "SW1", NodeType: "ethernet_switch", Position: Position{X: 5, Y: 6}, Properties: map[string]any{"console_type": "none"}},
+		},
+		Links:    []Link{{A: Endpoint{"R1", "e0"}, B: Endpoint{"SW1", "e1"}}},
+		Drawings: []Drawing{{Text: "Lab", Position: Position{X: 1, Y: 2}, Rotation: 90}},
+	}
+	data, err := want.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"position: [10, -20]", "- R1:e0 -- SW1:e1", "rotation: 90"} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("Marshal() does not contain %q:\n%s", line, data)
+		}
+	}
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(Marshal()) = %v\n%s", err, data)
+	}
+	again, err := got.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("round trip changed the topology:\n%s\nwant\n%s", again, data)
+	}
+}
+
+const lab = `
+name: lab
+nodes:
+  - name: PC1
+    template: VPCS
+    position: [-100, 0]
+  - name: SW1
+    node_type: ethernet_switch
+    position: [0, 0]
+links:
+  - PC1:e0 -- SW1:Ethernet1
+drawings:
+  - text: Lab
+    position: [0, -50]
+`
+
+// steps builds t and returns the steps as "action kind name".
+func steps(t *testing.T, c *sdk.Client, top *Topology) []string {
+	t.Helper()
+	var out []string
+	if err := Build(context.Background(), c, top, "", func(s Step) {
+		out = append(out, s.Action+" "+s.Kind+" "+s.Name)
+	}); err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+	defer ts.Close()
+	ctx := context.Background()
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(ctx, cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	c, err := sdk.NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := Parse([]byte(lab))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run plans the whole lab without creating the project.
+	dryCfg := cfg
+	dryCfg.DryRun = api.NewDryRun()
+	dry, err := sdk.NewFromConfig(dryCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"create project lab", "create node PC1", "create node SW1",
+		"create link PC1:e0 -- SW1:Ethernet1", `create drawing "Lab"`,
+	}
+	if got := steps(t, dry, top); !slices.Equal(got, want) {
+		t.Errorf("dry run Build() = %q, want %q", got, want)
+	}
+	if len(dryCfg.DryRun.Planned()) == 0 {
+		t.Error("the dry run planned no requests")
+	}
+	if projects, err := c.Projects().List(ctx); err != nil || len(projects) != 0 {
+		t.Fatalf("the dry run changed the server: %d projects, %v", len(projects), err)
+	}
+
+	if got := steps(t, c, top); !slices.Equal(got, want) {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+	if got := steps(t, c, top); len(got) != 0 {
+		t.Errorf("second Build() = %q, want no steps", got)
+	}
+
+	top.Nodes[0].Position = Position{X: -150, Y: 0}
+	var details []string
+	if err := Build(ctx, c, top, "", func(s Step) { details = append(details, s.Details...) }); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(details, []string{"position: -100, 0 -> -150, 0"}) {
+		t.Errorf("moving PC1 = %q", details)
+	}
+
+	projects, err := c.Projects().List(ctx)
+	if err != nil || len(projects) != 1 {
+		t.Fatalf("projects = %d, %v, want 1", len(projects), err)
+	}
+	exported, err := Export(ctx, c, projects[0].ProjectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Name != "lab" || len(exported.Nodes) != 2 {
+		t.Fatalf("Export() = %+v", exported)
+	}
+	for i, n := range exported.Nodes {
+		if w := top.Nodes[i]; n.Name != w.Name || n.Template != w.Template || n.NodeType != w.NodeType || n.Position != w.Position {
+			t.Errorf("exported node %+v, want %+v", n, w)
+		}
+	}
+	wantLink := Link{A: Endpoint{"PC1", "e0"}, B: Endpoint{"SW1", "e1"}}
+	if len(exported.Links) != 1 || exported.Links[0] != wantLink {
+		t.Errorf("exported links = %v, want [%s]", exported.Links, wantLink)
+	}
+	if len(exported.Drawings) != 1 || exported.Drawings[0].SVG != top.Drawings[0].svg() {
+		t.Errorf("exported drawings = %+v", exported.Drawings)
+	}
+
+	// The export builds the same project again without changes.
+	if got := steps(t, c, exported); len(got) != 0 {
+		t.Errorf("Build() of the export = %q, want no steps", got)
+	}
+}
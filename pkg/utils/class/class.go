@@ -1,6 +1,7 @@
 package class
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/stefanistkuhl/gns3util/pkg/api"
 	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
 	"github.com/stefanistkuhl/gns3util/pkg/cluster/db"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
@@ -68,7 +70,7 @@ func LoadClassFromFile(filePath string) (schemas.Class, error) {
 	return classData, nil
 }
 
-func CreateClass(cfg config.GlobalOptions, clusterID int, classData schemas.Class, insertedNodes []db.NodeDataAll) (bool, error) {
+func CreateClass(ctx context.Context, cfg config.GlobalOptions, clusterID int, classData schemas.Class, insertedNodes []db.NodeDataAll) (bool, error) {
 
 	conn, err := db.InitIfNeeded()
 	if err != nil {
@@ -207,15 +209,15 @@ GROUP BY ga.node_id;
 		}
 	}
 
-	if err := runPlans(cfg, classData, plans); err != nil {
+	if err := runPlans(ctx, cfg, classData, plans); err != nil {
 		return false, fmt.Errorf("failed to run plans: %w", err)
 	}
 
 	return true, nil
 }
 
-func addUserToGroup(cfg config.GlobalOptions, userID, groupID string) error {
-	_, status, err := utils.CallClient(cfg, "addGroupMember", []string{groupID, userID}, nil)
+func addUserToGroup(ctx context.Context, cfg config.GlobalOptions, userID, groupID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "addGroupMember", []string{groupID, userID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to add user to group: %w", err)
 	}
@@ -227,7 +229,7 @@ func addUserToGroup(cfg config.GlobalOptions, userID, groupID string) error {
 	return nil
 }
 
-func DeleteClass(cfg config.GlobalOptions, className string) error {
+func DeleteClass(ctx context.Context, cfg config.GlobalOptions, className string) error {
 	clusterID, err := getClusterIDForServer(cfg)
 
 	var (
@@ -282,7 +284,7 @@ func DeleteClass(cfg config.GlobalOptions, className string) error {
 				defer wg.Done()
 				nodeCfg := cfg
 				nodeCfg.Server = server
-				if err := deleteClassFromAPI(nodeCfg, className); err != nil {
+				if err := deleteClassFromAPI(ctx, nodeCfg, className); err != nil {
 					if errors.Is(err, ErrClassNotFound) {
 						fmt.Printf("%v Class %v not present on %s; skipping.\n",
 							messageUtils.WarningMsg("Warning"),
@@ -311,7 +313,7 @@ func DeleteClass(cfg config.GlobalOptions, className string) error {
 		}
 
 		if len(apiErrors) > 0 {
-			return fmt.Errorf("failed to delete from some nodes: %v", apiErrors)
+			return fmt.Errorf("failed to delete from some nodes: %w", errors.Join(apiErrors...))
 		}
 
 		return nil
@@ -323,7 +325,7 @@ func DeleteClass(cfg config.GlobalOptions, className string) error {
 			messageUtils.Bold(className))
 	}
 
-	if err := deleteClassFromAPI(cfg, className); err != nil {
+	if err := deleteClassFromAPI(ctx, cfg, className); err != nil {
 		if errors.Is(err, ErrClassNotFound) {
 			fmt.Printf("%v Class %v not present on %s; skipping.\n",
 				messageUtils.WarningMsg("Warning"),
@@ -338,8 +340,8 @@ func DeleteClass(cfg config.GlobalOptions, className string) error {
 	return nil
 }
 
-func deleteClassFromAPI(cfg config.GlobalOptions, className string) error {
-	groupsBody, status, err := utils.CallClient(cfg, "getGroups", []string{}, nil)
+func deleteClassFromAPI(ctx context.Context, cfg config.GlobalOptions, className string) error {
+	groupsBody, status, err := utils.CallClient(ctx, cfg, "getGroups", []string{}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get groups: %w", err)
 	}
@@ -367,12 +369,16 @@ func deleteClassFromAPI(cfg config.GlobalOptions, className string) error {
 	allGroupsToDelete := append(studentGroups, classGroups...)
 
 	allUsersToDelete := make(map[string]string)
+	done := newProgress()
 
 	for _, group := range allGroupsToDelete {
+		if ctx.Err() != nil {
+			return done.interrupted(ctx, "class deletion")
+		}
 		groupID := group.UserGroupID.String()
 		groupName := group.Name
 
-		members, err := getGroupMembers(cfg, groupID)
+		members, err := getGroupMembers(ctx, cfg, groupID)
 		if err != nil {
 			fmt.Printf("%v failed to get members for group %v: %v\n",
 				messageUtils.WarningMsgf("failed to get members for group %s", groupName),
@@ -388,12 +394,16 @@ func deleteClassFromAPI(cfg config.GlobalOptions, className string) error {
 	}
 
 	for userID, username := range allUsersToDelete {
-		if err := deleteUser(cfg, userID); err != nil {
+		if ctx.Err() != nil {
+			return done.interrupted(ctx, "class deletion")
+		}
+		if err := deleteUser(ctx, cfg, userID); err != nil {
 			fmt.Printf("%v failed to delete user %v: %v\n",
 				messageUtils.WarningMsgf("failed to delete user %s", username),
 				messageUtils.Bold(username),
 				err)
 		} else {
+			done.add("users deleted")
 			fmt.Printf("%v Deleted user %v\n",
 				messageUtils.SuccessMsg("Deleted user"),
 				messageUtils.Bold(username))
@@ -401,15 +411,19 @@ func deleteClassFromAPI(cfg config.GlobalOptions, className string) error {
 	}
 
 	for _, group := range studentGroups {
+		if ctx.Err() != nil {
+			return done.interrupted(ctx, "class deletion")
+		}
 		groupID := group.UserGroupID.String()
 		groupName := group.Name
 
-		if err := deleteGroup(cfg, groupID); err != nil {
+		if err := deleteGroup(ctx, cfg, groupID); err != nil {
 			fmt.Printf("%v failed to delete student group %v: %v\n",
 				messageUtils.WarningMsgf("failed to delete student group %s", groupName),
 				messageUtils.Bold(groupName),
 				err)
 		} else {
+			done.add("student groups deleted")
 			fmt.Printf("%v Deleted student group %v\n",
 				messageUtils.SuccessMsg("Deleted student group"),
 				messageUtils.Bold(groupName))
@@ -417,15 +431,19 @@ func deleteClassFromAPI(cfg config.GlobalOptions, className string) error {
 	}
 
 	for _, group := range classGroups {
+		if ctx.Err() != nil {
+			return done.interrupted(ctx, "class deletion")
+		}
 		groupID := group.UserGroupID.String()
 		groupName := group.Name
 
-		if err := deleteGroup(cfg, groupID); err != nil {
+		if err := deleteGroup(ctx, cfg, groupID); err != nil {
 			fmt.Printf("%v failed to delete class group %v: %v\n",
 				messageUtils.WarningMsgf("failed to delete class group %s", groupName),
 				messageUtils.Bold(groupName),
 				err)
 		} else {
+			done.add("class groups deleted")
 			fmt.Printf("%v Deleted class group %v\n",
 				messageUtils.SuccessMsg("Deleted class group"),
 				messageUtils.Bold(groupName))
@@ -450,8 +468,8 @@ func findClassAndStudentGroups(groups []schemas.UserGroupResponse, className str
 	return classGroups, studentGroups
 }
 
-func getGroupMembers(cfg config.GlobalOptions, groupID string) ([]schemas.UserResponse, error) {
-	membersBody, status, err := utils.CallClient(cfg, "getGroupMembers", []string{groupID}, nil)
+func getGroupMembers(ctx context.Context, cfg config.GlobalOptions, groupID string) ([]schemas.UserResponse, error) {
+	membersBody, status, err := utils.CallClient(ctx, cfg, "getGroupMembers", []string{groupID}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group members: %w", err)
 	}
@@ -467,8 +485,8 @@ func getGroupMembers(cfg config.GlobalOptions, groupID string) ([]schemas.UserRe
 	return members, nil
 }
 
-func deleteUser(cfg config.GlobalOptions, userID string) error {
-	_, status, err := utils.CallClient(cfg, "deleteUser", []string{userID}, nil)
+func deleteUser(ctx context.Context, cfg config.GlobalOptions, userID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "deleteUser", []string{userID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -478,7 +496,7 @@ func deleteUser(cfg config.GlobalOptions, userID string) error {
 	return nil
 }
 
-func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName string) error {
+func DeleteExercise(ctx context.Context, cfg config.GlobalOptions, exerciseName, className, groupName string) error {
 	dbConn, err := db.InitIfNeeded()
 	if err != nil {
 		fmt.Printf("%v Failed to initialize database: %v\n",
@@ -492,7 +510,7 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 		}()
 	}
 
-	projects, err := getProjectsForExercise(cfg, exerciseName, className, groupName)
+	projects, err := getProjectsForExercise(ctx, cfg, exerciseName, className, groupName)
 	if err != nil {
 		return fmt.Errorf("failed to get projects for exercise: %w", err)
 	}
@@ -517,7 +535,11 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 		len(projects),
 		messageUtils.Bold(exerciseName))
 
+	done := newProgress()
 	for _, project := range projects {
+		if ctx.Err() != nil {
+			return done.interrupted(ctx, "exercise deletion")
+		}
 		projectID := project.ProjectID
 		projectName := project.Name
 
@@ -531,14 +553,14 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 			resolvedExercise = parts[1]
 		}
 
-		if err := closeProject(cfg, projectID); err != nil {
+		if err := closeProject(ctx, cfg, projectID); err != nil {
 			fmt.Printf("%v Failed to close project %s: %v\n",
 				messageUtils.WarningMsg("Warning"),
 				projectName,
 				err)
 		}
 
-		pools, err := getPoolsForProject(cfg, projectID, projectName, resolvedClass, resolvedExercise)
+		pools, err := getPoolsForProject(ctx, cfg, projectID, projectName, resolvedClass, resolvedExercise)
 		if err != nil {
 			fmt.Printf("%v Failed to get pools for exercise %s: %v\n",
 				messageUtils.WarningMsg("Warning"),
@@ -546,7 +568,7 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 				err)
 		} else {
 			for _, pool := range pools {
-				aclsToDelete, collectErr := listACLsForPool(cfg, pool.ResourcePoolID, pool.Name)
+				aclsToDelete, collectErr := listACLsForPool(ctx, cfg, pool.ResourcePoolID, pool.Name)
 				if collectErr != nil {
 					fmt.Printf("%v Failed to enumerate ACLs for pool %s: %v\n",
 						messageUtils.WarningMsg("Warning"),
@@ -568,12 +590,14 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 					messageUtils.Bold(pool.Name))
 
 				for _, aclID := range aclsToDelete {
-					if err := deleteACL(cfg, aclID); err != nil {
+					if err := deleteACL(ctx, cfg, aclID); err != nil {
 						fmt.Printf("%v Failed to delete ACL %s for pool %s: %v\n",
 							messageUtils.WarningMsg("Warning"),
 							aclID,
 							pool.Name,
 							err)
+					} else {
+						done.add("ACLs deleted")
 					}
 				}
 
@@ -584,12 +608,13 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 						messageUtils.Bold(pool.Name))
 				}
 
-				if err := deletePool(cfg, pool.ResourcePoolID); err != nil {
+				if err := deletePool(ctx, cfg, pool.ResourcePoolID); err != nil {
 					fmt.Printf("%v Failed to delete pool %s: %v\n",
 						messageUtils.WarningMsg("Warning"),
 						pool.Name,
 						err)
 				} else {
+					done.add("pools deleted")
 					fmt.Printf("%v Deleted pool %s\n",
 						messageUtils.SuccessMsg("Success"),
 						messageUtils.Bold(pool.Name))
@@ -597,12 +622,13 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 			}
 		}
 
-		if err := deleteProject(cfg, projectID); err != nil {
+		if err := deleteProject(ctx, cfg, projectID); err != nil {
 			fmt.Printf("%v Failed to delete project %s: %v\n",
 				messageUtils.WarningMsg("Warning"),
 				projectName,
 				err)
 		} else {
+			done.add("projects deleted")
 			fmt.Printf("%v Deleted project %s\n",
 				messageUtils.SuccessMsg("Success"),
 				messageUtils.Bold(projectName))
@@ -622,6 +648,10 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 		}
 	}
 
+	if ctx.Err() != nil {
+		return done.interrupted(ctx, "exercise deletion")
+	}
+
 	if dbConn != nil {
 		_, err := dbConn.Exec(`
 			DELETE FROM exercises 
@@ -641,7 +671,7 @@ func DeleteExercise(cfg config.GlobalOptions, exerciseName, className, groupName
 	return nil
 }
 
-func getProjectsForExercise(cfg config.GlobalOptions, exerciseName, className, groupName string) ([]schemas.ProjectResponse, error) {
+func getProjectsForExercise(ctx context.Context, cfg config.GlobalOptions, exerciseName, className, groupName string) ([]schemas.ProjectResponse, error) {
 	if exerciseName == "" {
 		return nil, fmt.Errorf("exercise name cannot be empty")
 	}
@@ -705,7 +735,7 @@ func getProjectsForExercise(cfg config.GlobalOptions, exerciseName, className, g
 			}
 
 			if len(validProjects) > 0 {
-				projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+				projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get projects from API: %w", err)
 				}
@@ -743,7 +773,7 @@ func getProjectsForExercise(cfg config.GlobalOptions, exerciseName, className, g
 		messageUtils.Bold(className),
 		messageUtils.Bold(exerciseName))
 
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects from API: %w", err)
 	}
@@ -786,7 +816,7 @@ func getProjectsForExercise(cfg config.GlobalOptions, exerciseName, className, g
 	return matchingProjects, nil
 }
 
-func DeleteAllExercisesForClass(cfg config.GlobalOptions, className string) error {
+func DeleteAllExercisesForClass(ctx context.Context, cfg config.GlobalOptions, className string) error {
 	dbConn, err := db.InitIfNeeded()
 	if err != nil {
 		fmt.Printf("%v Failed to initialize database: %v\n",
@@ -814,7 +844,7 @@ func DeleteAllExercisesForClass(cfg config.GlobalOptions, className string) erro
 	}
 
 	if len(nodeServers) == 0 {
-		return deleteAllExercisesForClassOnNode(cfg, className)
+		return deleteAllExercisesForClassOnNode(ctx, cfg, className)
 	}
 
 	var wg sync.WaitGroup
@@ -825,7 +855,7 @@ func DeleteAllExercisesForClass(cfg config.GlobalOptions, className string) erro
 			defer wg.Done()
 			nodeCfg := cfg
 			nodeCfg.Server = server
-			if e := deleteAllExercisesForClassOnNode(nodeCfg, className); e != nil {
+			if e := deleteAllExercisesForClassOnNode(ctx, nodeCfg, className); e != nil {
 				errCh <- fmt.Errorf("%s: %w", server, e)
 			}
 		}(srv)
@@ -854,7 +884,7 @@ func DeleteAllExercisesForClass(cfg config.GlobalOptions, className string) erro
 	return nil
 }
 
-func deleteAllExercisesForClassOnNode(cfg config.GlobalOptions, className string) error {
+func deleteAllExercisesForClassOnNode(ctx context.Context, cfg config.GlobalOptions, className string) error {
 	dbConn, err := db.InitIfNeeded()
 	if err == nil {
 		defer func() {
@@ -881,7 +911,7 @@ func deleteAllExercisesForClassOnNode(cfg config.GlobalOptions, className string
 			err)
 	}
 
-	projectsBody, status, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+	projectsBody, status, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -923,8 +953,16 @@ func deleteAllExercisesForClassOnNode(cfg config.GlobalOptions, className string
 	deleted := 0
 	errorCount := 0
 	for _, exerciseName := range classExercises {
-		err := DeleteExercise(cfg, exerciseName, className, "")
+		if ctx.Err() != nil {
+			return fmt.Errorf("deleting exercises for class %s interrupted after %d/%d exercises: %w",
+				className, deleted, len(classExercises), ctx.Err())
+		}
+		err := DeleteExercise(ctx, cfg, exerciseName, className, "")
 		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return fmt.Errorf("deleting exercises for class %s interrupted after %d/%d exercises: %w",
+					className, deleted, len(classExercises), err)
+			}
 			if errors.Is(err, ErrExerciseNotFound) {
 				fmt.Printf("%v Exercise %v not present on %s; skipping.\n",
 					messageUtils.WarningMsg("Warning"),
@@ -959,11 +997,11 @@ func deleteAllExercisesForClassOnNode(cfg config.GlobalOptions, className string
 	return nil
 }
 
-func closeProject(cfg config.GlobalOptions, projectID string) error {
-	_, status, err := utils.CallClient(cfg, "closeProject", []string{projectID}, nil)
+func closeProject(ctx context.Context, cfg config.GlobalOptions, projectID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "closeProject", []string{projectID}, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "UUID") || strings.Contains(err.Error(), "uuid_parsing") {
-			projectsBody, _, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+		if api.IsInvalidField(err, "path", "project_id") {
+			projectsBody, _, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 			if err != nil {
 				return fmt.Errorf("failed to get projects: %w", err)
 			}
@@ -975,7 +1013,7 @@ func closeProject(cfg config.GlobalOptions, projectID string) error {
 
 			for _, p := range projects {
 				if p.Name == projectID {
-					_, status, err = utils.CallClient(cfg, "closeProject", []string{p.ProjectID}, nil)
+					_, status, err = utils.CallClient(ctx, cfg, "closeProject", []string{p.ProjectID}, nil)
 					if err != nil && status != 404 {
 						return fmt.Errorf("failed to close project %s: %w", p.ProjectID, err)
 					}
@@ -993,8 +1031,8 @@ func closeProject(cfg config.GlobalOptions, projectID string) error {
 	return nil
 }
 
-func getPoolsForProject(cfg config.GlobalOptions, projectID, projectName, className, exerciseName string) ([]schemas.ResourcePoolResponse, error) {
-	poolsBody, status, err := utils.CallClient(cfg, "getPools", []string{}, nil)
+func getPoolsForProject(ctx context.Context, cfg config.GlobalOptions, projectID, projectName, className, exerciseName string) ([]schemas.ResourcePoolResponse, error) {
+	poolsBody, status, err := utils.CallClient(ctx, cfg, "getPools", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pools: %w", err)
 	}
@@ -1019,7 +1057,7 @@ func getPoolsForProject(cfg config.GlobalOptions, projectID, projectName, classN
 			continue
 		}
 
-		contains, cerr := poolContainsProject(cfg, pool.ResourcePoolID, projectID)
+		contains, cerr := poolContainsProject(ctx, cfg, pool.ResourcePoolID, projectID)
 		if cerr != nil {
 			fmt.Printf("%v Failed to inspect pool %s for project membership: %v\n",
 				messageUtils.WarningMsg("Warning"),
@@ -1042,8 +1080,8 @@ func getPoolsForProject(cfg config.GlobalOptions, projectID, projectName, classN
 	return matchingPools, nil
 }
 
-func poolContainsProject(cfg config.GlobalOptions, poolID, projectID string) (bool, error) {
-	body, status, err := utils.CallClient(cfg, "getPoolResources", []string{poolID}, nil)
+func poolContainsProject(ctx context.Context, cfg config.GlobalOptions, poolID, projectID string) (bool, error) {
+	body, status, err := utils.CallClient(ctx, cfg, "getPoolResources", []string{poolID}, nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to get pool resources: %w", err)
 	}
@@ -1067,8 +1105,8 @@ func poolContainsProject(cfg config.GlobalOptions, poolID, projectID string) (bo
 	return false, nil
 }
 
-func listACLsForPool(cfg config.GlobalOptions, poolID, poolName string) ([]string, error) {
-	aclsBody, status, err := utils.CallClient(cfg, "getAcl", []string{}, nil)
+func listACLsForPool(ctx context.Context, cfg config.GlobalOptions, poolID, poolName string) ([]string, error) {
+	aclsBody, status, err := utils.CallClient(ctx, cfg, "getAcl", []string{}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ACLs: %w", err)
 	}
@@ -1151,8 +1189,8 @@ func listACLsForPool(cfg config.GlobalOptions, poolID, poolName string) ([]strin
 	return matched, nil
 }
 
-func deletePool(cfg config.GlobalOptions, poolID string) error {
-	_, status, err := utils.CallClient(cfg, "deletePool", []string{poolID}, nil)
+func deletePool(ctx context.Context, cfg config.GlobalOptions, poolID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "deletePool", []string{poolID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete pool: %w", err)
 	}
@@ -1162,8 +1200,8 @@ func deletePool(cfg config.GlobalOptions, poolID string) error {
 	return nil
 }
 
-func deleteGroup(cfg config.GlobalOptions, groupID string) error {
-	_, status, err := utils.CallClient(cfg, "deleteGroup", []string{groupID}, nil)
+func deleteGroup(ctx context.Context, cfg config.GlobalOptions, groupID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "deleteGroup", []string{groupID}, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete group: %w", err)
 	}
@@ -1173,16 +1211,16 @@ func deleteGroup(cfg config.GlobalOptions, groupID string) error {
 	return nil
 }
 
-func deleteProject(cfg config.GlobalOptions, projectID string) error {
-	_, status, err := utils.CallClient(cfg, "closeProject", []string{projectID}, nil)
+func deleteProject(ctx context.Context, cfg config.GlobalOptions, projectID string) error {
+	_, status, err := utils.CallClient(ctx, cfg, "closeProject", []string{projectID}, nil)
 	if err != nil && status != 404 {
 		return fmt.Errorf("failed to close project: %w", err)
 	}
 
-	_, status, err = utils.CallClient(cfg, "deleteProject", []string{projectID}, nil)
+	_, status, err = utils.CallClient(ctx, cfg, "deleteProject", []string{projectID}, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "UUID") || strings.Contains(err.Error(), "uuid_parsing") {
-			projectsBody, _, err := utils.CallClient(cfg, "getProjects", []string{}, nil)
+		if api.IsInvalidField(err, "path", "project_id") {
+			projectsBody, _, err := utils.CallClient(ctx, cfg, "getProjects", []string{}, nil)
 			if err != nil {
 				return fmt.Errorf("failed to get projects: %w", err)
 			}
@@ -1194,12 +1232,12 @@ func deleteProject(cfg config.GlobalOptions, projectID string) error {
 
 			for _, p := range projects {
 				if p.Name == projectID {
-					_, status, err = utils.CallClient(cfg, "closeProject", []string{p.ProjectID}, nil)
+					_, status, err = utils.CallClient(ctx, cfg, "closeProject", []string{p.ProjectID}, nil)
 					if err != nil && status != 404 {
 						return fmt.Errorf("failed to close project %s: %w", p.ProjectID, err)
 					}
 
-					_, _, err = utils.CallClient(cfg, "deleteProject", []string{p.ProjectID}, nil)
+					_, _, err = utils.CallClient(ctx, cfg, "deleteProject", []string{p.ProjectID}, nil)
 					if err != nil {
 						return fmt.Errorf("failed to delete project %s: %w", p.ProjectID, err)
 					}
@@ -1217,7 +1255,7 @@ func deleteProject(cfg config.GlobalOptions, projectID string) error {
 	return nil
 }
 
-func deleteACL(cfg config.GlobalOptions, aclID string) error {
+func deleteACL(ctx context.Context, cfg config.GlobalOptions, aclID string) error {
 	cleanID := strings.TrimSpace(aclID)
 	if cleanID == "" {
 		return fmt.Errorf("empty ACL id")
@@ -1229,7 +1267,7 @@ func deleteACL(cfg config.GlobalOptions, aclID string) error {
 		for _, suffix := range []string{"", "/"} {
 			candidate := strings.TrimSuffix(id, "/") + suffix
 
-			_, status, err := utils.CallClient(cfg, "deleteACE", []string{candidate}, nil)
+			_, status, err := utils.CallClient(ctx, cfg, "deleteACE", []string{candidate}, nil)
 			if err != nil {
 				lastErr := fmt.Errorf("failed to delete ACL %s: %w", candidate, err)
 				if suffix == "/" || id == variants[len(variants)-1] {
@@ -1432,7 +1470,7 @@ func distributeGroupsWithMode(nodes []db.NodeDataAll, classData schemas.Class, r
 	return result, nil
 }
 
-func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.NodeGroupsForClass) error {
+func runPlans(ctx context.Context, cfg config.GlobalOptions, classData schemas.Class, plans []db.NodeGroupsForClass) error {
 
 	// 1. create class group on all nodes
 
@@ -1444,6 +1482,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 		Name: &classData.Name,
 	}
 
+	done := newProgress()
 	var wg sync.WaitGroup
 
 	errChan := make(chan error, len(plans))
@@ -1454,7 +1493,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 			nodeCfg := cfg
 			nodeCfg.Server = plan.NodeURL
 
-			classGroupBody, status, err := utils.CallClient(nodeCfg, "createGroup", []string{}, classGroupData)
+			classGroupBody, status, err := utils.CallClient(ctx, nodeCfg, "createGroup", []string{}, classGroupData)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to create class group: %w", err)
 				return
@@ -1473,6 +1512,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 
 			classGroupName := classGroupResponse.Name
 
+			done.add("class groups created")
 			fmt.Printf("%v Created class group %v\n",
 				messageUtils.SuccessMsg("Created class group"),
 				messageUtils.Bold(classGroupName))
@@ -1481,6 +1521,9 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 	}
 	wg.Wait()
 	close(errChan)
+	if ctx.Err() != nil {
+		return done.interrupted(ctx, "class creation")
+	}
 	for err := range errChan {
 		if err != nil {
 			return err
@@ -1499,13 +1542,16 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 			nodeCfg := cfg
 			nodeCfg.Server = plan.NodeURL
 			for _, group := range plan.Groups {
+				if ctx.Err() != nil {
+					return
+				}
 				if group.Name == classData.Name {
 					continue
 				}
 				sgData := schemas.UserGroupCreate{
 					Name: &group.Name,
 				}
-				studentGroupBody, status, err := utils.CallClient(nodeCfg, "createGroup", []string{}, sgData)
+				studentGroupBody, status, err := utils.CallClient(ctx, nodeCfg, "createGroup", []string{}, sgData)
 				if err != nil {
 					errchan2 <- fmt.Errorf("failed to create student group %s: %w", group.Name, err)
 					return
@@ -1524,6 +1570,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 
 				studentGroupName := studentGroupResponse.Name
 
+				done.add("student groups created")
 				fmt.Printf("%v Created student group %v\n",
 					messageUtils.SuccessMsg("Created student group"),
 					messageUtils.Bold(studentGroupName))
@@ -1534,6 +1581,9 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 	}
 	wg2.Wait()
 	close(errchan2)
+	if ctx.Err() != nil {
+		return done.interrupted(ctx, "class creation")
+	}
 	for err := range errchan2 {
 		if err != nil {
 			return err
@@ -1555,6 +1605,9 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 			// Create users and add them to groups
 			for _, group := range plan.Groups {
 				for _, user := range group.Students {
+					if ctx.Err() != nil {
+						return
+					}
 					userData := schemas.UserCreate{
 						Username: &user.Username,
 						Password: &user.Password,
@@ -1562,7 +1615,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 						FullName: &user.FullName,
 					}
 
-					userBody, status, err := utils.CallClient(nodeCfg, "createUser", []string{}, userData)
+					userBody, status, err := utils.CallClient(ctx, nodeCfg, "createUser", []string{}, userData)
 					if err != nil {
 						errchan3 <- fmt.Errorf("failed to create user %s: %w", user.Username, err)
 						return
@@ -1587,7 +1640,7 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 						messageUtils.Bold(username))
 
 					// Get group IDs for class and student groups
-					groupsBody, status, err := utils.CallClient(nodeCfg, "getGroups", []string{}, nil)
+					groupsBody, status, err := utils.CallClient(ctx, nodeCfg, "getGroups", []string{}, nil)
 					if err != nil {
 						errchan3 <- fmt.Errorf("failed to get groups: %w", err)
 						return
@@ -1613,21 +1666,25 @@ func runPlans(cfg config.GlobalOptions, classData schemas.Class, plans []db.Node
 						}
 					}
 
-					if err := addUserToGroup(nodeCfg, userID, classGroupID); err != nil {
+					if err := addUserToGroup(ctx, nodeCfg, userID, classGroupID); err != nil {
 						errchan3 <- fmt.Errorf("failed to add user %s to class group: %w", username, err)
 						return
 					}
 
-					if err := addUserToGroup(nodeCfg, userID, studentGroupID); err != nil {
+					if err := addUserToGroup(ctx, nodeCfg, userID, studentGroupID); err != nil {
 						errchan3 <- fmt.Errorf("failed to add user %s to student group: %w", username, err)
 						return
 					}
+					done.add("users created")
 				}
 			}
 		}(plan)
 	}
 	wg3.Wait()
 	close(errchan3)
+	if ctx.Err() != nil {
+		return done.interrupted(ctx, "class creation")
+	}
 	for err := range errchan3 {
 		if err != nil {
 			return err
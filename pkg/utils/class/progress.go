@@ -0,0 +1,46 @@
+package class
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// progress counts the steps a bulk operation finished so an interrupted run
+// can report how far it got.
+type progress struct {
+	mu     sync.Mutex
+	order  []string
+	counts map[string]int
+}
+
+func newProgress() *progress {
+	return &progress{counts: make(map[string]int)}
+}
+
+func (p *progress) add(step string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.counts[step]; !ok {
+		p.order = append(p.order, step)
+	}
+	p.counts[step]++
+}
+
+func (p *progress) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.order) == 0 {
+		return "nothing completed"
+	}
+	parts := make([]string, 0, len(p.order))
+	for _, step := range p.order {
+		parts = append(parts, fmt.Sprintf("%s: %d", step, p.counts[step]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *progress) interrupted(ctx context.Context, op string) error {
+	return fmt.Errorf("%s interrupted (%s): %w", op, p, ctx.Err())
+}
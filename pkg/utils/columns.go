@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// resourceColumns are the -o table columns of a resource and the ones -o
+// wide adds.
+type resourceColumns struct {
+	table []Column[gjson.Result]
+	wide  []Column[gjson.Result]
+}
+
+func field(header, path string) Column[gjson.Result] {
+	return Column[gjson.Result]{Header: header, Value: func(item gjson.Result) string {
+		return cellValue(item.Get(path))
+	}}
+}
+
+func cellValue(v gjson.Result) string {
+	switch {
+	case !v.Exists() || v.Type == gjson.Null:
+		return ""
+	case v.IsArray():
+		values := make([]string, 0, len(v.Array()))
+		for _, e := range v.Array() {
+			values = append(values, cellValue(e))
+		}
+		return strings.Join(values, ",")
+	case v.IsObject():
+		return v.Raw
+	}
+	return v.String()
+}
+
+var defaultColumns = map[string]resourceColumns{
+	"project": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "project_id"), field("Status", "status")},
+		wide:  []Column[gjson.Result]{field("Auto Start", "auto_start"), field("Auto Close", "auto_close"), field("Path", "path")},
+	},
+	"node": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "node_id"), field("Type", "node_type"), field("Status", "status"), field("Console", "console")},
+		wide:  []Column[gjson.Result]{field("Console Type", "console_type"), field("Console Host", "console_host"), field("Compute", "compute_id"), field("Template", "template_id")},
+	},
+	"link": {
+		table: []Column[gjson.Result]{field("ID", "link_id"), field("Type", "link_type"), field("Ports", "nodes.#.label.text"), field("Suspended", "suspend")},
+		wide:  []Column[gjson.Result]{field("Nodes", "nodes.#.node_id"), field("Capturing", "capturing"), field("Capture File", "capture_file_name")},
+	},
+	"compute": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "compute_id"), field("Host", "host"), field("Port", "port"), field("Connected", "connected")},
+		wide:  []Column[gjson.Result]{field("Protocol", "protocol"), field("User", "user"), field("CPU %", "cpu_usage_percent"), field("Memory %", "memory_usage_percent")},
+	},
+	"user": {
+		table: []Column[gjson.Result]{field("Username", "username"), field("ID", "user_id"), field("Full Name", "full_name"), field("Email", "email"), field("Active", "is_active")},
+		wide:  []Column[gjson.Result]{field("Superadmin", "is_superadmin"), field("Last Login", "last_login"), field("Created", "created_at")},
+	},
+	"group": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "user_group_id"), field("Builtin", "is_builtin")},
+		wide:  []Column[gjson.Result]{field("Created", "created_at"), field("Updated", "updated_at")},
+	},
+	"role": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "role_id"), field("Builtin", "is_builtin"), field("Description", "description")},
+		wide:  []Column[gjson.Result]{field("Created", "created_at"), field("Updated", "updated_at")},
+	},
+	"privilege": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "privilege_id"), field("Description", "description")},
+	},
+	"acl-rule": {
+		table: []Column[gjson.Result]{field("ID", "ace_id"), field("Path", "path"), field("Type", "ace_type"), field("Allowed", "allowed"), field("Propagate", "propagate")},
+		wide:  []Column[gjson.Result]{field("User", "user_id"), field("Group", "group_id"), field("Role", "role_id")},
+	},
+	"template": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "template_id"), field("Type", "template_type"), field("Category", "category")},
+		wide:  []Column[gjson.Result]{field("Compute", "compute_id"), field("Builtin", "builtin"), field("Symbol", "symbol")},
+	},
+	"appliance": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "appliance_id"), field("Category", "category"), field("Vendor", "vendor_name")},
+		wide:  []Column[gjson.Result]{field("Status", "status"), field("Maintainer", "maintainer")},
+	},
+	"pool": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "resource_pool_id")},
+		wide:  []Column[gjson.Result]{field("Created", "created_at"), field("Updated", "updated_at")},
+	},
+	"image": {
+		table: []Column[gjson.Result]{field("Filename", "filename"), field("Type", "image_type"), field("Size", "image_size")},
+		wide:  []Column[gjson.Result]{field("Path", "path"), field("Checksum", "checksum")},
+	},
+	"drawing": {
+		table: []Column[gjson.Result]{field("ID", "drawing_id"), field("X", "x"), field("Y", "y"), field("Z", "z")},
+		wide:  []Column[gjson.Result]{field("Rotation", "rotation"), field("Locked", "locked")},
+	},
+	"snapshot": {
+		table: []Column[gjson.Result]{field("Name", "name"), field("ID", "snapshot_id"), field("Created", "created_at")},
+	},
+	"symbol": {
+		table: []Column[gjson.Result]{field("Filename", "filename"), field("ID", "symbol_id"), field("Builtin", "builtin")},
+		wide:  []Column[gjson.Result]{field("Theme", "theme")},
+	},
+	"class": {
+		table: []Column[gjson.Result]{field("Class Name", "name"), field("Description", "description"), field("Nodes", "nodes.#"), field("Groups", "groups"), field("Users", "users")},
+		wide:  []Column[gjson.Result]{field("Node URLs", "nodes.#.node")},
+	},
+	"cluster": {
+		table: []Column[gjson.Result]{field("ID", "Id"), field("Name", "Name"), field("Desc", "Desc.String")},
+	},
+	"exercise": {
+		table: []Column[gjson.Result]{field("Node", "node"), field("Exercises", "exercises"), field("Projects", "projects"), field("Groups", "groups")},
+	},
+}
+
+// tableColumns returns the columns for items of resource. Resources without
+// default columns show their scalar fields, all of them for wide. empty
+// replaces missing values.
+func tableColumns(resource string, items []gjson.Result, wide bool, empty string) []Column[gjson.Result] {
+	var columns []Column[gjson.Result]
+	if rc, ok := defaultColumns[resource]; ok {
+		columns = append(columns, rc.table...)
+		if wide {
+			columns = append(columns, rc.wide...)
+		}
+	} else {
+		columns = scalarColumns(items, wide)
+	}
+	if empty == "" {
+		return columns
+	}
+	withEmpty := make([]Column[gjson.Result], len(columns))
+	for i, col := range columns {
+		value := col.Value
+		withEmpty[i] = Column[gjson.Result]{Header: col.Header, Value: func(item gjson.Result) string {
+			if v := value(item); v != "" {
+				return v
+			}
+			return empty
+		}}
+	}
+	return withEmpty
+}
+
+// maxScalarColumns limits the guessed columns of -o table.
+const maxScalarColumns = 6
+
+func scalarColumns(items []gjson.Result, wide bool) []Column[gjson.Result] {
+	if len(items) == 0 || !items[0].IsObject() {
+		return []Column[gjson.Result]{{Header: "Value", Value: func(item gjson.Result) string { return cellValue(item) }}}
+	}
+	var keys []string
+	items[0].ForEach(func(key, value gjson.Result) bool {
+		if !value.IsObject() && !value.IsArray() {
+			keys = append(keys, key.String())
+		}
+		return wide || len(keys) < maxScalarColumns
+	})
+	if len(keys) == 0 {
+		items[0].ForEach(func(key, _ gjson.Result) bool {
+			keys = append(keys, key.String())
+			return true
+		})
+		sort.Strings(keys)
+	}
+	columns := make([]Column[gjson.Result], 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, field(strings.ToUpper(key[:1])+strings.ReplaceAll(key[1:], "_", " "), gjson.Escape(key)))
+	}
+	return columns
+}
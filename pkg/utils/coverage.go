@@ -0,0 +1,303 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+	"github.com/stefanistkuhl/gns3util/pkg/api/schemas"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+type CoverageOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+// CoverageSchema lists the properties of a request schema that its type in
+// pkg/api/schemas lacks. GoType is empty if there is no type for it at all.
+type CoverageSchema struct {
+	Schema        string   `json:"schema"`
+	GoType        string   `json:"go_type,omitempty"`
+	MissingFields []string `json:"missing_fields"`
+	UsedBy        []string `json:"used_by"`
+}
+
+type CoverageReport struct {
+	Operations      int                 `json:"operations"`
+	MissingEndpoint []CoverageOperation `json:"missing_endpoint"`
+	MissingCommand  []CoverageOperation `json:"missing_command"`
+	Schemas         []CoverageSchema    `json:"schemas"`
+}
+
+type openAPIDoc struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type openAPISchema struct {
+	Ref        string                     `json:"$ref"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	AllOf      []openAPISchema            `json:"allOf"`
+	AnyOf      []openAPISchema            `json:"anyOf"`
+	OneOf      []openAPISchema            `json:"oneOf"`
+}
+
+// extraRoutes are the routes of commands that build their requests
+// themselves or through pkg/sdk instead of the command table.
+var extraRoutes []endpoints.Route
+
+// RegisterRoutes records the routes a command sends requests to without the
+// command table, so the coverage report counts them. Paths use {} for their
+// parameters, like "/projects/{}/nodes/start".
+func RegisterRoutes(command string, routes ...endpoints.Route) {
+	for _, r := range routes {
+		r.Path = endpoints.RouteTemplate(r.Path)
+		r.Name = command
+		extraRoutes = append(extraRoutes, r)
+	}
+}
+
+// CommandRoutes returns the route of every entry in the command table that
+// backs the CLI and the routes registered with RegisterRoutes.
+func CommandRoutes() []endpoints.Route {
+	args := []string{"{}", "{}", "{}", "{}", "{}"}
+	var routes []endpoints.Route
+	for name, c := range commandMap {
+		path := c.Endpoint(endpoints.Endpoints{}, args)
+		if path == "" {
+			continue
+		}
+		routes = append(routes, endpoints.Route{Method: string(c.Method), Path: endpoints.RouteTemplate(path), Name: name})
+	}
+	return append(routes, extraRoutes...)
+}
+
+// FetchOpenAPI downloads the controller's OpenAPI document from
+// /v3/openapi.json, or /openapi.json if the former does not exist.
+func FetchOpenAPI(ctx context.Context, cfg config.GlobalOptions) ([]byte, error) {
+	// The document is public on most controllers, use a token if there is one.
+	token, _ := authentication.GetKeyForServer(cfg)
+
+	settings := config.APISettings(cfg, token)
+	client := api.NewGNS3Client(settings)
+	body, _, err := client.Do(api.NewRequestOptions(settings).WithContext(ctx).WithURL("/openapi.json"))
+	if !api.IsNotFound(err) {
+		return body, err
+	}
+
+	settings.BaseURL = strings.TrimSuffix(cfg.Server, "/")
+	client = api.NewGNS3Client(settings)
+	body, _, err = client.Do(api.NewRequestOptions(settings).WithContext(ctx).WithURL("/openapi.json"))
+	return body, err
+}
+
+// BuildCoverageReport compares an OpenAPI document with the endpoint
+// methods, the command table and the request types in pkg/api/schemas.
+func BuildCoverageReport(spec []byte) (CoverageReport, error) {
+	var doc openAPIDoc
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return CoverageReport{}, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return CoverageReport{}, fmt.Errorf("OpenAPI document has no paths")
+	}
+
+	known := func(routes []endpoints.Route) map[string]bool {
+		m := make(map[string]bool, len(routes))
+		for _, r := range routes {
+			m[r.Method+" "+r.Path] = true
+		}
+		return m
+	}
+	haveEndpoint := known(endpoints.Routes())
+	haveCommand := known(CommandRoutes())
+
+	var report CoverageReport
+	usedBy := make(map[string][]string)
+	for path, item := range doc.Paths {
+		route := endpoints.RouteTemplate(strings.TrimPrefix(path, api.API_VERSION))
+		for method, raw := range item {
+			method = strings.ToUpper(method)
+			switch method {
+			case "GET", "POST", "PUT", "DELETE", "PATCH":
+			default:
+				continue
+			}
+			var op openAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return CoverageReport{}, fmt.Errorf("failed to parse %s %s: %w", method, path, err)
+			}
+
+			report.Operations++
+			entry := CoverageOperation{Method: method, Path: path, OperationID: op.OperationID, Summary: op.Summary}
+			if !haveEndpoint[method+" "+route] {
+				report.MissingEndpoint = append(report.MissingEndpoint, entry)
+			}
+			if !haveCommand[method+" "+route] {
+				report.MissingCommand = append(report.MissingCommand, entry)
+			}
+
+			if op.RequestBody == nil {
+				continue
+			}
+			for _, content := range op.RequestBody.Content {
+				for _, name := range schemaRefs(content.Schema) {
+					usedBy[name] = append(usedBy[name], method+" "+path)
+				}
+			}
+		}
+	}
+
+	for name, ops := range usedBy {
+		props := schemaProperties(doc.Components.Schemas, doc.Components.Schemas[name], 0)
+		entry := CoverageSchema{Schema: name, UsedBy: Deduplicate(ops)}
+		if goType, ok := schemas.RequestTypes[name]; ok {
+			t := reflect.TypeOf(goType)
+			entry.GoType = "schemas." + t.Name()
+			have := jsonFields(t)
+			for p := range props {
+				if !have[p] {
+					entry.MissingFields = append(entry.MissingFields, p)
+				}
+			}
+		} else {
+			for p := range props {
+				entry.MissingFields = append(entry.MissingFields, p)
+			}
+		}
+		if entry.GoType != "" && len(entry.MissingFields) == 0 {
+			continue
+		}
+		sort.Strings(entry.MissingFields)
+		sort.Strings(entry.UsedBy)
+		report.Schemas = append(report.Schemas, entry)
+	}
+
+	sortOps := func(ops []CoverageOperation) {
+		sort.Slice(ops, func(i, j int) bool {
+			if ops[i].Path != ops[j].Path {
+				return ops[i].Path < ops[j].Path
+			}
+			return ops[i].Method < ops[j].Method
+		})
+	}
+	sortOps(report.MissingEndpoint)
+	sortOps(report.MissingCommand)
+	sort.Slice(report.Schemas, func(i, j int) bool { return report.Schemas[i].Schema < report.Schemas[j].Schema })
+	return report, nil
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// schemaRefs returns the named schemas a request body uses, looking into
+// unions like the template create body.
+func schemaRefs(s openAPISchema) []string {
+	if s.Ref != "" {
+		return []string{refName(s.Ref)}
+	}
+	var names []string
+	for _, group := range [][]openAPISchema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range group {
+			names = append(names, schemaRefs(sub)...)
+		}
+	}
+	return names
+}
+
+func schemaProperties(all map[string]openAPISchema, s openAPISchema, depth int) map[string]bool {
+	props := make(map[string]bool)
+	if depth > 10 {
+		return props
+	}
+	if s.Ref != "" {
+		return schemaProperties(all, all[refName(s.Ref)], depth+1)
+	}
+	for p := range s.Properties {
+		props[p] = true
+	}
+	for _, sub := range s.AllOf {
+		for p := range schemaProperties(all, sub, depth+1) {
+			props[p] = true
+		}
+	}
+	return props
+}
+
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for n := range jsonFields(f.Type) {
+				fields[n] = true
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+func PrintCoverageReport(r CoverageReport) {
+	fmt.Printf("%v %d operations, %d without an endpoint method, %d without a CLI command, %d request schemas incomplete\n",
+		messageUtils.InfoMsg("API coverage"), r.Operations, len(r.MissingEndpoint), len(r.MissingCommand), len(r.Schemas))
+
+	printOps := func(title string, ops []CoverageOperation) {
+		if len(ops) == 0 {
+			return
+		}
+		fmt.Printf("\n%s\n", messageUtils.Bold(title))
+		for _, op := range ops {
+			desc := op.OperationID
+			if op.Summary != "" {
+				desc = op.Summary
+			}
+			fmt.Printf("  %-7s %s %v\n", op.Method, op.Path, messageUtils.Seperator(desc))
+		}
+	}
+	printOps("Operations without an endpoint method:", r.MissingEndpoint)
+	printOps("Operations without a CLI command:", r.MissingCommand)
+
+	if len(r.Schemas) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", messageUtils.Bold("Request schemas:"))
+	for _, s := range r.Schemas {
+		if s.GoType == "" {
+			fmt.Printf("  %s has no type in pkg/api/schemas %v\n", s.Schema, messageUtils.Seperator("(used by "+strings.Join(s.UsedBy, ", ")+")"))
+			continue
+		}
+		fmt.Printf("  %s is missing %s\n", s.GoType, strings.Join(s.MissingFields, ", "))
+	}
+}
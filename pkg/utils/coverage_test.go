@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	saved := extraRoutes
+	t.Cleanup(func() { extraRoutes = saved })
+
+	RegisterRoutes("node duplicate", endpoints.Route{Method: "POST", Path: "/projects/{project_id}/nodes/{node_id}/duplicate"})
+	RegisterRoutes("project load", endpoints.Route{Method: "POST", Path: "/projects/load?path={}"})
+
+	tests := []struct {
+		method, path, name string
+	}{
+		{"POST", "/projects/{}/nodes/{}/duplicate", "node duplicate"},
+		{"POST", "/projects/load", "project load"},
+	}
+	routes := CommandRoutes()
+	for _, tt := range tests {
+		found := false
+		for _, r := range routes {
+			if r.Method == tt.method && r.Path == tt.path && r.Name == tt.name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("CommandRoutes() lacks %s %s from %q", tt.method, tt.path, tt.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+)
+
+var dryRunNouns = map[string]string{
+	"users":      "user",
+	"groups":     "group",
+	"roles":      "role",
+	"privileges": "privilege",
+	"acl":        "ACE",
+	"pools":      "pool",
+	"projects":   "project",
+	"nodes":      "node",
+	"links":      "link",
+	"drawings":   "drawing",
+	"snapshots":  "snapshot",
+	"templates":  "template",
+	"computes":   "compute",
+	"images":     "image",
+	"appliances": "appliance",
+	"symbols":    "symbol",
+	"members":    "user",
+	"resources":  "project",
+}
+
+// PrintDryRunPlan lists the requests a dry run kept back, grouped by server
+// in the order they would have been sent.
+func PrintDryRunPlan(d *api.DryRun) {
+	planned := d.Planned()
+	if len(planned) == 0 {
+		fmt.Printf("%v Nothing would be changed on the server\n", messageUtils.InfoMsg("Dry run"))
+		return
+	}
+
+	var servers []string
+	byServer := make(map[string][]api.PlannedRequest)
+	for _, p := range planned {
+		if _, ok := byServer[p.Server]; !ok {
+			servers = append(servers, p.Server)
+		}
+		byServer[p.Server] = append(byServer[p.Server], p)
+	}
+
+	fmt.Printf("\n%v %d requests were not sent:\n", messageUtils.WarningMsg("Dry run"), len(planned))
+	for _, server := range servers {
+		fmt.Printf("\n  %v\n", messageUtils.Bold(server))
+		for _, p := range byServer[server] {
+			fmt.Printf("  %4d. %s %v\n", p.Seq, describePlanned(d, p),
+				messageUtils.Seperator(fmt.Sprintf("(%s %s)", p.Method, p.Path)))
+		}
+	}
+}
+
+func describePlanned(d *api.DryRun, p api.PlannedRequest) string {
+	path, rawQuery, _ := strings.Cut(p.Path, "?")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segs) > 0 && segs[0] == "access" {
+		segs = segs[1:]
+	}
+	n := len(segs)
+	name := func(id string) string {
+		if v, ok := d.Name(id); ok {
+			return v
+		}
+		return id
+	}
+	noun := func(coll string) string {
+		if v, ok := dryRunNouns[coll]; ok {
+			return v
+		}
+		return coll
+	}
+
+	var body map[string]any
+	_ = json.Unmarshal([]byte(p.Body), &body)
+	bodyName := func() string {
+		for _, k := range []string{"username", "name"} {
+			if v, ok := body[k].(string); ok && v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	switch {
+	case p.Method == api.POST && n == 1 && segs[0] == "acl":
+		return describeACE(body, name)
+
+	case p.Method == api.POST && n == 4 && segs[0] == "projects" && segs[2] == "templates":
+		return fmt.Sprintf("create node from template %s in project %s", name(segs[3]), name(segs[1]))
+
+	case n == 4 && (segs[2] == "members" || segs[2] == "resources" || segs[2] == "privileges"):
+		switch p.Method {
+		case api.PUT:
+			return fmt.Sprintf("add %s %s to %s %s", noun(segs[2]), name(segs[3]), noun(segs[0]), name(segs[1]))
+		case api.DELETE:
+			return fmt.Sprintf("remove %s %s from %s %s", noun(segs[2]), name(segs[3]), noun(segs[0]), name(segs[1]))
+		}
+
+	case p.Method == api.POST && n%2 == 1 && dryRunNouns[segs[n-1]] != "":
+		what := strings.TrimSpace(fmt.Sprintf("create %s %s", noun(segs[n-1]), bodyName()))
+		if n > 1 {
+			what += fmt.Sprintf(" in %s %s", noun(segs[n-3]), name(segs[n-2]))
+		}
+		return what
+
+	case p.Method == api.POST && n >= 4 && n%2 == 0:
+		return fmt.Sprintf("%s all %ss in %s %s", segs[n-1], noun(segs[n-2]), noun(segs[n-4]), name(segs[n-3]))
+
+	case p.Method == api.POST && n >= 3 && n%2 == 1:
+		action, coll, id := segs[n-1], segs[n-3], segs[n-2]
+		what := fmt.Sprintf("%s %s %s", action, noun(coll), name(id))
+		switch action {
+		case "duplicate":
+			if v := bodyName(); v != "" {
+				what += " as " + v
+			}
+		case "import":
+			q, _ := url.ParseQuery(rawQuery)
+			what = fmt.Sprintf("import %s %s", noun(coll), q.Get("name"))
+		}
+		return what
+
+	case p.Method == api.PUT && n%2 == 0:
+		return fmt.Sprintf("update %s %s", noun(segs[n-2]), name(segs[n-1]))
+
+	case p.Method == api.DELETE && n%2 == 0:
+		return fmt.Sprintf("delete %s %s", noun(segs[n-2]), name(segs[n-1]))
+	}
+	return fmt.Sprintf("%s %s", p.Method, p.Path)
+}
+
+func describeACE(body map[string]any, name func(string) string) string {
+	str := func(k string) string {
+		v, _ := body[k].(string)
+		return v
+	}
+	subject := str("group_id")
+	if str("ace_type") == "user" {
+		subject = str("user_id")
+	}
+	target := str("path")
+	if parts := strings.Split(strings.Trim(target, "/"), "/"); len(parts) == 2 {
+		target = fmt.Sprintf("/%s/%s", parts[0], name(parts[1]))
+	}
+	verb := "allow"
+	if allowed, ok := body["allowed"].(bool); ok && !allowed {
+		verb = "deny"
+	}
+	return fmt.Sprintf("create ACE: %s %s %s with role %s on %s", verb, str("ace_type"), name(subject), name(str("role_id")), target)
+}
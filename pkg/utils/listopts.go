@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/tidwall/gjson"
+)
+
+// filterOperators are tried in order, so <= is found before <.
+var filterOperators = []string{"==", "!=", "~=", "<=", ">=", "<", ">"}
+
+// listFilter is a parsed --filter 'field op value'.
+type listFilter struct {
+	path  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func parseListFilter(s string) (listFilter, error) {
+	for i := 0; i < len(s); i++ {
+		for _, op := range filterOperators {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+			f := listFilter{
+				path:  strings.TrimPrefix(strings.TrimSpace(s[:i]), "."),
+				op:    op,
+				value: unquote(strings.TrimSpace(s[i+len(op):])),
+			}
+			if f.path == "" {
+				return listFilter{}, fmt.Errorf("invalid filter %q: missing field", s)
+			}
+			if op == "~=" {
+				re, err := regexp.Compile(f.value)
+				if err != nil {
+					return listFilter{}, fmt.Errorf("invalid filter %q: %w", s, err)
+				}
+				f.re = re
+			}
+			return f, nil
+		}
+	}
+	return listFilter{}, fmt.Errorf("invalid filter %q, use 'field op value' with one of %s", s, strings.Join(filterOperators, " "))
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func (f listFilter) match(item gjson.Result) bool {
+	v := item.Get(f.path)
+	switch f.op {
+	case "~=":
+		return f.re.MatchString(cellValue(v))
+	case "==":
+		return compareValue(v, f.value) == 0
+	case "!=":
+		return compareValue(v, f.value) != 0
+	}
+	if !v.Exists() {
+		return false
+	}
+	c := compareValue(v, f.value)
+	switch f.op {
+	case "<":
+		return c < 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	default:
+		return c >= 0
+	}
+}
+
+// compareValue compares v numerically if both sides are numbers and as
+// strings otherwise.
+func compareValue(v gjson.Result, value string) int {
+	if v.Type == gjson.Number {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			switch {
+			case v.Float() < n:
+				return -1
+			case v.Float() > n:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(cellValue(v), value)
+}
+
+// AddListFlags adds --query, --filter, --sort-by and --limit to a list
+// command.
+func AddListFlags(cmd *cobra.Command) {
+	cmd.Flags().String("query", "", "gjson path applied to the result before printing, e.g. '#.name'")
+	cmd.Flags().StringArray("filter", nil, "Only show items matching 'field op value', op is one of == != ~= (regex) < > <= >=; repeat to combine")
+	cmd.Flags().String("sort-by", "", "Sort by this field, prefix it with - for descending order")
+	cmd.Flags().Int("limit", 0, "Show at most this many items")
+}
+
+// ListOptionsFromFlags reads the flags of AddListFlags, commands without
+// them get empty options.
+func ListOptionsFromFlags(cmd *cobra.Command) (config.ListOptions, error) {
+	var opts config.ListOptions
+	if cmd.Flags().Lookup("filter") == nil {
+		return opts, nil
+	}
+	opts.Query, _ = cmd.Flags().GetString("query")
+	opts.Filters, _ = cmd.Flags().GetStringArray("filter")
+	opts.SortBy, _ = cmd.Flags().GetString("sort-by")
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	if opts.Limit < 0 {
+		return opts, fmt.Errorf("--limit must not be negative")
+	}
+	for _, f := range opts.Filters {
+		if _, err := parseListFilter(f); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// ApplyListOptions filters, sorts and limits the items of the JSON array
+// body and then runs the query on the result. Bodies that are no array
+// only get the query.
+func ApplyListOptions(opts config.ListOptions, body []byte) ([]byte, error) {
+	result := gjson.ParseBytes(body)
+	if result.IsArray() && (len(opts.Filters) > 0 || opts.SortBy != "" || opts.Limit > 0) {
+		filters := make([]listFilter, 0, len(opts.Filters))
+		for _, s := range opts.Filters {
+			f, err := parseListFilter(s)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+
+		var items []gjson.Result
+		for _, item := range result.Array() {
+			matches := true
+			for _, f := range filters {
+				if !f.match(item) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				items = append(items, item)
+			}
+		}
+
+		if opts.SortBy != "" {
+			path, desc := strings.CutPrefix(opts.SortBy, "-")
+			path = strings.TrimPrefix(path, ".")
+			sort.SliceStable(items, func(i, j int) bool {
+				a, b := items[i].Get(path), items[j].Get(path)
+				// Items without the field go last in both directions.
+				aSet, bSet := a.Exists() && a.Type != gjson.Null, b.Exists() && b.Type != gjson.Null
+				if !aSet || !bSet {
+					return aSet && !bSet
+				}
+				c := compareResults(a, b)
+				if desc {
+					return c > 0
+				}
+				return c < 0
+			})
+		}
+
+		if opts.Limit > 0 && len(items) > opts.Limit {
+			items = items[:opts.Limit]
+		}
+
+		raws := make([]string, len(items))
+		for i, item := range items {
+			raws[i] = item.Raw
+		}
+		body = []byte("[" + strings.Join(raws, ",") + "]")
+	}
+
+	if opts.Query != "" {
+		q := gjson.GetBytes(body, opts.Query)
+		if !q.Exists() {
+			return []byte("null"), nil
+		}
+		return []byte(q.Raw), nil
+	}
+	return body, nil
+}
+
+func compareResults(a, b gjson.Result) int {
+	if a.Type == gjson.Number && b.Type == gjson.Number {
+		switch {
+		case a.Float() < b.Float():
+			return -1
+		case a.Float() > b.Float():
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(cellValue(a), cellValue(b))
+}
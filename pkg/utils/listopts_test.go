@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/tidwall/gjson"
+)
+
+func TestParseListFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		path    string
+		op      string
+		value   string
+		wantErr bool
+	}{
+		{"name==lab", "name", "==", "lab", false},
+		{" .status != 'opened' ", "status", "!=", "opened", false},
+		{`name ~= "^lab-[0-9]+$"`, "name", "~=", "^lab-[0-9]+$", false},
+		{"port<=5000", "port", "<=", "5000", false},
+		{"port>=5000", "port", ">=", "5000", false},
+		{"port<5000", "port", "<", "5000", false},
+		{"label.text>e0", "label.text", ">", "e0", false},
+		{"name==a==b", "name", "==", "a==b", false},
+		{"name==", "name", "==", "", false},
+		{"==lab", "", "", "", true},
+		{"name", "", "", "", true},
+		{"name~=[", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			f, err := parseListFilter(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseListFilter(%q) error = %v", tt.in, err)
+			}
+			if f.path != tt.path || f.op != tt.op || f.value != tt.value {
+				t.Errorf("parseListFilter(%q) = %q %q %q, want %q %q %q", tt.in, f.path, f.op, f.value, tt.path, tt.op, tt.value)
+			}
+		})
+	}
+}
+
+func TestListFilterMatch(t *testing.T) {
+	item := gjson.Parse(`{"name": "lab-1", "port": 5000, "status": "opened", "tags": ["a", "b"], "gone": null}`)
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"name==lab-1", true},
+		{"name==lab-2", false},
+		{"name!=lab-2", true},
+		{"missing==''", true},
+		{"missing!=x", true},
+		{"name~=^lab-[0-9]$", true},
+		{"name~=^exam", false},
+		{"tags~=^a,b$", true},
+		{"port==5000.0", true},
+		{"port<10000", true},
+		{"port>10000", false},
+		{"port<=5000", true},
+		{"port>=5001", false},
+		{"status<p", true},
+		{"missing<1", false},
+		{"missing>1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := parseListFilter(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.match(item); got != tt.want {
+				t.Errorf("match(%s) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyListOptions(t *testing.T) {
+	body := `[{"name": "c", "port": 10}, {"name": "a", "port": 9}, {"name": "b"}, {"name": "d", "port": 100}]`
+	tests := []struct {
+		name string
+		opts config.ListOptions
+		body string
+		want string
+	}{
+		{"nothing", config.ListOptions{}, body, body},
+		{"filter", config.ListOptions{Filters: []string{"port>9"}}, body, `[{"name": "c", "port": 10},{"name": "d", "port": 100}]`},
+		{"filters combine", config.ListOptions{Filters: []string{"port>9", "name!=d"}}, body, `[{"name": "c", "port": 10}]`},
+		{"sort numbers", config.ListOptions{SortBy: "port", Query: "#.name"}, body, `["a","c","d","b"]`},
+		{"sort descending", config.ListOptions{SortBy: "-port", Query: "#.name"}, body, `["d","c","a","b"]`},
+		{"sort strings", config.ListOptions{SortBy: ".name", Query: "#.name"}, body, `["a","b","c","d"]`},
+		{"limit", config.ListOptions{SortBy: "name", Limit: 2, Query: "#.name"}, body, `["a","b"]`},
+		{"limit larger than the list", config.ListOptions{Limit: 10, Query: "#.name"}, body, `["c","a","b","d"]`},
+		{"no match", config.ListOptions{Filters: []string{"name==x"}}, body, `[]`},
+		{"query of an object", config.ListOptions{Filters: []string{"name==x"}, Query: "name"}, `{"name": "lab"}`, `"lab"`},
+		{"missing query", config.ListOptions{Query: "nothing"}, body, `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyListOptions(tt.opts, []byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ApplyListOptions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ApplyListOptions(config.ListOptions{Filters: []string{"port"}}, []byte(body)); err == nil {
+		t.Error("ApplyListOptions() accepted an invalid filter")
+	}
+}
+
+func TestListOptionsFromFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    config.ListOptions
+		wantErr bool
+	}{
+		{"none", nil, config.ListOptions{}, false},
+		{"all", []string{"--query", "#.name", "--filter", "port>1", "--filter", "name~=^a", "--sort-by", "-name", "--limit", "3"},
+			config.ListOptions{Query: "#.name", Filters: []string{"port>1", "name~=^a"}, SortBy: "-name", Limit: 3}, false},
+		{"negative limit", []string{"--limit", "-1"}, config.ListOptions{}, true},
+		{"invalid filter", []string{"--filter", "name"}, config.ListOptions{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			AddListFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ListOptionsFromFlags(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListOptionsFromFlags() error = %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Query != tt.want.Query || got.SortBy != tt.want.SortBy || got.Limit != tt.want.Limit || !slices.Equal(got.Filters, tt.want.Filters) {
+				t.Errorf("ListOptionsFromFlags() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := ListOptionsFromFlags(&cobra.Command{}); err != nil || got.Active() {
+		t.Errorf("ListOptionsFromFlags() without the flags = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,340 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/pretty"
+	"gopkg.in/yaml.v3"
+)
+
+// outputAnnotation marks the --output flags added by AddOutputFlag, other
+// commands use --output for file names.
+const outputAnnotation = "gns3util_output_format"
+
+var outputFormats = []string{"json", "yaml", "table", "wide", "csv", "tsv", "name", "go-template=...", "jsonpath=..."}
+
+// OutputFormat is a parsed --output value. Arg holds the template of
+// go-template and jsonpath.
+type OutputFormat struct {
+	Name string
+	Arg  string
+}
+
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	name, arg, hasArg := strings.Cut(s, "=")
+	switch name {
+	case "json", "yaml", "table", "wide", "csv", "tsv", "name":
+		if hasArg {
+			return OutputFormat{}, fmt.Errorf("output format %s takes no argument", name)
+		}
+	case "go-template", "jsonpath":
+		if arg == "" {
+			return OutputFormat{}, fmt.Errorf("output format %s needs a template, e.g. %s='{.name}'", name, name)
+		}
+	default:
+		return OutputFormat{}, fmt.Errorf("unknown output format %q, use one of %s", s, strings.Join(outputFormats, ", "))
+	}
+	return OutputFormat{Name: name, Arg: arg}, nil
+}
+
+// AddOutputFlag adds -o/--output to a command that prints resources. The
+// shorthand is left out where the command already uses -o.
+func AddOutputFlag(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("output") != nil {
+		return
+	}
+	usage := "Output format: " + strings.Join(outputFormats, ", ")
+	if cmd.Flags().ShorthandLookup("o") != nil {
+		cmd.Flags().String("output", "", usage)
+	} else {
+		cmd.Flags().StringP("output", "o", "", usage)
+	}
+	_ = cmd.Flags().SetAnnotation("output", outputAnnotation, []string{"true"})
+}
+
+// OutputFlag returns the --output value of cmd, or "" if it has no output
+// format flag.
+func OutputFlag(cmd *cobra.Command) string {
+	f := cmd.Flags().Lookup("output")
+	if f == nil || f.Annotations[outputAnnotation] == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// PrintOutput prints an API response of resource in the --output format,
+// after applying the list options. Without one it prints key/value pairs,
+// or JSON with --raw or --query.
+func PrintOutput(cfg config.GlobalOptions, resource string, body []byte) {
+	if cfg.List.Active() {
+		var err error
+		if body, err = ApplyListOptions(cfg.List, body); err != nil {
+			fmt.Printf("%v %v\n", messageUtils.ErrorMsg("Error"), err)
+			return
+		}
+		if cfg.List.Query != "" {
+			// The query result is no longer a list of resource.
+			resource = ""
+		}
+	}
+	if cfg.Output == "" {
+		if cfg.Raw || cfg.List.Query != "" {
+			if cfg.NoColors {
+				PrintJsonUgly(body)
+			} else {
+				PrintJson(body)
+			}
+		} else {
+			PrintKV(body)
+		}
+		return
+	}
+	if err := WriteOutput(cfg.Output, resource, body); err != nil {
+		fmt.Printf("%v %v\n", messageUtils.ErrorMsg("Error"), err)
+	}
+}
+
+// WriteOutput prints body in format. resource selects the table columns,
+// unknown resources get one column per scalar field.
+func WriteOutput(format, resource string, body []byte) error {
+	f, err := ParseOutputFormat(format)
+	if err != nil {
+		return err
+	}
+	result := gjson.ParseBytes(body)
+
+	switch f.Name {
+	case "json":
+		fmt.Print(string(pretty.Pretty(body)))
+	case "yaml":
+		data, err := decodeJSON(body)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	case "table", "wide":
+		if !result.IsArray() && !result.IsObject() {
+			fmt.Println(result.String())
+			return nil
+		}
+		items := outputItems(result)
+		PrintTable(items, tableColumns(resource, items, f.Name == "wide", "N/A"))
+	case "csv", "tsv":
+		items := outputItems(result)
+		columns := tableColumns(resource, items, false, "")
+		w := csv.NewWriter(os.Stdout)
+		if f.Name == "tsv" {
+			w.Comma = '\t'
+		}
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = col.Header
+		}
+		_ = w.Write(row)
+		for _, item := range items {
+			for i, col := range columns {
+				row[i] = col.Value(item)
+			}
+			_ = w.Write(row)
+		}
+		w.Flush()
+		return w.Error()
+	case "name":
+		for _, item := range outputItems(result) {
+			if !item.IsObject() {
+				fmt.Println(item.String())
+				continue
+			}
+			fmt.Println(item.Get(nameField(resource, item)).String())
+		}
+	case "go-template":
+		tmpl, err := template.New("output").Parse(f.Arg)
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %w", err)
+		}
+		data, err := decodeJSON(body)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(os.Stdout, data)
+	case "jsonpath":
+		out, err := evalJSONPath(f.Arg, body)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+	}
+	return nil
+}
+
+func outputItems(result gjson.Result) []gjson.Result {
+	if result.IsArray() {
+		return result.Array()
+	}
+	return []gjson.Result{result}
+}
+
+// decodeJSON decodes body for yaml and go-template keeping integers
+// integers, so ports and timestamps do not turn into floats.
+func decodeJSON(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return normalizeNumbers(data), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+func nameField(resource string, item gjson.Result) string {
+	if fields, ok := idElementName[resource]; ok && item.Get(fields[1]).Exists() {
+		return fields[1]
+	}
+	for _, field := range []string{"name", "username", "filename"} {
+		if item.Get(field).Exists() {
+			return field
+		}
+	}
+	if fields, ok := idElementName[resource]; ok {
+		return fields[0]
+	}
+	return "name"
+}
+
+// evalJSONPath renders a kubectl style jsonpath template like
+// '{[*].name}{"\n"}'. Expressions in braces select fields with .field, [n]
+// and [*], quoted strings are printed as they are. Ranges and filters are
+// not supported.
+func evalJSONPath(tmpl string, body []byte) (string, error) {
+	var out strings.Builder
+	for tmpl != "" {
+		start := strings.Index(tmpl, "{")
+		if start < 0 {
+			out.WriteString(tmpl)
+			break
+		}
+		end := strings.Index(tmpl[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("invalid jsonpath %q: unclosed {", tmpl)
+		}
+		out.WriteString(tmpl[:start])
+		expr := strings.TrimSpace(tmpl[start+1 : start+end])
+		tmpl = tmpl[start+end+1:]
+
+		if strings.HasPrefix(expr, `"`) {
+			s, err := strconv.Unquote(expr)
+			if err != nil {
+				return "", fmt.Errorf("invalid jsonpath string %s: %w", expr, err)
+			}
+			out.WriteString(s)
+			continue
+		}
+		result := gjson.ParseBytes(body)
+		if path := jsonPathToGJSON(expr); path != "" {
+			result = result.Get(path)
+		}
+		if result.IsArray() {
+			values := make([]string, 0, len(result.Array()))
+			for _, v := range result.Array() {
+				values = append(values, v.String())
+			}
+			out.WriteString(strings.Join(values, " "))
+		} else {
+			out.WriteString(result.String())
+		}
+	}
+	return out.String(), nil
+}
+
+// jsonPathToGJSON turns $.items[*].name into the gjson path items.#.name.
+func jsonPathToGJSON(expr string) string {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	var parts []string
+	for _, seg := range strings.Split(expr, ".") {
+		for seg != "" {
+			open := strings.Index(seg, "[")
+			if open < 0 {
+				parts = append(parts, seg)
+				break
+			}
+			if open > 0 {
+				parts = append(parts, seg[:open])
+			}
+			closing := strings.Index(seg[open:], "]")
+			if closing < 0 {
+				parts = append(parts, seg[open:])
+				break
+			}
+			index := seg[open+1 : open+closing]
+			if index == "*" {
+				index = "#"
+			}
+			parts = append(parts, index)
+			seg = seg[open+closing+1:]
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// commandResources maps the commands whose resource does not follow from
+// their name.
+var commandResources = map[string]string{
+	"getMe":               "user",
+	"getGroupMembers":     "user",
+	"getGroupMemberships": "group",
+	"getRolePrivs":        "privilege",
+	"getAcl":              "acl-rule",
+	"getAce":              "acl-rule",
+	"getNodeLinks":        "link",
+	"getDefaultSymbols":   "symbol",
+}
+
+// ResourceForCommand returns the resource type a command of commandMap
+// returns, e.g. "node" for getNodes, or "" if it is none of them.
+func ResourceForCommand(cmdName string) string {
+	if resource, ok := commandResources[cmdName]; ok {
+		return resource
+	}
+	name := strings.ToLower(strings.TrimPrefix(cmdName, "get"))
+	for _, candidate := range []string{name, strings.TrimSuffix(name, "s")} {
+		if _, ok := idElementName[candidate]; ok {
+			return candidate
+		}
+	}
+	return ""
+}
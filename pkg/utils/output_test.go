@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout returns what f prints to stdout.
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	ferr := f()
+	_ = w.Close()
+	return <-out, ferr
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"json", OutputFormat{Name: "json"}, false},
+		{"wide", OutputFormat{Name: "wide"}, false},
+		{"go-template={{.name}}", OutputFormat{Name: "go-template", Arg: "{{.name}}"}, false},
+		{"jsonpath={.a=b}", OutputFormat{Name: "jsonpath", Arg: "{.a=b}"}, false},
+		{"json=x", OutputFormat{}, true},
+		{"jsonpath", OutputFormat{}, true},
+		{"go-template=", OutputFormat{}, true},
+		{"xml", OutputFormat{}, true},
+		{"", OutputFormat{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOutputFormat(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseOutputFormat(%q) = %+v, %v", tt.in, got, err)
+			}
+		})
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	projects := `[{"name": "lab", "project_id": "p1", "status": "opened", "auto_start": false, "path": "/p/1"},
+		{"name": "exam", "project_id": "p2", "status": "closed", "auto_start": true, "path": "/p/2"}]`
+	tests := []struct {
+		name     string
+		format   string
+		resource string
+		body     string
+		want     string
+	}{
+		{"json", "json", "project", `{"name":"lab","port":5000}`, "{\n  \"name\": \"lab\",\n  \"port\": 5000\n}\n"},
+		{"yaml keeps integers", "yaml", "", `{"name": "lab", "port": 5000, "ratio": 0.5}`, "name: lab\nport: 5000\nratio: 0.5\n"},
+		{"csv", "csv", "project", projects, "Name,ID,Status\nlab,p1,opened\nexam,p2,closed\n"},
+		{"tsv", "tsv", "project", projects, "Name\tID\tStatus\nlab\tp1\topened\nexam\tp2\tclosed\n"},
+		{"csv of an unknown resource", "csv", "", `[{"a": 1, "b": "x", "c": {"d": 1}}]`, "A,B\n1,x\n"},
+		{"csv of an object", "csv", "project", `{"name": "lab", "project_id": "p1"}`, "Name,ID,Status\nlab,p1,\n"},
+		{"name", "name", "project", projects, "lab\nexam\n"},
+		{"name of users", "name", "user", `[{"user_id": "u1", "username": "alice"}]`, "alice\n"},
+		{"name of scalars", "name", "", `["a", "b"]`, "a\nb\n"},
+		{"go-template", "go-template={{range .}}{{.name}}={{.auto_start}} {{end}}", "project", projects, "lab=false exam=true "},
+		{"jsonpath", `jsonpath={[*].name}{"\n"}`, "project", projects, "lab exam\n"},
+		{"jsonpath index", `jsonpath=first: {$[0].project_id}`, "project", projects, "first: p1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := captureStdout(t, func() error { return WriteOutput(tt.format, tt.resource, []byte(tt.body)) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("WriteOutput(%s) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOutputErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		body   string
+	}{
+		{"unknown format", "xml", `{}`},
+		{"invalid go-template", "go-template={{.name", `{}`},
+		{"unclosed jsonpath", "jsonpath={.name", `{}`},
+		{"yaml of invalid json", "yaml", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := captureStdout(t, func() error { return WriteOutput(tt.format, "", []byte(tt.body)) }); err == nil {
+				t.Errorf("WriteOutput(%s) succeeded", tt.format)
+			}
+		})
+	}
+}
+
+func TestJSONPathToGJSON(t *testing.T) {
+	tests := map[string]string{
+		".name":             "name",
+		"$.items[*].name":   "items.#.name",
+		"[0].project_id":    "0.project_id",
+		"nodes[1].ports[*]": "nodes.1.ports.#",
+		"":                  "",
+	}
+	for expr, want := range tests {
+		if got := jsonPathToGJSON(expr); got != want {
+			t.Errorf("jsonPathToGJSON(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
+
+func TestResourceForCommand(t *testing.T) {
+	tests := map[string]string{
+		"getProjects":     "project",
+		"getNode":         "node",
+		"getMe":           "user",
+		"getGroupMembers": "user",
+		"getAcl":          "acl-rule",
+		"getVersion":      "",
+	}
+	for cmd, want := range tests {
+		if got := ResourceForCommand(cmd); got != want {
+			t.Errorf("ResourceForCommand(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
@@ -14,6 +14,13 @@ type GNS3Key struct {
 	User        string `json:"user"`
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
+	// CertSHA256 is the pinned certificate fingerprint of an https server.
+	CertSHA256 string `json:"cert_sha256,omitempty"`
+	// ExpiresAt is when the token expires in Unix seconds, 0 if unknown.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+	// Password is only stored encrypted with the credential store, to log
+	// in again when the token expired.
+	Password string `json:"password,omitempty"`
 }
 
 func ExpandPath(p string) (string, error) {
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/tidwall/gjson"
+)
+
+// RawRequest is a request to an arbitrary controller path as sent by
+// gns3util api.
+type RawRequest struct {
+	Method api.HTTPMethod
+	// Path is relative to the API prefix, e.g. /projects.
+	Path   string
+	Params map[string]string
+	Body   string
+}
+
+// ParseAPIPath splits a path given on the command line into the path below
+// the API prefix and its query parameters. A leading /v3 is dropped so
+// paths copied from the API docs work as well.
+func ParseAPIPath(p string) (string, map[string]string, error) {
+	u, err := url.Parse(p)
+	if err != nil || u.IsAbs() || u.Host != "" {
+		return "", nil, fmt.Errorf("invalid API path %q: give the path below the server URL, e.g. /projects", p)
+	}
+	path := "/" + strings.TrimPrefix(u.Path, "/")
+	if rest, ok := strings.CutPrefix(path, api.API_VERSION); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		path = "/" + strings.TrimPrefix(rest, "/")
+	}
+
+	params := make(map[string]string)
+	for k, v := range u.Query() {
+		params[k] = v[len(v)-1]
+	}
+	return path, params, nil
+}
+
+// CallRaw sends req with the token stored for cfg.Server.
+func CallRaw(ctx context.Context, cfg config.GlobalOptions, req RawRequest) ([]byte, int, error) {
+	token, err := authentication.GetKeyForServer(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	settings := config.APISettings(cfg, token)
+	client := api.NewGNS3Client(settings)
+	reqOpts := api.NewRequestOptions(settings).
+		WithContext(ctx).
+		WithURL(req.Path).
+		WithMethod(req.Method)
+	for k, v := range req.Params {
+		reqOpts = reqOpts.WithParam(k, v)
+	}
+	if req.Body != "" {
+		reqOpts = reqOpts.WithData(req.Body)
+	}
+
+	body, resp, err := client.Do(reqOpts)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	return body, status, err
+}
+
+// MergeArrays joins the JSON arrays of several responses into one array.
+// Responses that are not arrays are added as a single element.
+func MergeArrays(bodies [][]byte) []byte {
+	merged := []json.RawMessage{}
+	for _, body := range bodies {
+		result := gjson.ParseBytes(body)
+		switch {
+		case len(body) == 0:
+		case result.IsArray():
+			result.ForEach(func(_, elem gjson.Result) bool {
+				merged = append(merged, json.RawMessage(elem.Raw))
+				return true
+			})
+		default:
+			merged = append(merged, json.RawMessage(result.Raw))
+		}
+	}
+	out, _ := json.Marshal(merged)
+	return out
+}
+
+// PrintResponse prints an API response in the format selected by --raw and
+// --no-color. Plain values and lists of them, e.g. from a --jq query, are
+// printed one per line unless --raw is set.
+func PrintResponse(cfg config.GlobalOptions, body []byte) {
+	result := gjson.ParseBytes(body)
+	if !result.IsObject() && !result.IsArray() {
+		fmt.Println(result.String())
+		return
+	}
+	if !cfg.Raw && result.IsArray() && isScalarList(result) {
+		result.ForEach(func(_, elem gjson.Result) bool {
+			fmt.Println(elem.String())
+			return true
+		})
+		return
+	}
+	if cfg.Raw {
+		if cfg.NoColors {
+			PrintJsonUgly(body)
+		} else {
+			PrintJson(body)
+		}
+		return
+	}
+	PrintKV(body)
+}
+
+func isScalarList(result gjson.Result) bool {
+	elems := result.Array()
+	if len(elems) == 0 {
+		return false
+	}
+	for _, elem := range elems {
+		if elem.IsObject() || elem.IsArray() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+)
+
+// Session sends commands like CallClient but reads the token once and
+// keeps one client, for runs that send many requests. A token renewed
+// after a rejection is used for the following requests.
+type Session struct {
+	settings api.Settings
+	client   *api.GNS3ApiClient
+}
+
+func NewSession(cfg config.GlobalOptions) (*Session, error) {
+	token, err := authentication.GetKeyForServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{settings: config.APISettings(cfg, token)}
+	if reauth := s.settings.Reauth; reauth != nil {
+		s.settings.Reauth = func(ctx context.Context, expired string) (string, error) {
+			token, err := reauth(ctx, expired)
+			if err == nil {
+				s.settings.Token = token
+			}
+			return token, err
+		}
+	}
+	s.client = api.NewGNS3Client(s.settings)
+	return s, nil
+}
+
+func (s *Session) Call(ctx context.Context, cmdName string, args []string, body any) ([]byte, int, error) {
+	cmd, ok := commandMap[cmdName]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown command: %s", cmdName)
+	}
+	return callCommand(ctx, s.client, s.settings, cmdName, cmd, args, body)
+}
+
+// ResolveID is ResolveID with the session's login.
+func (s *Session) ResolveID(ctx context.Context, subcommand, name string, args []string) (string, error) {
+	cmdName, err := listCommandFor(subcommand)
+	if err != nil {
+		return "", err
+	}
+	body, _, err := s.Call(ctx, cmdName, args, nil)
+	if err != nil {
+		return "", err
+	}
+	return idForName(body, subcommand, name)
+}
@@ -2,13 +2,14 @@ package utils
 
 import (
 	"bufio"
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -18,9 +19,7 @@ import (
 	"github.com/stefanistkuhl/gns3util/pkg/api/endpoints"
 	"github.com/stefanistkuhl/gns3util/pkg/authentication"
 	"github.com/stefanistkuhl/gns3util/pkg/config"
-	"github.com/stefanistkuhl/gns3util/pkg/utils/errorUtils"
 	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
-	"github.com/stefanistkuhl/gns3util/pkg/utils/pathUtils"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/pretty"
 	"golang.org/x/text/cases"
@@ -68,7 +67,7 @@ func GetIDFieldMapping(resourceType string) (string, string, bool) {
 	return "", "", false
 }
 
-func CallClient(cfg config.GlobalOptions, cmdName string, args []string, body any) ([]byte, int, error) {
+func CallClient(ctx context.Context, cfg config.GlobalOptions, cmdName string, args []string, body any) ([]byte, int, error) {
 	cmd, ok := commandMap[cmdName]
 	if !ok {
 		return nil, 0, fmt.Errorf("unknown command: %s", cmdName)
@@ -83,20 +82,19 @@ func CallClient(cfg config.GlobalOptions, cmdName string, args []string, body an
 		}
 	}
 
-	settings := api.NewSettings(
-		api.WithBaseURL(cfg.Server),
-		api.WithVerify(!cfg.Insecure),
-		api.WithToken(token),
-	)
+	settings := config.APISettings(cfg, token)
+	return callCommand(ctx, api.NewGNS3Client(settings), settings, cmdName, cmd, args, body)
+}
 
+func callCommand(ctx context.Context, client *api.GNS3ApiClient, settings api.Settings, cmdName string, cmd CommandConfig, args []string, body any) ([]byte, int, error) {
 	ep := endpoints.Endpoints{}
 	endpointPath := cmd.Endpoint(ep, args)
 	if endpointPath == "" {
 		return nil, 0, fmt.Errorf("missing required arguments for command: %s", cmdName)
 	}
 
-	client := api.NewGNS3Client(settings)
 	reqOpts := api.NewRequestOptions(settings).
+		WithContext(ctx).
 		WithURL(endpointPath).
 		WithMethod(cmd.Method)
 
@@ -128,11 +126,15 @@ func CallClient(cfg config.GlobalOptions, cmdName string, args []string, body an
 	return respBody, resp.StatusCode, nil
 }
 
-func ExecuteAndPrint(cfg config.GlobalOptions, cmdName string, args []string) {
-	body, status, err := CallClient(cfg, cmdName, args, nil)
+func ExecuteAndPrint(ctx context.Context, cfg config.GlobalOptions, cmdName string, args []string) {
+	body, status, err := CallClient(ctx, cfg, cmdName, args, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Authentication was unsuccessful") {
-			fmt.Printf("%v Authentication failed. Please check your username and password.\n", messageUtils.ErrorMsg("Authentication failed"))
+		if api.IsUnauthorized(err) {
+			fmt.Printf("%v The server rejected the stored token, it may have expired. Use %s to log in again.\n", messageUtils.ErrorMsg("Authentication failed"), messageUtils.Bold("auth login"))
+			return
+		}
+		if errors.Is(err, context.Canceled) {
+			fmt.Printf("%v Command '%s' was cancelled\n", messageUtils.WarningMsg("Interrupted"), cmdName)
 			return
 		}
 		fmt.Printf("%v %v\n", messageUtils.ErrorMsg("API error"), err)
@@ -148,15 +150,7 @@ func ExecuteAndPrint(cfg config.GlobalOptions, cmdName string, args []string) {
 			messageUtils.SuccessMsg("Command executed successfully"), cmdName)
 		return
 	}
-	if cfg.Raw {
-		if cfg.NoColors {
-			PrintJsonUgly(body)
-		} else {
-			PrintJson(body)
-		}
-	} else {
-		PrintKV(body)
-	}
+	PrintOutput(cfg, ResourceForCommand(cmdName), body)
 }
 
 func PrintJson(body []byte) {
@@ -286,11 +280,15 @@ func PrintSeperator() {
 	fmt.Println(messageUtils.Seperator(strings.Repeat("-", 69)))
 }
 
-func ExecuteAndPrintWithBody(cfg config.GlobalOptions, cmdName string, args []string, body any) {
-	respBody, status, err := CallClient(cfg, cmdName, args, body)
+func ExecuteAndPrintWithBody(ctx context.Context, cfg config.GlobalOptions, cmdName string, args []string, body any) {
+	respBody, status, err := CallClient(ctx, cfg, cmdName, args, body)
 	if err != nil {
-		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Authentication was unsuccessful") {
-			fmt.Printf("%v Authentication failed. Please check your username and password.\n", messageUtils.ErrorMsg("Authentication failed"))
+		if api.IsUnauthorized(err) {
+			fmt.Printf("%v The server rejected the stored token, it may have expired. Use %s to log in again.\n", messageUtils.ErrorMsg("Authentication failed"), messageUtils.Bold("auth login"))
+			return
+		}
+		if errors.Is(err, context.Canceled) {
+			fmt.Printf("%v Command '%s' was cancelled\n", messageUtils.WarningMsg("Interrupted"), cmdName)
 			return
 		}
 		fmt.Printf("%v %v\n", messageUtils.ErrorMsg("API error"), err)
@@ -315,34 +313,26 @@ func IsValidUUIDv4(s string) bool {
 	u, err := uuid.Parse(s)
 	return err == nil && u.Version() == 4
 }
-func ResolveID(cfg config.GlobalOptions, subcommand string, name string, args []string) (string, error) {
-	titleCaser := cases.Title(language.Und)
-	key, ok := subcommandKeyMap[subcommand]
-	if !ok {
-		return "", fmt.Errorf("could not find the method used to resolve this id for subcommand: %s", subcommand)
-	}
-
-	cmd, ok := commandMap["get"+titleCaser.String(key)]
-	if !ok {
-		return "", fmt.Errorf("no command found to fetch list for subcommand: %s", subcommand)
+func ResolveID(ctx context.Context, cfg config.GlobalOptions, subcommand string, name string, args []string) (string, error) {
+	cmdName, err := listCommandFor(subcommand)
+	if err != nil {
+		return "", err
 	}
+	cmd := commandMap[cmdName]
 
 	token, err := authentication.GetKeyForServer(cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to get token: %w", err)
 	}
 
-	settings := api.NewSettings(
-		api.WithBaseURL(cfg.Server),
-		api.WithVerify(!cfg.Insecure),
-		api.WithToken(token),
-	)
+	settings := config.APISettings(cfg, token)
 	client := api.NewGNS3Client(settings)
 
 	ep := endpoints.Endpoints{}
 	endpointPath := cmd.Endpoint(ep, args)
 
 	reqOpts := api.NewRequestOptions(settings).
+		WithContext(ctx).
 		WithURL(endpointPath).
 		WithMethod(api.GET)
 
@@ -356,6 +346,27 @@ func ResolveID(cfg config.GlobalOptions, subcommand string, name string, args []
 		}
 	}()
 
+	return idForName(body, subcommand, name)
+}
+
+// listCommandFor returns the command that lists the resources of
+// subcommand, to look names up in.
+func listCommandFor(subcommand string) (string, error) {
+	titleCaser := cases.Title(language.Und)
+	key, ok := subcommandKeyMap[subcommand]
+	if !ok {
+		return "", fmt.Errorf("could not find the method used to resolve this id for subcommand: %s", subcommand)
+	}
+
+	cmdName := "get" + titleCaser.String(key)
+	if _, ok := commandMap[cmdName]; !ok {
+		return "", fmt.Errorf("no command found to fetch list for subcommand: %s", subcommand)
+	}
+	return cmdName, nil
+}
+
+// idForName returns the id of the resource called name in the list body.
+func idForName(body []byte, subcommand, name string) (string, error) {
 	var data []map[string]any
 	if err := json.Unmarshal(body, &data); err != nil {
 		return "", fmt.Errorf("failed to parse API response: %w", err)
@@ -383,7 +394,7 @@ func ResolveID(cfg config.GlobalOptions, subcommand string, name string, args []
 	return "", fmt.Errorf("failed to resolve the name %s to a valid id", messageUtils.Bold(name))
 }
 
-func GetResourceWithContext(cfg config.GlobalOptions, commandName string, resourceIDs []string, contextType, contextLabel string) (map[string][]byte, error) {
+func GetResourceWithContext(ctx context.Context, cfg config.GlobalOptions, commandName string, resourceIDs []string, contextType, contextLabel string) (map[string][]byte, error) {
 	resourceData := make(map[string][]byte)
 
 	needsContext := contextType != "" && contextLabel != ""
@@ -394,7 +405,7 @@ func GetResourceWithContext(cfg config.GlobalOptions, commandName string, resour
 		if needsContext {
 			contextCommand := getContextCommand(contextType)
 			if contextCommand != "" {
-				contextBody, _, err := CallClient(cfg, contextCommand, []string{resourceID}, nil)
+				contextBody, _, err := CallClient(ctx, cfg, contextCommand, []string{resourceID}, nil)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get %s info for %s: %w", contextType, resourceID, err)
 				}
@@ -411,7 +422,7 @@ func GetResourceWithContext(cfg config.GlobalOptions, commandName string, resour
 			contextKey = resourceID
 		}
 
-		resourceBody, _, err := CallClient(cfg, commandName, []string{resourceID}, nil)
+		resourceBody, _, err := CallClient(ctx, cfg, commandName, []string{resourceID}, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get %s for %s: %w", commandName, contextKey, err)
 		}
@@ -422,7 +433,17 @@ func GetResourceWithContext(cfg config.GlobalOptions, commandName string, resour
 	return resourceData, nil
 }
 
-func PrintResourceWithContext(resourceData map[string][]byte, contextLabel string) {
+func PrintResourceWithContext(cfg config.GlobalOptions, resource string, resourceData map[string][]byte, contextLabel string) {
+	if (cfg.Output != "" && cfg.Output != "table" && cfg.Output != "wide") || cfg.List.Active() {
+		// Machine readable formats get one document with all resources,
+		// so do filters, sorting and limits.
+		var bodies [][]byte
+		for _, contextKey := range getSortedKeys(resourceData) {
+			bodies = append(bodies, resourceData[contextKey])
+		}
+		PrintOutput(cfg, resource, MergeArrays(bodies))
+		return
+	}
 	for i, contextKey := range getSortedKeys(resourceData) {
 		resourceBody := resourceData[contextKey]
 
@@ -441,6 +462,8 @@ func PrintResourceWithContext(resourceData map[string][]byte, contextLabel strin
 			resourceResult := gjson.ParseBytes(resourceBody)
 			if resourceResult.IsArray() && len(resourceResult.Array()) == 0 {
 				fmt.Println("  No data found")
+			} else if cfg.Output != "" {
+				PrintOutput(cfg, resource, resourceBody)
 			} else {
 				PrintKV(resourceBody)
 			}
@@ -656,28 +679,9 @@ func ConfirmPrompt(msg string, defaultYes bool) bool {
 }
 
 func GetUserInKeyFileForUrl(cfg config.GlobalOptions) (string, error) {
-	var keys []pathUtils.GNS3Key
-	var getKeyErr error
-	if cfg.KeyFile != "" {
-		keys, getKeyErr = pathUtils.LoadGNS3KeysFile(cfg.KeyFile)
-		if getKeyErr != nil {
-			return "", errorUtils.WrapError(getKeyErr, "failed to load keys")
-		}
-	} else {
-		path, getPathErr := pathUtils.GetGNS3Dir()
-		if getPathErr != nil {
-			return "", errorUtils.WrapError(getPathErr, "failed to get the gns3 dir")
-		}
-		keys, getKeyErr = pathUtils.LoadGNS3KeysFile(filepath.Join(path, "gns3key"))
-		if getKeyErr != nil {
-			return "", errorUtils.WrapError(getKeyErr, "failed to load keys")
-		}
-	}
-	for _, key := range keys {
-		if key.ServerURL == cfg.Server {
-			return key.User, nil
-		}
+	key, ok := authentication.KeyForServer(cfg)
+	if !ok {
+		return "", fmt.Errorf("failed to get a matching entry in key file for the url %s", cfg.Server)
 	}
-
-	return "", fmt.Errorf("failed to get a matching entry in key file for the url %s", cfg.Server)
+	return key.User, nil
 }
@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/stefanistkuhl/gns3util/pkg/api"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/tidwall/gjson"
+)
+
+// DefaultWatchInterval is how often --watch polls when the notification
+// stream is unavailable.
+const DefaultWatchInterval = 2 * time.Second
+
+// AddWatchFlags adds --watch and --watch-interval to a list command.
+func AddWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("watch", "w", false, "Keep the list open and update it as the controller reports changes")
+	cmd.Flags().Duration("watch-interval", DefaultWatchInterval, "Poll interval of --watch when the notification stream is unavailable")
+}
+
+// WatchFlags returns whether --watch is set and its poll interval.
+func WatchFlags(cmd *cobra.Command) (bool, time.Duration) {
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("watch-interval")
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	return watch, interval
+}
+
+// Watch describes a list kept up to date by WatchList.
+type Watch struct {
+	// CmdName and Args fetch the list, as for ExecuteAndPrint.
+	CmdName string
+	Args    []string
+	// StreamURL is the notification endpoint whose events update the list.
+	StreamURL string
+	// ProjectID, if set, ignores events of other projects.
+	ProjectID string
+	Interval  time.Duration
+}
+
+// WatchList prints a list and updates it with the <resource>.created,
+// .updated, .deleted and project.opened/closed events of the notification
+// stream until ctx is cancelled. When the stream ends it reconnects and
+// fetches the list again; while the stream is unavailable the list is polled
+// every w.Interval instead.
+func WatchList(ctx context.Context, cfg config.GlobalOptions, w Watch) error {
+	resource := ResourceForCommand(w.CmdName)
+	idField := idElementName[resource][0]
+	if cfg.Output == "" {
+		cfg.Output = "table"
+	}
+
+	// The stream is opened first so no change between the fetch and the
+	// first event is lost.
+	stream, streamErr := openNotifications(ctx, cfg, w.StreamURL)
+	defer func() {
+		if stream != nil {
+			_ = stream.Close()
+		}
+	}()
+
+	body, _, err := CallClient(ctx, cfg, w.CmdName, w.Args, nil)
+	if err != nil {
+		return err
+	}
+	items, err := decodeItems(body)
+	if err != nil {
+		return err
+	}
+
+	tty := term.IsTerminal(os.Stdout.Fd())
+	rendered := false
+	render := func(body []byte, reason string) {
+		if tty {
+			fmt.Print("\033[H\033[2J")
+		} else if rendered {
+			fmt.Println()
+		}
+		rendered = true
+		fmt.Printf("%v %s\n\n", messageUtils.Bold(time.Now().Format(time.TimeOnly)), reason)
+		PrintOutput(cfg, resource, body)
+	}
+	refetch := func(reason string) {
+		next, _, err := CallClient(ctx, cfg, w.CmdName, w.Args, nil)
+		if err == nil && !bytes.Equal(next, body) {
+			var nextItems []map[string]any
+			if nextItems, err = decodeItems(next); err == nil {
+				body, items = next, nextItems
+				render(body, reason)
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "%v %v\n", messageUtils.WarningMsg("Warning"), err)
+		}
+	}
+	render(body, "Watching for changes, press Ctrl-C to stop")
+
+	polling := streamErr != nil
+	if polling {
+		fmt.Fprintf(os.Stderr, "%v notification stream unavailable (%v), polling every %s\n", messageUtils.WarningMsg("Warning"), streamErr, w.Interval)
+	}
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if stream != nil {
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+			for scanner.Scan() {
+				action, event, ok := watchedEvent(scanner.Bytes(), resource, w.ProjectID)
+				if !ok || !applyEvent(&items, idField, action, event) {
+					continue
+				}
+				if body, err = json.Marshal(items); err != nil {
+					return fmt.Errorf("failed to marshall the results: %w", err)
+				}
+				render(body, fmt.Sprintf("%s %s", messageUtils.Highlight(action), event.Get(nameField(resource, event)).String()))
+			}
+			_ = stream.Close()
+			stream = nil
+			if ctx.Err() != nil {
+				return nil
+			}
+			streamErr = scanner.Err()
+			if streamErr == nil {
+				streamErr = io.EOF
+			}
+			fmt.Fprintf(os.Stderr, "%v notification stream closed (%v), reconnecting\n", messageUtils.WarningMsg("Warning"), streamErr)
+			ticker.Reset(w.Interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// A stream the controller refuses will not come back, only errors
+		// of a failing or unreachable controller are retried.
+		if apiErr, ok := api.AsAPIError(streamErr); !ok || apiErr.StatusCode >= 500 {
+			if stream, streamErr = openNotifications(ctx, cfg, w.StreamURL); streamErr == nil {
+				polling = false
+				// Changes while the stream was down have no events.
+				refetch("Reconnected to the notification stream")
+				continue
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+		if !polling {
+			polling = true
+			fmt.Fprintf(os.Stderr, "%v notification stream unavailable (%v), polling every %s\n", messageUtils.WarningMsg("Warning"), streamErr, w.Interval)
+		}
+		refetch("Polled changes")
+	}
+}
+
+// watchedEvent returns the action and event of a notification line if it
+// is about resource and, with a projectID, not about another project.
+func watchedEvent(line []byte, resource, projectID string) (string, gjson.Result, bool) {
+	action := gjson.GetBytes(line, "action").String()
+	event := gjson.GetBytes(line, "event")
+	if !strings.HasPrefix(action, resource+".") || !event.IsObject() {
+		return "", gjson.Result{}, false
+	}
+	if projectID != "" && event.Get("project_id").Exists() && event.Get("project_id").String() != projectID {
+		return "", gjson.Result{}, false
+	}
+	return action, event, true
+}
+
+func openNotifications(ctx context.Context, cfg config.GlobalOptions, url string) (io.ReadCloser, error) {
+	token, err := authentication.GetKeyForServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	settings := config.APISettings(cfg, token)
+	// The stream stays open for as long as the watch runs.
+	settings.Timeout = 0
+	client := api.NewGNS3Client(settings)
+	reqOpts := api.NewRequestOptions(settings).
+		WithContext(ctx).
+		WithURL(url).
+		WithMethod(api.GET).
+		WithStream()
+	_, resp, err := client.Do(reqOpts)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errors.New("no response received")
+	}
+	return resp.Body, nil
+}
+
+func decodeItems(body []byte) ([]map[string]any, error) {
+	data, err := decodeJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := data.([]any)
+	if !ok {
+		return nil, errors.New("the response is not a list")
+	}
+	items := make([]map[string]any, 0, len(list))
+	for _, e := range list {
+		if item, ok := e.(map[string]any); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// applyEvent updates items with a notification event and reports whether
+// anything changed. Updates are merged into the known item, as some events
+// only carry the changed fields.
+func applyEvent(items *[]map[string]any, idField, action string, event gjson.Result) bool {
+	id := event.Get(idField).String()
+	if id == "" {
+		return false
+	}
+	index := -1
+	for i, item := range *items {
+		if fmt.Sprint(item[idField]) == id {
+			index = i
+			break
+		}
+	}
+
+	if strings.HasSuffix(action, ".deleted") {
+		if index < 0 {
+			return false
+		}
+		*items = append((*items)[:index], (*items)[index+1:]...)
+		return true
+	}
+	data, err := decodeJSON([]byte(event.Raw))
+	if err != nil {
+		return false
+	}
+	fields, ok := data.(map[string]any)
+	if !ok {
+		return false
+	}
+	if index < 0 {
+		*items = append(*items, fields)
+		return true
+	}
+	for k, v := range fields {
+		(*items)[index][k] = v
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/tidwall/gjson"
+)
+
+func TestDecodeItems(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    []map[string]any
+		wantErr bool
+	}{
+		{body: `[]`, want: []map[string]any{}},
+		{body: `[{"name":"a"},{"name":"b"}]`, want: []map[string]any{{"name": "a"}, {"name": "b"}}},
+		{body: `[{"name":"a"},"b",1]`, want: []map[string]any{{"name": "a"}}},
+		{body: `{"name":"a"}`, wantErr: true},
+		{body: `[{"name":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := decodeItems([]byte(tt.body))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeItems(%s) error = %v", tt.body, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decodeItems(%s) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestApplyEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		event       string
+		want        string
+		wantChanged bool
+	}{
+		{"created", "node.created", `{"node_id":"3","name":"R3"}`,
+			`[{"node_id":"1","name":"R1","status":"stopped"},{"node_id":"2","name":"R2"},{"node_id":"3","name":"R3"}]`, true},
+		{"update merges fields", "node.updated", `{"node_id":"1","status":"started"}`,
+			`[{"node_id":"1","name":"R1","status":"started"},{"node_id":"2","name":"R2"}]`, true},
+		{"update of an unknown item adds it", "node.updated", `{"node_id":"3","name":"R3"}`,
+			`[{"node_id":"1","name":"R1","status":"stopped"},{"node_id":"2","name":"R2"},{"node_id":"3","name":"R3"}]`, true},
+		{"deleted", "node.deleted", `{"node_id":"1"}`, `[{"node_id":"2","name":"R2"}]`, true},
+		{"deleted unknown", "node.deleted", `{"node_id":"9"}`,
+			`[{"node_id":"1","name":"R1","status":"stopped"},{"node_id":"2","name":"R2"}]`, false},
+		{"no id", "node.updated", `{"name":"R9"}`,
+			`[{"node_id":"1","name":"R1","status":"stopped"},{"node_id":"2","name":"R2"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := decodeItems([]byte(`[{"node_id":"1","name":"R1","status":"stopped"},{"node_id":"2","name":"R2"}]`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed := applyEvent(&items, "node_id", tt.action, gjson.Parse(tt.event)); changed != tt.wantChanged {
+				t.Errorf("applyEvent() = %v, want %v", changed, tt.wantChanged)
+			}
+			want, _ := decodeItems([]byte(tt.want))
+			if !reflect.DeepEqual(items, want) {
+				got, _ := json.Marshal(items)
+				t.Errorf("items = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchedEvent(t *testing.T) {
+	tests := []struct {
+		line      string
+		projectID string
+		want      bool
+	}{
+		{`{"action":"node.updated","event":{"node_id":"1","project_id":"p1"}}`, "", true},
+		{`{"action":"node.updated","event":{"node_id":"1","project_id":"p1"}}`, "p1", true},
+		{`{"action":"node.updated","event":{"node_id":"1","project_id":"p2"}}`, "p1", false},
+		{`{"action":"node.updated","event":{"node_id":"1"}}`, "p1", true},
+		{`{"action":"link.updated","event":{"link_id":"1"}}`, "", false},
+		{`{"action":"nodes.updated","event":{"node_id":"1"}}`, "", false},
+		{`{"action":"node.updated","event":"text"}`, "", false},
+		{`{"action":"ping","event":{"cpu_usage_percent":1}}`, "", false},
+		{`not json`, "", false},
+	}
+	for _, tt := range tests {
+		action, event, ok := watchedEvent([]byte(tt.line), "node", tt.projectID)
+		if ok != tt.want {
+			t.Errorf("watchedEvent(%s, %q) = %v, want %v", tt.line, tt.projectID, ok, tt.want)
+			continue
+		}
+		if ok && (action != "node.updated" || event.Get("node_id").String() != "1") {
+			t.Errorf("watchedEvent(%s) = %q, %s", tt.line, action, event.Raw)
+		}
+	}
+}
+
+func TestWatchListReconnects(t *testing.T) {
+	homedir.DisableCache = true
+	t.Setenv("HOME", t.TempDir())
+	fake := fakeserver.New(fakeserver.Options{})
+	var streams atomic.Int32
+	reconnected := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/notifications" {
+			fake.ServeHTTP(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		// The first stream ends at once, the second stays open.
+		if streams.Add(1) == 2 {
+			close(reconnected)
+			<-r.Context().Done()
+		}
+	}))
+	defer ts.Close()
+	cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+	if err := authentication.Login(context.Background(), cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-reconnected:
+		case <-time.After(5 * time.Second):
+		}
+		cancel()
+	}()
+	_, err := captureStdout(t, func() error {
+		return WatchList(ctx, cfg, Watch{CmdName: "getProjects", StreamURL: "/notifications", Interval: 10 * time.Millisecond})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := streams.Load(); n < 2 {
+		t.Errorf("the notification stream was opened %d times, want a reconnect", n)
+	}
+}
@@ -0,0 +1,307 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tidwall/gjson"
+)
+
+// A condition is a small expression over the resource a subcommand acts
+// on, e.g. "user.email.endswith('@example.com') and not user.is_superadmin".
+// Paths start with the subcommand name (acl_rule for acl-rule), values
+// can be compared with == != < > <= >= and tested with the startswith,
+// endswith, contains and matches methods. Terms combine with and, or, not
+// and parentheses.
+
+type token struct {
+	kind string // path, string, number, op, (, ), ","
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, token{kind: string(c), text: string(c)})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in condition %q", s)
+			}
+			tokens = append(tokens, token{kind: "string", text: s[i+1 : i+1+end]})
+			i += end + 2
+		case strings.ContainsRune("=!<>", rune(c)):
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("unknown operator %q in condition %q", op, s)
+			}
+			tokens = append(tokens, token{kind: "op", text: op})
+			i += len(op)
+		case c == '-' || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: "number", text: s[i:j]})
+			i = j
+		case unicode.IsLetter(rune(c)) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: "path", text: s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in condition %q", c, s)
+		}
+	}
+	return tokens, nil
+}
+
+type condParser struct {
+	tokens   []token
+	pos      int
+	resource string
+	obj      gjson.Result
+}
+
+// evalCondition evaluates cond against obj, the resource named resource.
+func evalCondition(cond, resource string, obj gjson.Result) (bool, error) {
+	tokens, err := tokenize(cond)
+	if err != nil {
+		return false, err
+	}
+	p := &condParser{tokens: tokens, resource: strings.ReplaceAll(resource, "-", "_"), obj: obj}
+	ok, err := p.or()
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", cond, err)
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("invalid condition %q: unexpected %q", cond, p.tokens[p.pos].text)
+	}
+	return ok, nil
+}
+
+func (p *condParser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{}
+}
+
+func (p *condParser) keyword(word string) bool {
+	if t := p.peek(); t.kind == "path" && t.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *condParser) or() (bool, error) {
+	left, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *condParser) and() (bool, error) {
+	left, err := p.not()
+	if err != nil {
+		return false, err
+	}
+	for p.keyword("and") {
+		right, err := p.not()
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *condParser) not() (bool, error) {
+	if p.keyword("not") {
+		v, err := p.not()
+		return !v, err
+	}
+	return p.term()
+}
+
+func (p *condParser) term() (bool, error) {
+	if p.peek().kind == "(" {
+		p.pos++
+		v, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if p.peek().kind != ")" {
+			return false, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	left, method, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	if method != "" {
+		return p.call(left, method)
+	}
+	if p.peek().kind != "op" {
+		return truthy(left), nil
+	}
+	op := p.peek().text
+	p.pos++
+	right, method, err := p.operand()
+	if err != nil {
+		return false, err
+	}
+	if method != "" {
+		return false, fmt.Errorf("method %s can not be compared", method)
+	}
+	c := compare(left, right)
+	switch op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case ">":
+		return c > 0, nil
+	case "<=":
+		return c <= 0, nil
+	}
+	return c >= 0, nil
+}
+
+var methods = map[string]bool{"startswith": true, "endswith": true, "contains": true, "matches": true}
+
+// operand returns the value of a literal or path. For a method call like
+// user.name.endswith(...) it returns the value of user.name and the
+// method, leaving the arguments to call.
+func (p *condParser) operand() (gjson.Result, string, error) {
+	t := p.peek()
+	p.pos++
+	switch t.kind {
+	case "string":
+		return gjson.Result{Type: gjson.String, Str: t.text}, "", nil
+	case "number":
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return gjson.Result{}, "", fmt.Errorf("invalid number %s", t.text)
+		}
+		return gjson.Result{Type: gjson.Number, Num: n, Raw: t.text}, "", nil
+	case "path":
+		switch t.text {
+		case "true":
+			return gjson.Result{Type: gjson.True}, "", nil
+		case "false":
+			return gjson.Result{Type: gjson.False}, "", nil
+		case "null":
+			return gjson.Result{Type: gjson.Null}, "", nil
+		}
+		path, method := t.text, ""
+		if dot := strings.LastIndexByte(path, '.'); dot >= 0 && p.peek().kind == "(" && methods[path[dot+1:]] {
+			path, method = path[:dot], path[dot+1:]
+		}
+		rest, ok := strings.CutPrefix(path, p.resource)
+		if !ok || (rest != "" && rest[0] != '.') {
+			return gjson.Result{}, "", fmt.Errorf("%s does not start with %s", path, p.resource)
+		}
+		if rest == "" {
+			return p.obj, method, nil
+		}
+		return p.obj.Get(rest[1:]), method, nil
+	case "":
+		return gjson.Result{}, "", fmt.Errorf("unexpected end")
+	}
+	return gjson.Result{}, "", fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *condParser) call(v gjson.Result, method string) (bool, error) {
+	if p.peek().kind != "(" {
+		return false, fmt.Errorf("%s needs an argument", method)
+	}
+	p.pos++
+	arg := p.peek()
+	if arg.kind != "string" && arg.kind != "number" {
+		return false, fmt.Errorf("%s needs a string argument", method)
+	}
+	p.pos++
+	if p.peek().kind != ")" {
+		return false, fmt.Errorf("%s takes one argument", method)
+	}
+	p.pos++
+	s := v.String()
+	switch method {
+	case "startswith":
+		return strings.HasPrefix(s, arg.text), nil
+	case "endswith":
+		return strings.HasSuffix(s, arg.text), nil
+	case "contains":
+		if v.IsArray() {
+			for _, e := range v.Array() {
+				if e.String() == arg.text {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(s, arg.text), nil
+	}
+	re, err := regexp.Compile(arg.text)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+func truthy(v gjson.Result) bool {
+	switch v.Type {
+	case gjson.Null, gjson.False:
+		return false
+	case gjson.String:
+		return v.Str != ""
+	case gjson.Number:
+		return v.Num != 0
+	}
+	return v.Exists()
+}
+
+func compare(a, b gjson.Result) int {
+	if a.Type == gjson.Number && b.Type == gjson.Number {
+		switch {
+		case a.Num < b.Num:
+			return -1
+		case a.Num > b.Num:
+			return 1
+		}
+		return 0
+	}
+	if (a.Type == gjson.Null || !a.Exists()) && b.Type == gjson.Null {
+		return 0
+	}
+	return strings.Compare(a.String(), b.String())
+}
@@ -0,0 +1,471 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/utils"
+	"github.com/stefanistkuhl/gns3util/pkg/utils/messageUtils"
+	"github.com/tidwall/gjson"
+)
+
+// operations maps a command and the resource of a subcommand to the
+// command of utils.CallClient that runs it.
+var operations = map[string]map[string]string{
+	"create": {
+		"user": "createUser", "group": "createGroup", "role": "createRole", "acl-rule": "createACL",
+		"project": "createProject", "template": "createTemplate", "compute": "createCompute", "pool": "createPool",
+	},
+	"update": {
+		"user": "updateUser", "group": "updateGroup", "role": "updateRole", "acl-rule": "updateACE",
+		"project": "updateProject", "template": "updateTemplate", "compute": "updateCompute", "pool": "updatePool",
+	},
+	"delete": {
+		"user": "deleteUser", "group": "deleteGroup", "role": "deleteRole", "acl-rule": "deleteACE",
+		"project": "deleteProject", "template": "deleteTemplate", "compute": "deleteCompute", "pool": "deletePool",
+	},
+	"open":  {"project": "openProject"},
+	"close": {"project": "closeProject"},
+	"start": {"project": "startAllNodes"},
+	"stop":  {"project": "stopAllNodes"},
+}
+
+// listCommands fetch the existing resources for filters and conditions.
+var listCommands = map[string]string{
+	"user": "getUsers", "group": "getGroups", "role": "getRoles", "acl-rule": "getAcl",
+	"project": "getProjects", "template": "getTemplates", "compute": "getComputes", "pool": "getPools",
+}
+
+// JobResult counts what the subcommands of a job did to resources.
+type JobResult struct {
+	Name    string
+	Done    int
+	Skipped int
+	Failed  int
+}
+
+type runner struct {
+	session  *utils.Session
+	options  Options
+	progress *progressBar
+}
+
+// Run runs the jobs of w in order and returns a result per job that ran.
+// Failed steps are reported and skipped unless exit_on_fail is set, then
+// the first failure ends the run with an error.
+func Run(ctx context.Context, cfg config.GlobalOptions, w *Workflow) ([]JobResult, error) {
+	session, err := utils.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := &runner{session: session, options: w.Options}
+	if w.Options.ProgressBar && term.IsTerminal(os.Stderr.Fd()) {
+		r.progress = &progressBar{total: w.Steps()}
+	}
+	defer r.progress.clear()
+
+	var results []JobResult
+	for _, job := range w.Jobs {
+		r.printf("%v %s\n", messageUtils.Bold("Job"), messageUtils.Highlight(job.Name))
+		result := JobResult{Name: job.Name}
+		err := r.runJob(ctx, job, w.Vars, &result)
+		results = append(results, result)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func (r *runner) runJob(ctx context.Context, job Job, vars map[string]string, result *JobResult) error {
+	for _, c := range job.Commands {
+		for i := 1; i <= max(c.Repeat, 1); i++ {
+			iterVars := iterationVars(vars, r.options.IterationsVarName, i)
+			for _, sub := range c.Subcommands {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				r.progress.step(job.Name)
+				err := r.runStep(ctx, c.Name, sub, iterVars, result)
+				if err == nil {
+					continue
+				}
+				var stop *stopError
+				if errors.As(err, &stop) {
+					return fmt.Errorf("job %s failed: %w", job.Name, stop.err)
+				}
+				result.Failed++
+				r.printf("  %v %s %s: %v\n", messageUtils.ErrorMsg("Failed"), c.Name, sub.Name, err)
+				if r.options.ExitOnFail || errors.Is(err, context.Canceled) {
+					return fmt.Errorf("job %s failed: %w", job.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// runStep runs one subcommand. Failed requests for existing resources are
+// counted and reported here, so a bulk step goes on with the others.
+func (r *runner) runStep(ctx context.Context, command string, sub Subcommand, vars map[string]string, result *JobResult) error {
+	expanded, err := expandValue(sub.Parameters, vars)
+	if err != nil {
+		return err
+	}
+	params, _ := expanded.(map[string]any)
+	if params == nil {
+		params = map[string]any{}
+	}
+	condition, err := expand(sub.Condition, vars)
+	if err != nil {
+		return err
+	}
+
+	cmdName := operations[command][sub.Name]
+	id, _ := params["id"].(string)
+	friendlyName, _ := params["friendly_name"].(string)
+	filterSpec := params["filter"]
+	all, _ := params[command+"_all"].(bool)
+	body := make(map[string]any, len(params))
+	for k, v := range params {
+		switch k {
+		case "id", "friendly_name", "filter", command + "_all":
+		default:
+			body[k] = v
+		}
+	}
+
+	if command == "create" {
+		obj, err := toResult(body)
+		if err != nil {
+			return err
+		}
+		if condition != "" {
+			ok, err := evalCondition(condition, sub.Name, obj)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				result.Skipped++
+				r.printf("  %v %s %s, the condition is false\n", messageUtils.WarningMsg("Skipped"), command, describe(sub.Name, obj))
+				return nil
+			}
+		}
+		var args []string
+		if sub.Name == "compute" {
+			args = []string{"false"}
+		}
+		if _, _, err := r.session.Call(ctx, cmdName, args, body); err != nil {
+			return err
+		}
+		result.Done++
+		r.printf("  %v %s %s\n", messageUtils.SuccessMsg("Done"), command, describe(sub.Name, obj))
+		return nil
+	}
+
+	targets, err := r.targets(ctx, sub.Name, id, friendlyName, filterSpec, condition != "")
+	if err != nil {
+		return err
+	}
+	if len(targets) > 1 && !all {
+		return fmt.Errorf("the filter matches %d %ss, set %s_all: true to %s all of them", len(targets), sub.Name, command, command)
+	}
+	if len(targets) == 0 {
+		r.printf("  %v %s %s, nothing matches the filter\n", messageUtils.InfoMsg("Nothing to do"), command, sub.Name)
+		return nil
+	}
+
+	idField, _, _ := utils.GetIDFieldMapping(sub.Name)
+	for _, obj := range targets {
+		if condition != "" {
+			ok, err := evalCondition(condition, sub.Name, obj)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				result.Skipped++
+				r.printf("  %v %s %s, the condition is false\n", messageUtils.WarningMsg("Skipped"), command, describe(sub.Name, obj))
+				continue
+			}
+		}
+		var reqBody any
+		if command == "update" {
+			reqBody = body
+		}
+		if _, _, err := r.session.Call(ctx, cmdName, []string{obj.Get(idField).String()}, reqBody); err != nil {
+			result.Failed++
+			r.printf("  %v %s %s: %v\n", messageUtils.ErrorMsg("Failed"), command, describe(sub.Name, obj), err)
+			if r.options.ExitOnFail || errors.Is(err, context.Canceled) {
+				return &stopError{err: err}
+			}
+			continue
+		}
+		result.Done++
+		r.printf("  %v %s %s\n", messageUtils.SuccessMsg("Done"), command, describe(sub.Name, obj))
+	}
+	return nil
+}
+
+// stopError ends the run after a failure that was already counted and
+// reported.
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string { return e.err.Error() }
+
+// targets returns the existing resources a subcommand selects by id,
+// friendly_name or filter. The objects of id and friendly_name are only
+// fetched if a condition needs them.
+func (r *runner) targets(ctx context.Context, resource, id, friendlyName string, filterSpec any, needObjects bool) ([]gjson.Result, error) {
+	idField, _, _ := utils.GetIDFieldMapping(resource)
+	if filterSpec == nil {
+		switch {
+		case id != "":
+		case friendlyName != "":
+			var err error
+			if id, err = r.session.ResolveID(ctx, resource, friendlyName, nil); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%s needs an id, friendly_name or filter parameter", resource)
+		}
+		if !needObjects {
+			obj, _ := toResult(map[string]any{idField: id, "name": friendlyName})
+			return []gjson.Result{obj}, nil
+		}
+	}
+
+	items, err := r.list(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	if filterSpec == nil {
+		for _, item := range items {
+			if item.Get(idField).String() == id {
+				return []gjson.Result{item}, nil
+			}
+		}
+		return nil, fmt.Errorf("%s %s does not exist", resource, id)
+	}
+
+	filters, err := parseFilters(resource, filterSpec)
+	if err != nil {
+		return nil, err
+	}
+	var matches []gjson.Result
+	for _, item := range items {
+		ok := true
+		for _, f := range filters {
+			if !f.match(item) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, item)
+		}
+	}
+	return matches, nil
+}
+
+func (r *runner) list(ctx context.Context, resource string) ([]gjson.Result, error) {
+	cmdName, ok := listCommands[resource]
+	if !ok {
+		return nil, fmt.Errorf("can not list %ss", resource)
+	}
+	body, _, err := r.session.Call(ctx, cmdName, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return gjson.ParseBytes(body).Array(), nil
+}
+
+// printf prints a line of the run above the progress bar.
+func (r *runner) printf(format string, a ...any) {
+	r.progress.clear()
+	fmt.Printf(format, a...)
+	r.progress.draw()
+}
+
+func toResult(v any) (gjson.Result, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("failed to encode parameters: %w", err)
+	}
+	return gjson.ParseBytes(b), nil
+}
+
+func describe(resource string, obj gjson.Result) string {
+	idField, nameField, _ := utils.GetIDFieldMapping(resource)
+	for _, field := range []string{nameField, "name", idField} {
+		if v := obj.Get(field).String(); v != "" {
+			return fmt.Sprintf("%s %s", resource, messageUtils.Bold(v))
+		}
+	}
+	return resource
+}
+
+// filter is a parameters.filter entry: field, operator and value.
+type filter struct {
+	field    string
+	operator string
+	values   []string
+	re       *regexp.Regexp
+}
+
+var filterOperators = []string{"eq", "ne", "in", "not_in", "contains", "startswith", "endswith", "matches", "lt", "gt"}
+
+// parseFilters accepts one filter or a list of them. Fields named
+// <resource>_name and <resource>_id refer to the name and id of the
+// resource.
+func parseFilters(resource string, spec any) ([]filter, error) {
+	var specs []any
+	switch spec := spec.(type) {
+	case []any:
+		specs = spec
+	default:
+		specs = []any{spec}
+	}
+	idField, nameField, _ := utils.GetIDFieldMapping(resource)
+	prefix := strings.ReplaceAll(resource, "-", "_")
+
+	filters := make([]filter, 0, len(specs))
+	for _, s := range specs {
+		m, ok := s.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("a filter needs field, operator and value")
+		}
+		f := filter{operator: "eq"}
+		f.field, _ = m["field"].(string)
+		if op, ok := m["operator"].(string); ok {
+			f.operator = op
+		}
+		switch f.field {
+		case "":
+			return nil, fmt.Errorf("a filter needs a field")
+		case prefix + "_name":
+			f.field = nameField
+		case prefix + "_id":
+			f.field = idField
+		}
+		switch v := m["value"].(type) {
+		case []any:
+			for _, e := range v {
+				f.values = append(f.values, fmt.Sprint(e))
+			}
+		case nil:
+			// Only an explicit empty list matches nothing, a forgotten
+			// value must not match empty fields instead.
+			return nil, fmt.Errorf("the %s filter on %s needs a value", f.operator, f.field)
+		default:
+			f.values = []string{fmt.Sprint(v)}
+		}
+		if f.operator == "in" || f.operator == "not_in" {
+			var values []string
+			for _, v := range f.values {
+				for _, part := range strings.Split(v, ",") {
+					values = append(values, strings.TrimSpace(part))
+				}
+			}
+			f.values = values
+		}
+		switch f.operator {
+		case "in", "not_in":
+		case "eq", "ne", "contains", "startswith", "endswith", "matches", "lt", "gt":
+			// Only in and not_in take a list, an empty one matches nothing.
+			if len(f.values) == 0 {
+				return nil, fmt.Errorf("the %s filter on %s needs a value", f.operator, f.field)
+			}
+		default:
+			return nil, fmt.Errorf("unknown filter operator %q, use one of %s", f.operator, strings.Join(filterOperators, ", "))
+		}
+		if f.operator == "matches" {
+			re, err := regexp.Compile(f.values[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter regex: %w", err)
+			}
+			f.re = re
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+func (f filter) match(item gjson.Result) bool {
+	v := item.Get(f.field)
+	s := v.String()
+	switch f.operator {
+	case "eq":
+		return s == f.values[0]
+	case "ne":
+		return s != f.values[0]
+	case "in", "not_in":
+		found := false
+		for _, value := range f.values {
+			if s == value {
+				found = true
+				break
+			}
+		}
+		return found == (f.operator == "in")
+	case "contains":
+		return strings.Contains(s, f.values[0])
+	case "startswith":
+		return strings.HasPrefix(s, f.values[0])
+	case "endswith":
+		return strings.HasSuffix(s, f.values[0])
+	case "matches":
+		return f.re.MatchString(s)
+	}
+	other := gjson.Parse(f.values[0])
+	c := compare(v, other)
+	if other.Type != gjson.Number {
+		c = strings.Compare(s, f.values[0])
+	}
+	if f.operator == "lt" {
+		return c < 0
+	}
+	return c > 0
+}
+
+// progressBar draws the progress of a run on the last line of stderr. A
+// nil bar draws nothing.
+type progressBar struct {
+	total int
+	done  int
+	job   string
+}
+
+const progressWidth = 30
+
+func (p *progressBar) step(job string) {
+	if p == nil {
+		return
+	}
+	p.done++
+	p.job = job
+	p.draw()
+}
+
+func (p *progressBar) draw() {
+	if p == nil || p.done == 0 {
+		return
+	}
+	filled := progressWidth * p.done / max(p.total, 1)
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %d/%d %s", strings.Repeat("#", filled), strings.Repeat(".", progressWidth-filled), p.done, p.total, p.job)
+}
+
+func (p *progressBar) clear() {
+	if p == nil || p.done == 0 {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
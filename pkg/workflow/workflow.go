@@ -0,0 +1,191 @@
+// Package workflow runs the YAML job files of gns3util run: variables,
+// options and jobs whose commands create, update or delete resources on the
+// controller.
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultIterationsVar is the variable that holds the repetition of a
+// command when the workflow does not name one.
+const DefaultIterationsVar = "i"
+
+type Workflow struct {
+	Vars    map[string]string `yaml:"vars"`
+	Options Options           `yaml:"options"`
+	Jobs    []Job             `yaml:"-"`
+}
+
+type Options struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	ProgressBar bool   `yaml:"progress_bar"`
+	ExitOnFail  bool   `yaml:"exit_on_fail"`
+	// IterationsVarName is set to 1..repeat while a command repeats.
+	IterationsVarName string `yaml:"iterations_var_name"`
+}
+
+type Job struct {
+	Name     string
+	Commands []Command `yaml:"commands"`
+}
+
+// Command is an action like create or delete, applied to each of its
+// subcommands Repeat times.
+type Command struct {
+	Name        string       `yaml:"name"`
+	Repeat      int          `yaml:"repeat"`
+	Subcommands []Subcommand `yaml:"subcommands"`
+}
+
+// Subcommand names the resource a command acts on. Parameters are the
+// request body plus the keys that select existing resources: id,
+// friendly_name, filter and <command>_all.
+type Subcommand struct {
+	Name       string         `yaml:"name"`
+	Parameters map[string]any `yaml:"parameters"`
+	Condition  string         `yaml:"condition"`
+}
+
+// Load reads and parses the workflow file at path.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses a workflow. Jobs are a list of single key maps, the key is
+// the job name.
+func Parse(data []byte) (*Workflow, error) {
+	var raw struct {
+		Workflow `yaml:",inline"`
+		Jobs     []map[string]Job `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	w := raw.Workflow
+	if w.Vars == nil {
+		w.Vars = make(map[string]string)
+	}
+	if w.Options.IterationsVarName == "" {
+		w.Options.IterationsVarName = DefaultIterationsVar
+	}
+	for i, entry := range raw.Jobs {
+		if len(entry) != 1 {
+			return nil, fmt.Errorf("invalid workflow: job %d must be a single name: {commands: ...} entry", i+1)
+		}
+		for name, job := range entry {
+			job.Name = name
+			w.Jobs = append(w.Jobs, job)
+		}
+	}
+	if len(w.Jobs) == 0 {
+		return nil, fmt.Errorf("invalid workflow: no jobs")
+	}
+	for _, job := range w.Jobs {
+		for _, c := range job.Commands {
+			if _, ok := operations[c.Name]; !ok {
+				return nil, fmt.Errorf("job %s: unknown command %q, use one of %s", job.Name, c.Name, strings.Join(commandNames(), ", "))
+			}
+			if c.Repeat < 0 {
+				return nil, fmt.Errorf("job %s: repeat of %s must not be negative", job.Name, c.Name)
+			}
+			for _, sub := range c.Subcommands {
+				if _, ok := operations[c.Name][sub.Name]; !ok {
+					return nil, fmt.Errorf("job %s: %s does not support %q", job.Name, c.Name, sub.Name)
+				}
+			}
+		}
+	}
+	return &w, nil
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Steps returns how many subcommand runs the workflow has, counting
+// repetitions.
+func (w *Workflow) Steps() int {
+	n := 0
+	for _, job := range w.Jobs {
+		for _, c := range job.Commands {
+			n += max(c.Repeat, 1) * len(c.Subcommands)
+		}
+	}
+	return n
+}
+
+var templateVar = regexp.MustCompile(`{{\s*([A-Za-z_][A-Za-z0-9_]*)\s*}}`)
+
+// expand replaces the {{var}} templates of s. Unknown variables are an
+// error so typos do not end up in resource names.
+func expand(s string, vars map[string]string) (string, error) {
+	var missing []string
+	out := templateVar.ReplaceAllStringFunc(s, func(m string) string {
+		name := templateVar.FindStringSubmatch(m)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined variable %s in %q", strings.Join(missing, ", "), s)
+	}
+	return out, nil
+}
+
+// expandValue expands the templates in every string of a parameter value.
+func expandValue(v any, vars map[string]string) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return expand(v, vars)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			x, err := expandValue(e, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = x
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			x, err := expandValue(e, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = x
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func iterationVars(vars map[string]string, name string, i int) map[string]string {
+	out := make(map[string]string, len(vars)+1)
+	for k, v := range vars {
+		out[k] = v
+	}
+	out[name] = strconv.Itoa(i)
+	return out
+}
@@ -0,0 +1,286 @@
+package workflow
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/stefanistkuhl/gns3util/pkg/authentication"
+	"github.com/stefanistkuhl/gns3util/pkg/config"
+	"github.com/stefanistkuhl/gns3util/pkg/fakeserver"
+	"github.com/stefanistkuhl/gns3util/pkg/sdk"
+	"github.com/tidwall/gjson"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"valid", "jobs:\n  - a:\n      commands:\n        - name: create\n          subcommands:\n            - name: user\n", ""},
+		{"no jobs", "vars: {}\n", "no jobs"},
+		{"two names", "jobs:\n  - a: {}\n    b: {}\n", "single name"},
+		{"unknown command", "jobs:\n  - a:\n      commands:\n        - name: rename\n", "unknown command"},
+		{"unsupported resource", "jobs:\n  - a:\n      commands:\n        - name: open\n          subcommands:\n            - name: user\n", "does not support"},
+		{"negative repeat", "jobs:\n  - a:\n      commands:\n        - name: create\n          repeat: -1\n", "negative"},
+		{"invalid yaml", "jobs: [", "invalid workflow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := Parse([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if w.Jobs[0].Name != "a" || w.Options.IterationsVarName != DefaultIterationsVar || w.Vars == nil {
+					t.Errorf("Parse() = %+v", w)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"i": "2", "DOMAIN": "lab.local"}
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"user{{i}}@{{ DOMAIN }}", "user2@lab.local", false},
+		{"plain", "plain", false},
+		{"{{missing}}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := expand(tt.in, vars)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("expand(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+
+	got, err := expandValue(map[string]any{"name": "g{{i}}", "tags": []any{"{{DOMAIN}}", 1}}, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := got.(map[string]any)
+	if m["name"] != "g2" || m["tags"].([]any)[0] != "lab.local" || m["tags"].([]any)[1] != 1 {
+		t.Errorf("expandValue() = %v", got)
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    any
+		field   string
+		values  []string
+		wantErr bool
+	}{
+		{"name alias", map[string]any{"field": "user_name", "value": "alice"}, "username", []string{"alice"}, false},
+		{"id alias", map[string]any{"field": "user_id", "operator": "ne", "value": "u1"}, "user_id", []string{"u1"}, false},
+		{"in splits", map[string]any{"field": "username", "operator": "in", "value": "a, b,c"}, "username", []string{"a", "b", "c"}, false},
+		{"in list", map[string]any{"field": "username", "operator": "not_in", "value": []any{"a", "b"}}, "username", []string{"a", "b"}, false},
+		{"in empty list", map[string]any{"field": "username", "operator": "in", "value": []any{}}, "username", nil, false},
+		{"missing value", map[string]any{"field": "username"}, "", nil, true},
+		{"null value", map[string]any{"field": "username", "operator": "ne", "value": nil}, "", nil, true},
+		{"in null", map[string]any{"field": "username", "operator": "in", "value": nil}, "", nil, true},
+		{"not_in missing value", map[string]any{"field": "username", "operator": "not_in"}, "", nil, true},
+		{"list of filters", []any{map[string]any{"field": "email", "operator": "endswith", "value": "@lab"}}, "email", []string{"@lab"}, false},
+		{"eq empty list", map[string]any{"field": "username", "value": []any{}}, "", nil, true},
+		{"lt empty list", map[string]any{"field": "username", "operator": "lt", "value": []any{}}, "", nil, true},
+		{"matches empty list", map[string]any{"field": "username", "operator": "matches", "value": []any{}}, "", nil, true},
+		{"invalid regex", map[string]any{"field": "username", "operator": "matches", "value": "["}, "", nil, true},
+		{"unknown operator", map[string]any{"field": "username", "operator": "like", "value": "a"}, "", nil, true},
+		{"no field", map[string]any{"value": "a"}, "", nil, true},
+		{"not a map", "username==a", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := parseFilters("user", tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilters() error = %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(filters) != 1 || filters[0].field != tt.field || !slices.Equal(filters[0].values, tt.values) {
+				t.Errorf("parseFilters() = %+v, want field %s values %q", filters, tt.field, tt.values)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	item := gjson.Parse(`{"username": "student2", "email": "s2@lab.local", "port": 5000}`)
+	tests := []struct {
+		operator string
+		value    any
+		want     bool
+	}{
+		{"eq", "student2", true},
+		{"ne", "student2", false},
+		{"in", "student1,student2", true},
+		{"in", []any{}, false},
+		{"not_in", "admin", true},
+		{"not_in", []any{}, true},
+		{"contains", "dent", true},
+		{"startswith", "stu", true},
+		{"endswith", "1", false},
+		{"matches", "^student[0-9]$", true},
+		{"lt", "student3", true},
+		{"gt", "student3", false},
+	}
+	for _, tt := range tests {
+		filters, err := parseFilters("user", map[string]any{"field": "username", "operator": tt.operator, "value": tt.value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := filters[0].match(item); got != tt.want {
+			t.Errorf("%s %v match = %v, want %v", tt.operator, tt.value, got, tt.want)
+		}
+	}
+
+	filters, _ := parseFilters("user", map[string]any{"field": "port", "operator": "gt", "value": 999})
+	if !filters[0].match(item) {
+		t.Error("port gt 999 compared as strings")
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	user := gjson.Parse(`{"username": "alice", "email": "alice@lab.local", "is_active": true}`)
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"user.email.endswith('@lab.local')", true},
+		{"user.username == 'bob'", false},
+		{"user.is_active and not user.username == 'bob'", true},
+		{"user.username == 'bob' or user.email.startswith('alice')", true},
+	}
+	for _, tt := range tests {
+		got, err := evalCondition(tt.cond, "user", user)
+		if err != nil || got != tt.want {
+			t.Errorf("evalCondition(%q) = %v, %v, want %v", tt.cond, got, err, tt.want)
+		}
+	}
+}
+
+const lifecycle = `
+vars:
+  DOMAIN: lab.local
+options:
+  exit_on_fail: %s
+jobs:
+  - create_students:
+      commands:
+        - name: create
+          repeat: 3
+          subcommands:
+            - name: user
+              parameters:
+                username: "student{{i}}"
+                email: "student{{i}}@{{DOMAIN}}"
+                password: "Password123!"
+  - rename_first:
+      commands:
+        - name: update
+          subcommands:
+            - name: user
+              parameters:
+                friendly_name: student1
+                full_name: First Student
+  - remove_missing:
+      commands:
+        - name: delete
+          subcommands:
+            - name: user
+              parameters:
+                friendly_name: nobody
+  - remove_students:
+      commands:
+        - name: delete
+          subcommands:
+            - name: user
+              parameters:
+                filter:
+                  field: username
+                  operator: startswith
+                  value: student
+                delete_all: true
+              condition: "user.email.endswith('@{{DOMAIN}}')"
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		exitOnFail string
+		results    []JobResult
+		wantErr    bool
+		users      []string
+	}{
+		{"false", []JobResult{
+			{Name: "create_students", Done: 3},
+			{Name: "rename_first", Done: 1},
+			{Name: "remove_missing", Failed: 1},
+			{Name: "remove_students", Done: 3},
+		}, false, []string{"admin"}},
+		{"true", []JobResult{
+			{Name: "create_students", Done: 3},
+			{Name: "rename_first", Done: 1},
+			{Name: "remove_missing", Failed: 1},
+		}, true, []string{"admin", "student1", "student2", "student3"}},
+	}
+	for _, tt := range tests {
+		t.Run("exit_on_fail "+tt.exitOnFail, func(t *testing.T) {
+			homedir.DisableCache = true
+			t.Setenv("HOME", t.TempDir())
+			ts := httptest.NewServer(fakeserver.New(fakeserver.Options{}))
+			defer ts.Close()
+			ctx := context.Background()
+			cfg := config.GlobalOptions{Server: ts.URL, KeyFile: filepath.Join(t.TempDir(), "gns3key")}
+			if err := authentication.Login(ctx, cfg, fakeserver.DefaultAdminUser, fakeserver.DefaultAdminPassword); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := Parse([]byte(strings.Replace(lifecycle, "%s", tt.exitOnFail, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := Run(ctx, cfg, w)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v", err)
+			}
+			if !slices.Equal(results, tt.results) {
+				t.Errorf("Run() = %+v, want %+v", results, tt.results)
+			}
+
+			c, err := sdk.NewFromConfig(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, err := c.Users().List(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, u := range users {
+				names = append(names, u.Username)
+				if u.Username == "student1" && (u.FullName == nil || *u.FullName != "First Student") {
+					t.Errorf("student1 full name = %v, want First Student", u.FullName)
+				}
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.users) {
+				t.Errorf("users after the run = %v, want %v", names, tt.users)
+			}
+		})
+	}
+}